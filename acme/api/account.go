@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"crypto"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -12,8 +14,13 @@ type AccountService service
 
 // New Creates a new account.
 func (a *AccountService) New(req acme.Account) (acme.ExtendedAccount, error) {
+	return a.NewWithContext(context.Background(), req)
+}
+
+// NewWithContext Creates a new account.
+func (a *AccountService) NewWithContext(ctx context.Context, req acme.Account) (acme.ExtendedAccount, error) {
 	var account acme.Account
-	resp, err := a.core.post(a.core.GetDirectory().NewAccountURL, req, &account)
+	resp, err := a.core.post(ctx, a.core.GetDirectory().NewAccountURL, req, &account)
 	location := getLocation(resp)
 
 	if location != "" {
@@ -29,6 +36,11 @@ func (a *AccountService) New(req acme.Account) (acme.ExtendedAccount, error) {
 
 // NewEAB Creates a new account with an External Account Binding.
 func (a *AccountService) NewEAB(accMsg acme.Account, kid, hmacEncoded string) (acme.ExtendedAccount, error) {
+	return a.NewEABWithContext(context.Background(), accMsg, kid, hmacEncoded)
+}
+
+// NewEABWithContext Creates a new account with an External Account Binding.
+func (a *AccountService) NewEABWithContext(ctx context.Context, accMsg acme.Account, kid, hmacEncoded string) (acme.ExtendedAccount, error) {
 	hmac, err := base64.RawURLEncoding.DecodeString(hmacEncoded)
 	if err != nil {
 		return acme.ExtendedAccount{}, fmt.Errorf("acme: could not decode hmac key: %w", err)
@@ -41,17 +53,22 @@ func (a *AccountService) NewEAB(accMsg acme.Account, kid, hmacEncoded string) (a
 
 	accMsg.ExternalAccountBinding = eabJWS
 
-	return a.New(accMsg)
+	return a.NewWithContext(ctx, accMsg)
 }
 
 // Get Retrieves an account.
 func (a *AccountService) Get(accountURL string) (acme.Account, error) {
+	return a.GetWithContext(context.Background(), accountURL)
+}
+
+// GetWithContext Retrieves an account.
+func (a *AccountService) GetWithContext(ctx context.Context, accountURL string) (acme.Account, error) {
 	if accountURL == "" {
 		return acme.Account{}, errors.New("account[get]: empty URL")
 	}
 
 	var account acme.Account
-	_, err := a.core.postAsGet(accountURL, &account)
+	_, err := a.core.postAsGet(ctx, accountURL, &account)
 	if err != nil {
 		return acme.Account{}, err
 	}
@@ -60,12 +77,17 @@ func (a *AccountService) Get(accountURL string) (acme.Account, error) {
 
 // Update Updates an account.
 func (a *AccountService) Update(accountURL string, req acme.Account) (acme.Account, error) {
+	return a.UpdateWithContext(context.Background(), accountURL, req)
+}
+
+// UpdateWithContext Updates an account.
+func (a *AccountService) UpdateWithContext(ctx context.Context, accountURL string, req acme.Account) (acme.Account, error) {
 	if accountURL == "" {
 		return acme.Account{}, errors.New("account[update]: empty URL")
 	}
 
 	var account acme.Account
-	_, err := a.core.post(accountURL, req, &account)
+	_, err := a.core.post(ctx, accountURL, req, &account)
 	if err != nil {
 		return acme.Account{}, err
 	}
@@ -75,11 +97,122 @@ func (a *AccountService) Update(accountURL string, req acme.Account) (acme.Accou
 
 // Deactivate Deactivates an account.
 func (a *AccountService) Deactivate(accountURL string) error {
+	return a.DeactivateWithContext(context.Background(), accountURL)
+}
+
+// DeactivateWithContext Deactivates an account.
+func (a *AccountService) DeactivateWithContext(ctx context.Context, accountURL string) error {
 	if accountURL == "" {
 		return errors.New("account[deactivate]: empty URL")
 	}
 
 	req := acme.Account{Status: acme.StatusDeactivated}
-	_, err := a.core.post(accountURL, req, nil)
+	_, err := a.core.post(ctx, accountURL, req, nil)
 	return err
 }
+
+// KeyChange Changes the key associated with the account (account key rollover).
+// On success, the new key is used to sign all the next requests.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.5
+func (a *AccountService) KeyChange(newKey crypto.PrivateKey) error {
+	return a.KeyChangeWithContext(context.Background(), newKey)
+}
+
+// KeyChangeWithContext Changes the key associated with the account (account key rollover).
+// On success, the new key is used to sign all the next requests.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.5
+func (a *AccountService) KeyChangeWithContext(ctx context.Context, newKey crypto.PrivateKey) error {
+	if newKey == nil {
+		return errors.New("account[keyChange]: the new key cannot be nil")
+	}
+
+	keyChangeURL := a.core.GetDirectory().KeyChangeURL
+	if keyChangeURL == "" {
+		return errors.New("account[keyChange]: server does not advertise a key change endpoint")
+	}
+
+	innerJWS, err := a.core.signKeyChangeContent(keyChangeURL, newKey)
+	if err != nil {
+		return fmt.Errorf("acme: error signing key change content: %w", err)
+	}
+
+	_, err = a.core.retrievablePost(ctx, a.core.jws, keyChangeURL, innerJWS, nil)
+	if err != nil {
+		return err
+	}
+
+	a.core.jws.SetPrivateKey(newKey)
+
+	return nil
+}
+
+// ListOrders Lists the URLs of the orders of an account.
+// The list is paginated by the server, all the pages are fetched.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.2.1
+func (a *AccountService) ListOrders(ordersURL string) ([]string, error) {
+	return a.ListOrdersWithContext(context.Background(), ordersURL)
+}
+
+// ListOrdersWithContext Lists the URLs of the orders of an account.
+// The list is paginated by the server, all the pages are fetched.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.2.1
+func (a *AccountService) ListOrdersWithContext(ctx context.Context, ordersURL string) ([]string, error) {
+	if ordersURL == "" {
+		return nil, errors.New("account[orders]: empty URL")
+	}
+
+	var orders []string
+
+	// Protects against a server returning a loop of pages.
+	seen := map[string]struct{}{}
+
+	for pageURL := ordersURL; pageURL != ""; {
+		if _, ok := seen[pageURL]; ok {
+			return nil, fmt.Errorf("account[orders]: pagination loop detected: %s", pageURL)
+		}
+
+		seen[pageURL] = struct{}{}
+
+		var list acme.OrdersList
+		resp, err := a.core.postAsGet(ctx, pageURL, &list)
+		if err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, list.Orders...)
+
+		pageURL = getLink(resp.Header, "next")
+	}
+
+	return orders, nil
+}
+
+// GetOrders Retrieves all the orders of an account.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.2.1
+func (a *AccountService) GetOrders(ordersURL string) ([]acme.ExtendedOrder, error) {
+	return a.GetOrdersWithContext(context.Background(), ordersURL)
+}
+
+// GetOrdersWithContext Retrieves all the orders of an account.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.2.1
+func (a *AccountService) GetOrdersWithContext(ctx context.Context, ordersURL string) ([]acme.ExtendedOrder, error) {
+	orderURLs, err := a.ListOrdersWithContext(ctx, ordersURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []acme.ExtendedOrder
+
+	for _, orderURL := range orderURLs {
+		order, err := a.core.Orders.GetWithContext(ctx, orderURL)
+		if err != nil {
+			return nil, err
+		}
+
+		order.Location = orderURL
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
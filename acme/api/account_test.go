@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountService_KeyChange(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	// small value keeps test fast
+	oldKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	newKey, errK := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, errK, "Could not generate test key")
+
+	const accountURL = "https://example.com/acme/acct/1"
+
+	var keyChange acme.KeyChangeMessage
+
+	mux.HandleFunc("/keyChange", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		// The outer JWS is signed by the old key.
+		body, err := readSignedBody(r, oldKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// The inner JWS is signed by the new key, and embeds it.
+		inner, err := jose.ParseSigned(string(body), []jose.SignatureAlgorithm{jose.ES256})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		jwk := inner.Signatures[0].Protected.JSONWebKey
+		if jwk == nil {
+			http.Error(w, "missing jwk", http.StatusBadRequest)
+			return
+		}
+
+		if inner.Signatures[0].Protected.Nonce != "" {
+			http.Error(w, "unexpected nonce", http.StatusBadRequest)
+			return
+		}
+
+		payload, err := inner.Verify(jwk)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = json.Unmarshal(payload, &keyChange)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", accountURL, oldKey)
+	require.NoError(t, err)
+
+	err = core.Accounts.KeyChange(newKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, accountURL, keyChange.Account)
+
+	var oldJWK jose.JSONWebKey
+	err = json.Unmarshal(keyChange.OldKey, &oldJWK)
+	require.NoError(t, err)
+
+	pub, ok := oldJWK.Key.(*rsa.PublicKey)
+	require.True(t, ok)
+	assert.True(t, oldKey.PublicKey.Equal(pub))
+
+	// The next requests must be signed by the new key.
+	signed, err := core.jws.SignContent(context.Background(), apiURL, []byte("{}"))
+	require.NoError(t, err)
+
+	_, err = signed.Verify(newKey.Public())
+	require.NoError(t, err)
+}
+
+func TestAccountService_KeyChange_error(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	// small value keeps test fast
+	oldKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	newKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	mux.HandleFunc("/keyChange", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_ = tester.WriteJSONResponse(w, acme.ProblemDetails{
+			Type:   "urn:ietf:params:acme:error:malformed",
+			Detail: "New key is already in use for a different account",
+		})
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "https://example.com/acme/acct/1", oldKey)
+	require.NoError(t, err)
+
+	err = core.Accounts.KeyChange(newKey)
+	require.Error(t, err)
+
+	var pd *acme.ProblemDetails
+	require.ErrorAs(t, err, &pd)
+	assert.Equal(t, http.StatusConflict, pd.HTTPStatus)
+
+	// The key must not have been changed.
+	signed, err := core.jws.SignContent(context.Background(), apiURL, []byte("{}"))
+	require.NoError(t, err)
+
+	_, err = signed.Verify(oldKey.Public())
+	require.NoError(t, err)
+}
+
+func TestAccountService_ListOrders(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	// small value keeps test fast
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	mux.HandleFunc("/orders/1", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Link", "<"+apiURL+`/orders/1/2>; rel="next"`)
+
+		_ = tester.WriteJSONResponse(w, acme.OrdersList{
+			Orders: []string{apiURL + "/order/a", apiURL + "/order/b"},
+		})
+	})
+
+	mux.HandleFunc("/orders/1/2", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.OrdersList{
+			Orders: []string{apiURL + "/order/c"},
+		})
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "https://example.com/acme/acct/1", privateKey)
+	require.NoError(t, err)
+
+	orders, err := core.Accounts.ListOrders(apiURL + "/orders/1")
+	require.NoError(t, err)
+
+	expected := []string{apiURL + "/order/a", apiURL + "/order/b", apiURL + "/order/c"}
+	assert.Equal(t, expected, orders)
+}
+
+func TestAccountService_ListOrders_loop(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	// small value keeps test fast
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	mux.HandleFunc("/orders/1", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Link", "<"+apiURL+`/orders/1>; rel="next"`)
+
+		_ = tester.WriteJSONResponse(w, acme.OrdersList{
+			Orders: []string{apiURL + "/order/a"},
+		})
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "https://example.com/acme/acct/1", privateKey)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.ListOrders(apiURL + "/orders/1")
+	require.EqualError(t, err, "account[orders]: pagination loop detected: "+apiURL+"/orders/1")
+}
+
+func TestAccountService_GetOrders(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	// small value keeps test fast
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	mux.HandleFunc("/orders/1", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.OrdersList{
+			Orders: []string{apiURL + "/order/a"},
+		})
+	})
+
+	mux.HandleFunc("/order/a", func(w http.ResponseWriter, _ *http.Request) {
+		_ = tester.WriteJSONResponse(w, acme.Order{
+			Status:      acme.StatusInvalid,
+			Identifiers: []acme.Identifier{{Type: "dns", Value: "example.com"}},
+			Error:       &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:unauthorized", Detail: "no TXT record found"},
+		})
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "https://example.com/acme/acct/1", privateKey)
+	require.NoError(t, err)
+
+	orders, err := core.Accounts.GetOrders(apiURL + "/orders/1")
+	require.NoError(t, err)
+
+	expected := []acme.ExtendedOrder{{
+		Order: acme.Order{
+			Status:      acme.StatusInvalid,
+			Identifiers: []acme.Identifier{{Type: "dns", Value: "example.com"}},
+			Error:       &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:unauthorized", Detail: "no TXT record found"},
+		},
+		Location: apiURL + "/order/a",
+	}}
+
+	assert.Equal(t, expected, orders)
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"encoding/json"
 	"errors"
@@ -23,6 +24,7 @@ type Core struct {
 	nonceManager *nonces.Manager
 	jws          *secure.JWS
 	directory    acme.Directory
+	retryPolicy  *RetryPolicy
 	HTTPClient   *http.Client
 
 	common         service // Reuse a single struct instead of allocating one for each service on the heap.
@@ -35,9 +37,15 @@ type Core struct {
 
 // New Creates a new Core.
 func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey crypto.PrivateKey) (*Core, error) {
+	return NewWithContext(context.Background(), httpClient, userAgent, caDirURL, kid, privateKey)
+}
+
+// NewWithContext Creates a new Core.
+// The context is only used to get the directory.
+func NewWithContext(ctx context.Context, httpClient *http.Client, userAgent, caDirURL, kid string, privateKey crypto.PrivateKey) (*Core, error) {
 	doer := sender.NewDoer(httpClient, userAgent)
 
-	dir, err := getDirectory(doer, caDirURL)
+	dir, err := getDirectory(ctx, doer, caDirURL)
 	if err != nil {
 		return nil, err
 	}
@@ -60,22 +68,60 @@ func New(httpClient *http.Client, userAgent, caDirURL, kid string, privateKey cr
 
 // post performs an HTTP POST request and parses the response body as JSON,
 // into the provided respBody object.
-func (a *Core) post(uri string, reqBody, response any) (*http.Response, error) {
+func (a *Core) post(ctx context.Context, uri string, reqBody, response any) (*http.Response, error) {
+	content, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, errors.New("failed to marshal message")
+	}
+
+	return a.retrievablePost(ctx, a.jws, uri, content, response)
+}
+
+// postWithKey performs an HTTP POST request signed by a private key other than the account key.
+// The public key is embedded in the JWS (jwk) instead of the account URL (kid).
+func (a *Core) postWithKey(ctx context.Context, privateKey crypto.PrivateKey, uri string, reqBody, response any) (*http.Response, error) {
 	content, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, errors.New("failed to marshal message")
 	}
 
-	return a.retrievablePost(uri, content, response)
+	return a.retrievablePost(ctx, secure.NewJWS(privateKey, "", a.nonceManager), uri, content, response)
 }
 
 // postAsGet performs an HTTP POST ("POST-as-GET") request.
 // https://www.rfc-editor.org/rfc/rfc8555.html#section-6.3
-func (a *Core) postAsGet(uri string, response any) (*http.Response, error) {
-	return a.retrievablePost(uri, []byte{}, response)
+func (a *Core) postAsGet(ctx context.Context, uri string, response any) (*http.Response, error) {
+	return a.retrievablePost(ctx, a.jws, uri, []byte{}, response)
+}
+
+// SetRetryPolicy defines the policy used to retry the requests rejected by a rate limit (opt-in).
+// By default, the requests are not retried.
+func (a *Core) SetRetryPolicy(policy *RetryPolicy) {
+	a.retryPolicy = policy
+}
+
+func (a *Core) retrievablePost(ctx context.Context, jws *secure.JWS, uri string, content []byte, response any) (*http.Response, error) {
+	for retries := 0; ; retries++ {
+		resp, err := a.noncePost(ctx, jws, uri, content, response)
+		if err == nil {
+			return resp, nil
+		}
+
+		delay, ok := a.retryPolicy.delay(err, retries)
+		if !ok {
+			return resp, err
+		}
+
+		log.Infof("retry in %s due to: %v", delay, err)
+
+		if errS := sleep(ctx, delay); errS != nil {
+			return resp, errors.Join(errS, err)
+		}
+	}
 }
 
-func (a *Core) retrievablePost(uri string, content []byte, response any) (*http.Response, error) {
+// noncePost performs a signed POST request, and retries it if the nonce was invalidated.
+func (a *Core) noncePost(ctx context.Context, jws *secure.JWS, uri string, content []byte, response any) (*http.Response, error) {
 	// during tests, allow to support ~90% of bad nonce with a minimum of attempts.
 	bo := backoff.NewExponentialBackOff()
 	bo.InitialInterval = 200 * time.Millisecond
@@ -85,7 +131,7 @@ func (a *Core) retrievablePost(uri string, content []byte, response any) (*http.
 	var resp *http.Response
 	operation := func() error {
 		var err error
-		resp, err = a.signedPost(uri, content, response)
+		resp, err = a.signedPost(ctx, jws, uri, content, response)
 		if err != nil {
 			// Retry if the nonce was invalidated
 			var e *acme.NonceError
@@ -103,7 +149,7 @@ func (a *Core) retrievablePost(uri string, content []byte, response any) (*http.
 		log.Infof("retry due to: %v", err)
 	}
 
-	err := backoff.RetryNotify(operation, bo, notify)
+	err := backoff.RetryNotify(operation, backoff.WithContext(bo, ctx), notify)
 	if err != nil {
 		return resp, err
 	}
@@ -111,15 +157,15 @@ func (a *Core) retrievablePost(uri string, content []byte, response any) (*http.
 	return resp, nil
 }
 
-func (a *Core) signedPost(uri string, content []byte, response any) (*http.Response, error) {
-	signedContent, err := a.jws.SignContent(uri, content)
+func (a *Core) signedPost(ctx context.Context, jws *secure.JWS, uri string, content []byte, response any) (*http.Response, error) {
+	signedContent, err := jws.SignContent(ctx, uri, content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to post JWS message: failed to sign content: %w", err)
 	}
 
 	signedBody := bytes.NewBufferString(signedContent.FullSerialize())
 
-	resp, err := a.doer.Post(uri, signedBody, "application/jose+json", response)
+	resp, err := a.doer.Post(ctx, uri, signedBody, "application/jose+json", response)
 
 	// nonceErr is ignored to keep the root error.
 	nonce, nonceErr := nonces.GetFromResponse(resp)
@@ -139,18 +185,33 @@ func (a *Core) signEABContent(newAccountURL, kid string, hmac []byte) ([]byte, e
 	return []byte(eabJWS.FullSerialize()), nil
 }
 
+func (a *Core) signKeyChangeContent(keyChangeURL string, newKey crypto.PrivateKey) ([]byte, error) {
+	innerJWS, err := a.jws.SignKeyChangeContent(keyChangeURL, newKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(innerJWS.FullSerialize()), nil
+}
+
 // GetKeyAuthorization Gets the key authorization.
 func (a *Core) GetKeyAuthorization(token string) (string, error) {
 	return a.jws.GetKeyAuthorization(token)
 }
 
+// GetAccountURL Gets the account URL (key identifier).
+// Empty if the account is not registered.
+func (a *Core) GetAccountURL() string {
+	return a.jws.GetKid()
+}
+
 func (a *Core) GetDirectory() acme.Directory {
 	return a.directory
 }
 
-func getDirectory(do *sender.Doer, caDirURL string) (acme.Directory, error) {
+func getDirectory(ctx context.Context, do *sender.Doer, caDirURL string) (acme.Directory, error) {
 	var dir acme.Directory
-	if _, err := do.Get(caDirURL, &dir); err != nil {
+	if _, err := do.Get(ctx, caDirURL, &dir); err != nil {
 		return dir, fmt.Errorf("get directory at '%s': %w", caDirURL, err)
 	}
 
@@ -1,21 +1,61 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/go-acme/lego/v4/acme"
 )
 
 type AuthorizationService service
 
+// New Creates a new authorization for a domain (pre-authorization).
+func (c *AuthorizationService) New(domain string) (acme.ExtendedAuthorization, error) {
+	return c.NewWithContext(context.Background(), domain)
+}
+
+// NewWithContext Creates a new authorization for a domain (pre-authorization).
+func (c *AuthorizationService) NewWithContext(ctx context.Context, domain string) (acme.ExtendedAuthorization, error) {
+	newAuthzURL := c.core.GetDirectory().NewAuthzURL
+	if newAuthzURL == "" {
+		return acme.ExtendedAuthorization{}, errors.New("authorization[new]: the server does not support pre-authorization")
+	}
+
+	// Pre-authorization cannot be used to authorize issuance of certificates containing wildcard domain names.
+	// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.4.1
+	if strings.HasPrefix(domain, "*.") {
+		return acme.ExtendedAuthorization{}, fmt.Errorf("authorization[new]: wildcard domains cannot be used as pre-authorization identifiers (RFC 8555 section 7.4.1), pre-authorize the base domain with a DNS challenge instead: %s", domain)
+	}
+
+	msg := acme.NewAuthzMessage{Identifier: newIdentifier(domain)}
+
+	var authz acme.Authorization
+	resp, err := c.core.post(ctx, newAuthzURL, msg, &authz)
+	if err != nil {
+		return acme.ExtendedAuthorization{}, err
+	}
+
+	return acme.ExtendedAuthorization{
+		Authorization: authz,
+		Location:      resp.Header.Get("Location"),
+	}, nil
+}
+
 // Get Gets an authorization.
 func (c *AuthorizationService) Get(authzURL string) (acme.Authorization, error) {
+	return c.GetWithContext(context.Background(), authzURL)
+}
+
+// GetWithContext Gets an authorization.
+func (c *AuthorizationService) GetWithContext(ctx context.Context, authzURL string) (acme.Authorization, error) {
 	if authzURL == "" {
 		return acme.Authorization{}, errors.New("authorization[get]: empty URL")
 	}
 
 	var authz acme.Authorization
-	_, err := c.core.postAsGet(authzURL, &authz)
+	_, err := c.core.postAsGet(ctx, authzURL, &authz)
 	if err != nil {
 		return acme.Authorization{}, err
 	}
@@ -24,11 +64,16 @@ func (c *AuthorizationService) Get(authzURL string) (acme.Authorization, error)
 
 // Deactivate Deactivates an authorization.
 func (c *AuthorizationService) Deactivate(authzURL string) error {
+	return c.DeactivateWithContext(context.Background(), authzURL)
+}
+
+// DeactivateWithContext Deactivates an authorization.
+func (c *AuthorizationService) DeactivateWithContext(ctx context.Context, authzURL string) error {
 	if authzURL == "" {
 		return errors.New("authorization[deactivate]: empty URL")
 	}
 
 	var disabledAuth acme.Authorization
-	_, err := c.core.post(authzURL, acme.Authorization{Status: acme.StatusDeactivated}, &disabledAuth)
+	_, err := c.core.post(ctx, authzURL, acme.Authorization{Status: acme.StatusDeactivated}, &disabledAuth)
 	return err
 }
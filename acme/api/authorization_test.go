@@ -0,0 +1,90 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizationService_New(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	// small value keeps test fast
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	mux.HandleFunc("/newAuthz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := readSignedBody(r, privateKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		msg := acme.NewAuthzMessage{}
+		err = json.Unmarshal(body, &msg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Location", apiURL+"/authz/1")
+		w.WriteHeader(http.StatusCreated)
+
+		err = tester.WriteJSONResponse(w, acme.Authorization{
+			Status:     acme.StatusPending,
+			Identifier: msg.Identifier,
+			Challenges: []acme.Challenge{
+				{Type: "dns-01", URL: apiURL + "/chlg/1", Status: acme.StatusPending, Token: "token"},
+			},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	authz, err := core.Authorizations.New("example.com")
+	require.NoError(t, err)
+
+	expected := acme.ExtendedAuthorization{
+		Authorization: acme.Authorization{
+			Status:     acme.StatusPending,
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+			Challenges: []acme.Challenge{
+				{Type: "dns-01", URL: apiURL + "/chlg/1", Status: acme.StatusPending, Token: "token"},
+			},
+		},
+		Location: apiURL + "/authz/1",
+	}
+
+	assert.Equal(t, expected, authz)
+}
+
+func TestAuthorizationService_New_wildcard(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	// small value keeps test fast
+	privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, errK, "Could not generate test key")
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	_, err = core.Authorizations.New("*.example.com")
+	require.EqualError(t, err, "authorization[new]: wildcard domains cannot be used as pre-authorization identifiers (RFC 8555 section 7.4.1), pre-authorize the base domain with a DNS challenge instead: *.example.com")
+}
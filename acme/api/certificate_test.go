@@ -1,13 +1,19 @@
 package api
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"encoding/json"
 	"encoding/pem"
+	"io"
 	"net/http"
 	"testing"
 
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/platform/tester"
+	jose "github.com/go-jose/go-jose/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -128,3 +134,56 @@ func TestCertificateService_Get_embeddedIssuer(t *testing.T) {
 	assert.Equal(t, certResponseMock, string(cert), "Certificate")
 	assert.Equal(t, issuerMock, string(issuer), "IssuerCertificate")
 }
+
+func TestCertificateService_RevokeWithKey(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	mux.HandleFunc("/revokeCert", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		jws, err := jose.ParseSigned(string(body), []jose.SignatureAlgorithm{jose.ES256})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		header := jws.Signatures[0].Protected
+		if header.KeyID != "" || header.JSONWebKey == nil {
+			http.Error(w, "the request must contain a jwk and no kid", http.StatusBadRequest)
+			return
+		}
+
+		payload, err := jws.Verify(&certKey.PublicKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var msg acme.RevokeCertMessage
+
+		err = json.Unmarshal(payload, &msg)
+		if err != nil || msg.Certificate != "cert" {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+	})
+
+	accountKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", apiURL+"/account/1", accountKey)
+	require.NoError(t, err)
+
+	err = core.Certificates.RevokeWithKey(acme.RevokeCertMessage{Certificate: "cert"}, certKey)
+	require.NoError(t, err)
+
+	// The account key is still used by the other requests.
+	assert.Equal(t, apiURL+"/account/1", core.GetAccountURL())
+}
@@ -2,11 +2,23 @@ package api
 
 import (
 	"cmp"
+	"net"
 	"slices"
 
 	"github.com/go-acme/lego/v4/acme"
 )
 
+// newIdentifier creates an [acme.Identifier] from a domain or an IP address.
+func newIdentifier(domain string) acme.Identifier {
+	ident := acme.Identifier{Value: domain, Type: "dns"}
+
+	if net.ParseIP(domain) != nil {
+		ident.Type = "ip"
+	}
+
+	return ident
+}
+
 // compareIdentifiers compares 2 slices of [acme.Identifier].
 func compareIdentifiers(a, b []acme.Identifier) int {
 	// Clones slices to avoid modifying original slices.
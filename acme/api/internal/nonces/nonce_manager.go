@@ -1,12 +1,14 @@
 package nonces
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"sync"
 
 	"github.com/go-acme/lego/v4/acme/api/internal/sender"
+	jose "github.com/go-jose/go-jose/v4"
 )
 
 // Manager Manages nonces.
@@ -48,14 +50,23 @@ func (n *Manager) Push(nonce string) {
 
 // Nonce implement jose.NonceSource.
 func (n *Manager) Nonce() (string, error) {
+	return n.nonce(context.Background())
+}
+
+// WithContext returns a jose.NonceSource which uses the context to get new nonces.
+func (n *Manager) WithContext(ctx context.Context) jose.NonceSource {
+	return &source{ctx: ctx, manager: n}
+}
+
+func (n *Manager) nonce(ctx context.Context) (string, error) {
 	if nonce, ok := n.Pop(); ok {
 		return nonce, nil
 	}
-	return n.getNonce()
+	return n.getNonce(ctx)
 }
 
-func (n *Manager) getNonce() (string, error) {
-	resp, err := n.do.Head(n.nonceURL)
+func (n *Manager) getNonce(ctx context.Context) (string, error) {
+	resp, err := n.do.Head(ctx, n.nonceURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to get nonce from HTTP HEAD: %w", err)
 	}
@@ -76,3 +87,14 @@ func GetFromResponse(resp *http.Response) (string, error) {
 
 	return nonce, nil
 }
+
+// source a context-aware jose.NonceSource.
+type source struct {
+	ctx     context.Context
+	manager *Manager
+}
+
+// Nonce implement jose.NonceSource.
+func (s *source) Nonce() (string, error) {
+	return s.manager.nonce(s.ctx)
+}
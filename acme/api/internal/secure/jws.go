@@ -1,13 +1,16 @@
 package secure
 
 import (
+	"context"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rsa"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api/internal/nonces"
 	jose "github.com/go-jose/go-jose/v4"
 )
@@ -33,27 +36,25 @@ func (j *JWS) SetKid(kid string) {
 	j.kid = kid
 }
 
-// SignContent Signs a content with the JWS.
-func (j *JWS) SignContent(url string, content []byte) (*jose.JSONWebSignature, error) {
-	var alg jose.SignatureAlgorithm
-	switch k := j.privKey.(type) {
-	case *rsa.PrivateKey:
-		alg = jose.RS256
-	case *ecdsa.PrivateKey:
-		if k.Curve == elliptic.P256() {
-			alg = jose.ES256
-		} else if k.Curve == elliptic.P384() {
-			alg = jose.ES384
-		}
-	}
+// GetKid Gets the key identifier.
+func (j *JWS) GetKid() string {
+	return j.kid
+}
+
+// SetPrivateKey Sets the private key used to sign the content.
+func (j *JWS) SetPrivateKey(privateKey crypto.PrivateKey) {
+	j.privKey = privateKey
+}
 
+// SignContent Signs a content with the JWS.
+func (j *JWS) SignContent(ctx context.Context, url string, content []byte) (*jose.JSONWebSignature, error) {
 	signKey := jose.SigningKey{
-		Algorithm: alg,
+		Algorithm: getSignatureAlgorithm(j.privKey),
 		Key:       jose.JSONWebKey{Key: j.privKey, KeyID: j.kid},
 	}
 
 	options := jose.SignerOptions{
-		NonceSource: j.nonces,
+		NonceSource: j.nonces.WithContext(ctx),
 		ExtraHeaders: map[jose.HeaderKey]any{
 			"url": url,
 		},
@@ -105,6 +106,47 @@ func (j *JWS) SignEABContent(url, kid string, hmac []byte) (*jose.JSONWebSignatu
 	return signed, nil
 }
 
+// SignKeyChangeContent Signs the inner JWS of a key change request with the new key.
+// The outer JWS must be signed with the current key (SignContent).
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.5
+func (j *JWS) SignKeyChangeContent(url string, newKey crypto.PrivateKey) (*jose.JSONWebSignature, error) {
+	oldJWK := jose.JSONWebKey{Key: j.privKey}
+	oldJWKJSON, err := oldJWK.Public().MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("acme: error encoding old jwk key: %w", err)
+	}
+
+	payload, err := json.Marshal(acme.KeyChangeMessage{
+		Account: j.kid,
+		OldKey:  oldJWKJSON,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("acme: error encoding key change payload: %w", err)
+	}
+
+	// The inner JWS MUST have a "jwk" header parameter containing the public key of the new key pair.
+	// The inner JWS MUST NOT have a "nonce" header parameter.
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: getSignatureAlgorithm(newKey), Key: newKey},
+		&jose.SignerOptions{
+			EmbedJWK: true,
+			ExtraHeaders: map[jose.HeaderKey]any{
+				"url": url,
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key change jose signer: %w", err)
+	}
+
+	signed, err := signer.Sign(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign key change content: %w", err)
+	}
+
+	return signed, nil
+}
+
 // GetKeyAuthorization Gets the key authorization for a token.
 func (j *JWS) GetKeyAuthorization(token string) (string, error) {
 	var publicKey crypto.PublicKey
@@ -128,3 +170,18 @@ func (j *JWS) GetKeyAuthorization(token string) (string, error) {
 
 	return token + "." + keyThumb, nil
 }
+
+func getSignatureAlgorithm(privateKey crypto.PrivateKey) jose.SignatureAlgorithm {
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return jose.RS256
+	case *ecdsa.PrivateKey:
+		if k.Curve == elliptic.P256() {
+			return jose.ES256
+		} else if k.Curve == elliptic.P384() {
+			return jose.ES384
+		}
+	}
+
+	return ""
+}
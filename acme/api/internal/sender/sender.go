@@ -1,12 +1,15 @@
 package sender
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-acme/lego/v4/acme"
 )
@@ -35,8 +38,8 @@ func NewDoer(client *http.Client, userAgent string) *Doer {
 
 // Get performs a GET request with a proper User-Agent string.
 // If "response" is not provided, callers should close resp.Body when done reading from it.
-func (d *Doer) Get(url string, response any) (*http.Response, error) {
-	req, err := d.newRequest(http.MethodGet, url, nil)
+func (d *Doer) Get(ctx context.Context, url string, response any) (*http.Response, error) {
+	req, err := d.newRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -46,8 +49,8 @@ func (d *Doer) Get(url string, response any) (*http.Response, error) {
 
 // Head performs a HEAD request with a proper User-Agent string.
 // The response body (resp.Body) is already closed when this function returns.
-func (d *Doer) Head(url string) (*http.Response, error) {
-	req, err := d.newRequest(http.MethodHead, url, nil)
+func (d *Doer) Head(ctx context.Context, url string) (*http.Response, error) {
+	req, err := d.newRequest(ctx, http.MethodHead, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -57,8 +60,8 @@ func (d *Doer) Head(url string) (*http.Response, error) {
 
 // Post performs a POST request with a proper User-Agent string.
 // If "response" is not provided, callers should close resp.Body when done reading from it.
-func (d *Doer) Post(url string, body io.Reader, bodyType string, response any) (*http.Response, error) {
-	req, err := d.newRequest(http.MethodPost, url, body, contentType(bodyType))
+func (d *Doer) Post(ctx context.Context, url string, body io.Reader, bodyType string, response any) (*http.Response, error) {
+	req, err := d.newRequest(ctx, http.MethodPost, url, body, contentType(bodyType))
 	if err != nil {
 		return nil, err
 	}
@@ -66,8 +69,8 @@ func (d *Doer) Post(url string, body io.Reader, bodyType string, response any) (
 	return d.do(req, response)
 }
 
-func (d *Doer) newRequest(method, uri string, body io.Reader, opts ...RequestOption) (*http.Request, error) {
-	req, err := http.NewRequest(method, uri, body)
+func (d *Doer) newRequest(ctx context.Context, method, uri string, body io.Reader, opts ...RequestOption) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, uri, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -127,7 +130,12 @@ func checkError(req *http.Request, resp *http.Response) error {
 		var errorDetails *acme.ProblemDetails
 		err = json.Unmarshal(body, &errorDetails)
 		if err != nil {
-			return fmt.Errorf("%d ::%s :: %s :: %w :: %s", resp.StatusCode, req.Method, req.URL, err, string(body))
+			if !isRetryableStatus(resp.StatusCode) {
+				return fmt.Errorf("%d ::%s :: %s :: %w :: %s", resp.StatusCode, req.Method, req.URL, err, string(body))
+			}
+
+			// Keep the Retry-After information even if the body is not a problem document (i.e. a load balancer response).
+			errorDetails = &acme.ProblemDetails{Detail: strings.TrimSpace(string(body))}
 		}
 
 		errorDetails.Method = req.Method
@@ -137,6 +145,10 @@ func checkError(req *http.Request, resp *http.Response) error {
 			errorDetails.HTTPStatus = resp.StatusCode
 		}
 
+		if isRetryableStatus(resp.StatusCode) || errorDetails.Type == acme.RateLimitedErr {
+			errorDetails.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+
 		// Check for errors we handle specifically
 		if errorDetails.HTTPStatus == http.StatusBadRequest && errorDetails.Type == acme.BadNonceErr {
 			return &acme.NonceError{ProblemDetails: errorDetails}
@@ -146,7 +158,43 @@ func checkError(req *http.Request, resp *http.Response) error {
 			return &acme.AlreadyReplacedError{ProblemDetails: errorDetails}
 		}
 
-		return errorDetails
+		switch errorDetails.Type {
+		case acme.BadNonceErr, acme.AlreadyReplacedErr:
+			// Only handled with the expected HTTP status.
+			return errorDetails
+		default:
+			return acme.WrapProblem(errorDetails)
+		}
 	}
 	return nil
 }
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+// parseRetryAfter parses the value of the Retry-After header.
+// The value can be a number of seconds or an HTTP-date.
+// https://www.rfc-editor.org/rfc/rfc9110.html#section-10.2.3
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0
+	}
+
+	return max(date.Sub(now), 0)
+}
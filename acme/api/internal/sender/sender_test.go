@@ -1,11 +1,15 @@
 package sender
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -27,17 +31,19 @@ func TestDo_UserAgentOnAllHTTPMethod(t *testing.T) {
 		{
 			method: http.MethodGet,
 			call: func(u string) (*http.Response, error) {
-				return doer.Get(u, nil)
+				return doer.Get(context.Background(), u, nil)
 			},
 		},
 		{
 			method: http.MethodHead,
-			call:   doer.Head,
+			call: func(u string) (*http.Response, error) {
+				return doer.Head(context.Background(), u)
+			},
 		},
 		{
 			method: http.MethodPost,
 			call: func(u string) (*http.Response, error) {
-				return doer.Post(u, strings.NewReader("falalalala"), "text/plain", nil)
+				return doer.Post(context.Background(), u, strings.NewReader("falalalala"), "text/plain", nil)
 			},
 		},
 	}
@@ -65,3 +71,134 @@ func TestDo_CustomUserAgent(t *testing.T) {
 	}
 	assert.Len(t, strings.Split(ua, " "), 5)
 }
+
+func TestDo_typedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusForbidden)
+
+		_ = json.NewEncoder(w).Encode(acme.ProblemDetails{
+			Type:   acme.CompoundErr,
+			Detail: "Error creating new order",
+			SubProblems: []acme.SubProblem{
+				{
+					Type:       acme.CAAErr,
+					Detail:     "CAA record for example.com prevents issuance",
+					Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+				},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	doer := NewDoer(http.DefaultClient, "")
+
+	_, err := doer.Get(context.Background(), server.URL, nil)
+	require.Error(t, err)
+
+	var compoundErr *acme.CompoundError
+	require.ErrorAs(t, err, &compoundErr)
+
+	var pd *acme.ProblemDetails
+	require.ErrorAs(t, err, &pd)
+	assert.Equal(t, http.StatusForbidden, pd.HTTPStatus)
+
+	subs := compoundErr.SubProblemsFor("example.com")
+	require.Len(t, subs, 1)
+
+	var caaErr *acme.CAAError
+	require.ErrorAs(t, subs[0].Err(), &caaErr)
+}
+
+func TestDo_rateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+
+		_ = json.NewEncoder(w).Encode(acme.ProblemDetails{
+			Type:   acme.RateLimitedErr,
+			Detail: "too many certificates already issued",
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	doer := NewDoer(http.DefaultClient, "")
+
+	_, err := doer.Get(context.Background(), server.URL, nil)
+	require.Error(t, err)
+
+	var rateLimitedErr *acme.RateLimitedError
+	require.ErrorAs(t, err, &rateLimitedErr)
+	assert.Equal(t, 120*time.Second, rateLimitedErr.RetryAfter)
+}
+
+func TestDo_serviceUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		_, _ = w.Write([]byte("maintenance"))
+	}))
+	t.Cleanup(server.Close)
+
+	doer := NewDoer(http.DefaultClient, "")
+
+	_, err := doer.Get(context.Background(), server.URL, nil)
+	require.Error(t, err)
+
+	var pd *acme.ProblemDetails
+	require.ErrorAs(t, err, &pd)
+	assert.Equal(t, http.StatusServiceUnavailable, pd.HTTPStatus)
+	assert.Equal(t, "maintenance", pd.Detail)
+	assert.Equal(t, 30*time.Second, pd.RetryAfter)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		value    string
+		expected time.Duration
+	}{
+		{
+			desc:     "empty",
+			value:    "",
+			expected: 0,
+		},
+		{
+			desc:     "seconds",
+			value:    "90",
+			expected: 90 * time.Second,
+		},
+		{
+			desc:     "negative seconds",
+			value:    "-1",
+			expected: 0,
+		},
+		{
+			desc:     "HTTP-date",
+			value:    "Wed, 01 Jan 2025 12:10:00 GMT",
+			expected: 10 * time.Minute,
+		},
+		{
+			desc:     "HTTP-date in the past",
+			value:    "Wed, 01 Jan 2025 11:00:00 GMT",
+			expected: 0,
+		},
+		{
+			desc:     "invalid",
+			value:    "soon",
+			expected: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, parseRetryAfter(test.value, now))
+		})
+	}
+}
@@ -1,10 +1,10 @@
 package api
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"net"
 	"slices"
 	"time"
 
@@ -36,15 +36,14 @@ func (o *OrderService) New(domains []string) (acme.ExtendedOrder, error) {
 
 // NewWithOptions Creates a new order.
 func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acme.ExtendedOrder, error) {
+	return o.NewWithOptionsAndContext(context.Background(), domains, opts)
+}
+
+// NewWithOptionsAndContext Creates a new order.
+func (o *OrderService) NewWithOptionsAndContext(ctx context.Context, domains []string, opts *OrderOptions) (acme.ExtendedOrder, error) {
 	var identifiers []acme.Identifier
 	for _, domain := range domains {
-		ident := acme.Identifier{Value: domain, Type: "dns"}
-
-		if net.ParseIP(domain) != nil {
-			ident.Type = "ip"
-		}
-
-		identifiers = append(identifiers, ident)
+		identifiers = append(identifiers, newIdentifier(domain))
 	}
 
 	orderReq := acme.Order{Identifiers: identifiers}
@@ -68,7 +67,7 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 	}
 
 	var order acme.Order
-	resp, err := o.core.post(o.core.GetDirectory().NewOrderURL, orderReq, &order)
+	resp, err := o.core.post(ctx, o.core.GetDirectory().NewOrderURL, orderReq, &order)
 	if err != nil {
 		are := &acme.AlreadyReplacedError{}
 		if !errors.As(err, &are) {
@@ -80,7 +79,7 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 		// https://www.rfc-editor.org/rfc/rfc9773.html#section-5
 		orderReq.Replaces = ""
 
-		resp, err = o.core.post(o.core.GetDirectory().NewOrderURL, orderReq, &order)
+		resp, err = o.core.post(ctx, o.core.GetDirectory().NewOrderURL, orderReq, &order)
 		if err != nil {
 			return acme.ExtendedOrder{}, err
 		}
@@ -109,12 +108,17 @@ func (o *OrderService) NewWithOptions(domains []string, opts *OrderOptions) (acm
 
 // Get Gets an order.
 func (o *OrderService) Get(orderURL string) (acme.ExtendedOrder, error) {
+	return o.GetWithContext(context.Background(), orderURL)
+}
+
+// GetWithContext Gets an order.
+func (o *OrderService) GetWithContext(ctx context.Context, orderURL string) (acme.ExtendedOrder, error) {
 	if orderURL == "" {
 		return acme.ExtendedOrder{}, errors.New("order[get]: empty URL")
 	}
 
 	var order acme.Order
-	_, err := o.core.postAsGet(orderURL, &order)
+	_, err := o.core.postAsGet(ctx, orderURL, &order)
 	if err != nil {
 		return acme.ExtendedOrder{}, err
 	}
@@ -124,12 +128,17 @@ func (o *OrderService) Get(orderURL string) (acme.ExtendedOrder, error) {
 
 // UpdateForCSR Updates an order for a CSR.
 func (o *OrderService) UpdateForCSR(orderURL string, csr []byte) (acme.ExtendedOrder, error) {
+	return o.UpdateForCSRWithContext(context.Background(), orderURL, csr)
+}
+
+// UpdateForCSRWithContext Updates an order for a CSR.
+func (o *OrderService) UpdateForCSRWithContext(ctx context.Context, orderURL string, csr []byte) (acme.ExtendedOrder, error) {
 	csrMsg := acme.CSRMessage{
 		Csr: base64.RawURLEncoding.EncodeToString(csr),
 	}
 
 	var order acme.Order
-	_, err := o.core.post(orderURL, csrMsg, &order)
+	_, err := o.core.post(ctx, orderURL, csrMsg, &order)
 	if err != nil {
 		return acme.ExtendedOrder{}, err
 	}
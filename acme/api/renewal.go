@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 )
 
@@ -16,6 +18,12 @@ var ErrNoARI = errors.New("renewalInfo[get/post]: server does not advertise a re
 //
 // https://www.rfc-editor.org/rfc/rfc9773.html
 func (c *CertificateService) GetRenewalInfo(certID string) (*http.Response, error) {
+	return c.GetRenewalInfoWithContext(context.Background(), certID)
+}
+
+// GetRenewalInfoWithContext GETs renewal information for a certificate from the renewalInfo endpoint.
+// This method will return api.ErrNoARI if the server does not advertise a renewal info endpoint.
+func (c *CertificateService) GetRenewalInfoWithContext(ctx context.Context, certID string) (*http.Response, error) {
 	if c.core.GetDirectory().RenewalInfo == "" {
 		return nil, ErrNoARI
 	}
@@ -24,5 +32,10 @@ func (c *CertificateService) GetRenewalInfo(certID string) (*http.Response, erro
 		return nil, errors.New("renewalInfo[get]: 'certID' cannot be empty")
 	}
 
-	return c.core.HTTPClient.Get(c.core.GetDirectory().RenewalInfo + "/" + certID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.core.GetDirectory().RenewalInfo+"/"+certID, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("renewalInfo[get]: %w", err)
+	}
+
+	return c.core.HTTPClient.Do(req)
 }
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+// RetryPolicy defines how the requests rejected by a rate limit, or because the server is unavailable, are retried.
+// A request is retried only if the server provides a Retry-After header.
+type RetryPolicy struct {
+	// MaxWait the maximum duration to wait before retrying a request.
+	// The request is not retried if the server asks to wait longer.
+	MaxWait time.Duration
+
+	// MaxRetries the maximum number of retries for a request.
+	MaxRetries int
+}
+
+// delay returns the duration to wait before retrying the request,
+// or false if the request must not be retried.
+func (p *RetryPolicy) delay(err error, retries int) (time.Duration, bool) {
+	if p == nil || retries >= p.MaxRetries {
+		return 0, false
+	}
+
+	retryAfter, ok := GetRetryAfter(err)
+	if !ok || retryAfter > p.MaxWait {
+		return 0, false
+	}
+
+	return retryAfter, true
+}
+
+// GetRetryAfter returns the duration to wait, provided by the server (Retry-After header),
+// when a request has been rejected by a rate limit or because the server is unavailable.
+func GetRetryAfter(err error) (time.Duration, bool) {
+	var pd *acme.ProblemDetails
+	if !errors.As(err, &pd) {
+		return 0, false
+	}
+
+	return pd.RetryAfter, pd.RetryAfter > 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
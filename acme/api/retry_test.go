@@ -0,0 +1,107 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_retryPolicy(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		policy     *RetryPolicy
+		retryAfter string
+		expected   int32
+		requireErr require.ErrorAssertionFunc
+	}{
+		{
+			desc:       "no policy",
+			retryAfter: "1",
+			expected:   1,
+			requireErr: require.Error,
+		},
+		{
+			desc:       "retry",
+			policy:     &RetryPolicy{MaxWait: 5 * time.Second, MaxRetries: 3},
+			retryAfter: "1",
+			expected:   2,
+			requireErr: require.NoError,
+		},
+		{
+			desc:       "wait too long",
+			policy:     &RetryPolicy{MaxWait: 5 * time.Second, MaxRetries: 3},
+			retryAfter: "3600",
+			expected:   1,
+			requireErr: require.Error,
+		},
+		{
+			desc:       "no Retry-After",
+			policy:     &RetryPolicy{MaxWait: 5 * time.Second, MaxRetries: 3},
+			retryAfter: "",
+			expected:   1,
+			requireErr: require.Error,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mux, apiURL := tester.SetupFakeAPI(t)
+
+			// small value keeps test fast
+			privateKey, errK := rsa.GenerateKey(rand.Reader, 1024)
+			require.NoError(t, errK, "Could not generate test key")
+
+			var calls atomic.Int32
+
+			mux.HandleFunc("/order/1", func(w http.ResponseWriter, _ *http.Request) {
+				if calls.Add(1) == 1 {
+					w.Header().Set("Retry-After", test.retryAfter)
+					w.WriteHeader(http.StatusTooManyRequests)
+					_ = tester.WriteJSONResponse(w, acme.ProblemDetails{
+						Type:   acme.RateLimitedErr,
+						Detail: "too many requests",
+					})
+
+					return
+				}
+
+				_ = tester.WriteJSONResponse(w, acme.Order{Status: acme.StatusValid})
+			})
+
+			core, err := New(http.DefaultClient, "lego-test", apiURL+"/dir", "https://example.com/acme/acct/1", privateKey)
+			require.NoError(t, err)
+
+			core.SetRetryPolicy(test.policy)
+
+			_, err = core.Orders.Get(apiURL + "/order/1")
+			test.requireErr(t, err)
+
+			assert.Equal(t, test.expected, calls.Load())
+		})
+	}
+}
+
+func TestGetRetryAfter(t *testing.T) {
+	err := &acme.RateLimitedError{ProblemDetails: &acme.ProblemDetails{
+		Type:       acme.RateLimitedErr,
+		HTTPStatus: http.StatusTooManyRequests,
+		RetryAfter: time.Hour,
+	}}
+
+	retryAfter, ok := GetRetryAfter(err)
+	require.True(t, ok)
+	assert.Equal(t, time.Hour, retryAfter)
+
+	_, ok = GetRetryAfter(&acme.ProblemDetails{Type: acme.MalformedErr})
+	assert.False(t, ok)
+}
@@ -127,6 +127,14 @@ type Account struct {
 	ExternalAccountBinding json.RawMessage `json:"externalAccountBinding,omitempty"`
 }
 
+// OrdersList the list of orders of an account.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.2.1
+type OrdersList struct {
+	// orders (required, array of string):
+	// An array of URLs, each identifying an order belonging to the account.
+	Orders []string `json:"orders"`
+}
+
 // ExtendedOrder a extended Order.
 type ExtendedOrder struct {
 	Order
@@ -202,12 +210,20 @@ type Order struct {
 
 func (r *Order) Err() error {
 	if r.Error != nil {
-		return r.Error
+		return WrapProblem(r.Error)
 	}
 
 	return nil
 }
 
+// ExtendedAuthorization a extended Authorization.
+type ExtendedAuthorization struct {
+	Authorization
+
+	// The authorization URL, contains the value of the response header `Location`
+	Location string `json:"-"`
+}
+
 // Authorization the ACME authorization object.
 // - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.1.4
 type Authorization struct {
@@ -241,6 +257,14 @@ type Authorization struct {
 	Wildcard bool `json:"wildcard,omitempty"`
 }
 
+// NewAuthzMessage the payload of a pre-authorization request.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.4.1
+type NewAuthzMessage struct {
+	// identifier (required, object):
+	// The identifier that the account wants to be authorized to represent.
+	Identifier Identifier `json:"identifier"`
+}
+
 // ExtendedChallenge a extended Challenge.
 type ExtendedChallenge struct {
 	Challenge
@@ -291,11 +315,16 @@ type Challenge struct {
 
 	// https://www.rfc-editor.org/rfc/rfc8555.html#section-8.1
 	KeyAuthorization string `json:"keyAuthorization"`
+
+	// issuer-domain-names (required for dns-persist-01, array of string):
+	// The issuer domain names accepted by the CA in the persistent validation record.
+	// https://datatracker.ietf.org/doc/draft-ietf-acme-dns-persist/
+	IssuerDomainNames []string `json:"issuer-domain-names,omitempty"`
 }
 
 func (c *Challenge) Err() error {
 	if c.Error != nil {
-		return c.Error
+		return WrapProblem(c.Error)
 	}
 
 	return nil
@@ -336,6 +365,20 @@ type RevokeCertMessage struct {
 	Reason *uint `json:"reason,omitempty"`
 }
 
+// KeyChangeMessage the payload of the inner JWS of a key change request.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-7.3.5
+type KeyChangeMessage struct {
+	// account (required, string):
+	// The URL for the account being modified.
+	// The content of this field MUST be the exact string provided in the Location header field
+	// in response to the newAccount request that created the account.
+	Account string `json:"account"`
+
+	// oldKey (required, JWK):
+	// The JWK representation of the old key.
+	OldKey json.RawMessage `json:"oldKey"`
+}
+
 // RawCertificate raw data of a certificate.
 type RawCertificate struct {
 	Cert   []byte
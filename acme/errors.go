@@ -2,13 +2,40 @@ package acme
 
 import (
 	"fmt"
+	"time"
 )
 
 // Errors types.
+// - https://www.rfc-editor.org/rfc/rfc8555.html#section-6.7
+// - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
 const (
-	errNS              = "urn:ietf:params:acme:error:"
-	BadNonceErr        = errNS + "badNonce"
-	AlreadyReplacedErr = errNS + "alreadyReplaced"
+	errNS = "urn:ietf:params:acme:error:"
+
+	AccountDoesNotExistErr     = errNS + "accountDoesNotExist"
+	AlreadyReplacedErr         = errNS + "alreadyReplaced"
+	AlreadyRevokedErr          = errNS + "alreadyRevoked"
+	BadCSRErr                  = errNS + "badCSR"
+	BadNonceErr                = errNS + "badNonce"
+	BadPublicKeyErr            = errNS + "badPublicKey"
+	BadRevocationReasonErr     = errNS + "badRevocationReason"
+	BadSignatureAlgorithmErr   = errNS + "badSignatureAlgorithm"
+	CAAErr                     = errNS + "caa"
+	CompoundErr                = errNS + "compound"
+	ConnectionErr              = errNS + "connection"
+	DNSErr                     = errNS + "dns"
+	ExternalAccountRequiredErr = errNS + "externalAccountRequired"
+	IncorrectResponseErr       = errNS + "incorrectResponse"
+	InvalidContactErr          = errNS + "invalidContact"
+	MalformedErr               = errNS + "malformed"
+	OrderNotReadyErr           = errNS + "orderNotReady"
+	RateLimitedErr             = errNS + "rateLimited"
+	RejectedIdentifierErr      = errNS + "rejectedIdentifier"
+	ServerInternalErr          = errNS + "serverInternal"
+	TLSErr                     = errNS + "tls"
+	UnauthorizedErr            = errNS + "unauthorized"
+	UnsupportedContactErr      = errNS + "unsupportedContact"
+	UnsupportedIdentifierErr   = errNS + "unsupportedIdentifier"
+	UserActionRequiredErr      = errNS + "userActionRequired"
 )
 
 // ProblemDetails the problem details object.
@@ -24,6 +51,10 @@ type ProblemDetails struct {
 	// additional values to have a better error message (Not defined by the RFC)
 	Method string `json:"method,omitempty"`
 	URL    string `json:"url,omitempty"`
+
+	// RetryAfter the duration to wait before retrying the request (Retry-After header).
+	// Only set on rate-limited and "service unavailable" responses, zero if the server didn't provide it.
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (p *ProblemDetails) Error() string {
@@ -41,6 +72,10 @@ func (p *ProblemDetails) Error() string {
 		msg += ", url: " + p.Instance
 	}
 
+	if p.RetryAfter > 0 {
+		msg += ", retry after: " + p.RetryAfter.String()
+	}
+
 	return msg
 }
 
@@ -52,15 +87,338 @@ type SubProblem struct {
 	Identifier Identifier `json:"identifier,omitempty"`
 }
 
+// Err returns the typed error of the subproblem.
+func (s SubProblem) Err() error {
+	return WrapProblem(&ProblemDetails{
+		Type:   s.Type,
+		Detail: s.Detail,
+	})
+}
+
+// SubProblemsFor returns the subproblems related to an identifier value (i.e. a domain or an IP address).
+func (p *ProblemDetails) SubProblemsFor(value string) []SubProblem {
+	var subs []SubProblem
+
+	for _, sub := range p.SubProblems {
+		if sub.Identifier.Value == value {
+			subs = append(subs, sub)
+		}
+	}
+
+	return subs
+}
+
 // NonceError represents the error which is returned
 // if the nonce sent by the client was not accepted by the server.
 type NonceError struct {
 	*ProblemDetails
 }
 
+func (e *NonceError) Unwrap() error {
+	return e.ProblemDetails
+}
+
 // AlreadyReplacedError represents the error which is returned
 // If the Server rejects the request because the identified certificate has already been marked as replaced.
 // - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
 type AlreadyReplacedError struct {
 	*ProblemDetails
 }
+
+func (e *AlreadyReplacedError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// AccountDoesNotExistError represents the error which is returned
+// if the request specified an account that does not exist.
+type AccountDoesNotExistError struct {
+	*ProblemDetails
+}
+
+func (e *AccountDoesNotExistError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// AlreadyRevokedError represents the error which is returned
+// if the request specified a certificate to be revoked that has already been revoked.
+type AlreadyRevokedError struct {
+	*ProblemDetails
+}
+
+func (e *AlreadyRevokedError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// BadCSRError represents the error which is returned
+// if the CSR is unacceptable (e.g., due to a short key).
+type BadCSRError struct {
+	*ProblemDetails
+}
+
+func (e *BadCSRError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// BadPublicKeyError represents the error which is returned
+// if the JWS was signed by a public key the server does not support.
+type BadPublicKeyError struct {
+	*ProblemDetails
+}
+
+func (e *BadPublicKeyError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// BadRevocationReasonError represents the error which is returned
+// if the revocation reason provided is not allowed by the server.
+type BadRevocationReasonError struct {
+	*ProblemDetails
+}
+
+func (e *BadRevocationReasonError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// BadSignatureAlgorithmError represents the error which is returned
+// if the JWS was signed with an algorithm the server does not support.
+type BadSignatureAlgorithmError struct {
+	*ProblemDetails
+}
+
+func (e *BadSignatureAlgorithmError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// CAAError represents the error which is returned
+// if the Certification Authority Authorization (CAA) records forbid the CA from issuing a certificate.
+type CAAError struct {
+	*ProblemDetails
+}
+
+func (e *CAAError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// CompoundError represents the error which is returned
+// if specific error conditions are indicated in the "subproblems" array.
+// The subproblems are available through ProblemDetails.SubProblemsFor.
+type CompoundError struct {
+	*ProblemDetails
+}
+
+func (e *CompoundError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// ConnectionError represents the error which is returned
+// if the server could not connect to the validation target.
+type ConnectionError struct {
+	*ProblemDetails
+}
+
+func (e *ConnectionError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// DNSError represents the error which is returned
+// if there was a problem with a DNS query during identifier validation.
+type DNSError struct {
+	*ProblemDetails
+}
+
+func (e *DNSError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// ExternalAccountRequiredError represents the error which is returned
+// if the request must include a value for the "externalAccountBinding" field.
+type ExternalAccountRequiredError struct {
+	*ProblemDetails
+}
+
+func (e *ExternalAccountRequiredError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// IncorrectResponseError represents the error which is returned
+// if the response received didn't match the challenge's requirements.
+type IncorrectResponseError struct {
+	*ProblemDetails
+}
+
+func (e *IncorrectResponseError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// InvalidContactError represents the error which is returned
+// if a contact URL for an account was invalid.
+type InvalidContactError struct {
+	*ProblemDetails
+}
+
+func (e *InvalidContactError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// MalformedError represents the error which is returned
+// if the request message was malformed.
+type MalformedError struct {
+	*ProblemDetails
+}
+
+func (e *MalformedError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// OrderNotReadyError represents the error which is returned
+// if the request attempted to finalize an order that is not ready to be finalized.
+type OrderNotReadyError struct {
+	*ProblemDetails
+}
+
+func (e *OrderNotReadyError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// RateLimitedError represents the error which is returned
+// if the request exceeds a rate limit.
+type RateLimitedError struct {
+	*ProblemDetails
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// RejectedIdentifierError represents the error which is returned
+// if the server will not issue certificates for the identifier.
+type RejectedIdentifierError struct {
+	*ProblemDetails
+}
+
+func (e *RejectedIdentifierError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// ServerInternalError represents the error which is returned
+// if the server experienced an internal error.
+type ServerInternalError struct {
+	*ProblemDetails
+}
+
+func (e *ServerInternalError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// TLSError represents the error which is returned
+// if the server received a TLS error during validation.
+type TLSError struct {
+	*ProblemDetails
+}
+
+func (e *TLSError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// UnauthorizedError represents the error which is returned
+// if the client lacks sufficient authorization.
+type UnauthorizedError struct {
+	*ProblemDetails
+}
+
+func (e *UnauthorizedError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// UnsupportedContactError represents the error which is returned
+// if a contact URL for an account used an unsupported protocol scheme.
+type UnsupportedContactError struct {
+	*ProblemDetails
+}
+
+func (e *UnsupportedContactError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// UnsupportedIdentifierError represents the error which is returned
+// if an identifier is of an unsupported type.
+type UnsupportedIdentifierError struct {
+	*ProblemDetails
+}
+
+func (e *UnsupportedIdentifierError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// UserActionRequiredError represents the error which is returned
+// if the user must visit the "instance" URL and take the actions specified there.
+type UserActionRequiredError struct {
+	*ProblemDetails
+}
+
+func (e *UserActionRequiredError) Unwrap() error {
+	return e.ProblemDetails
+}
+
+// WrapProblem returns the typed error matching the type of the problem.
+// The problem is returned as is if the type is unknown.
+func WrapProblem(p *ProblemDetails) error {
+	if p == nil {
+		return nil
+	}
+
+	switch p.Type {
+	case BadNonceErr:
+		return &NonceError{ProblemDetails: p}
+	case AlreadyReplacedErr:
+		return &AlreadyReplacedError{ProblemDetails: p}
+	case AccountDoesNotExistErr:
+		return &AccountDoesNotExistError{ProblemDetails: p}
+	case AlreadyRevokedErr:
+		return &AlreadyRevokedError{ProblemDetails: p}
+	case BadCSRErr:
+		return &BadCSRError{ProblemDetails: p}
+	case BadPublicKeyErr:
+		return &BadPublicKeyError{ProblemDetails: p}
+	case BadRevocationReasonErr:
+		return &BadRevocationReasonError{ProblemDetails: p}
+	case BadSignatureAlgorithmErr:
+		return &BadSignatureAlgorithmError{ProblemDetails: p}
+	case CAAErr:
+		return &CAAError{ProblemDetails: p}
+	case CompoundErr:
+		return &CompoundError{ProblemDetails: p}
+	case ConnectionErr:
+		return &ConnectionError{ProblemDetails: p}
+	case DNSErr:
+		return &DNSError{ProblemDetails: p}
+	case ExternalAccountRequiredErr:
+		return &ExternalAccountRequiredError{ProblemDetails: p}
+	case IncorrectResponseErr:
+		return &IncorrectResponseError{ProblemDetails: p}
+	case InvalidContactErr:
+		return &InvalidContactError{ProblemDetails: p}
+	case MalformedErr:
+		return &MalformedError{ProblemDetails: p}
+	case OrderNotReadyErr:
+		return &OrderNotReadyError{ProblemDetails: p}
+	case RateLimitedErr:
+		return &RateLimitedError{ProblemDetails: p}
+	case RejectedIdentifierErr:
+		return &RejectedIdentifierError{ProblemDetails: p}
+	case ServerInternalErr:
+		return &ServerInternalError{ProblemDetails: p}
+	case TLSErr:
+		return &TLSError{ProblemDetails: p}
+	case UnauthorizedErr:
+		return &UnauthorizedError{ProblemDetails: p}
+	case UnsupportedContactErr:
+		return &UnsupportedContactError{ProblemDetails: p}
+	case UnsupportedIdentifierErr:
+		return &UnsupportedIdentifierError{ProblemDetails: p}
+	case UserActionRequiredErr:
+		return &UserActionRequiredError{ProblemDetails: p}
+	default:
+		return p
+	}
+}
@@ -1,22 +1,24 @@
 package certificate
 
 import (
-	"time"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/log"
 )
 
-func (c *Certifier) getAuthorizations(order acme.ExtendedOrder) ([]acme.Authorization, error) {
+func (c *Certifier) getAuthorizations(ctx context.Context, order acme.ExtendedOrder) ([]acme.Authorization, error) {
 	resc, errc := make(chan acme.Authorization), make(chan domainError)
 
-	delay := time.Second / time.Duration(c.overallRequestLimit)
-
 	for _, authzURL := range order.Authorizations {
-		time.Sleep(delay)
+		// The error is ignored: the request fails if the context is canceled.
+		_ = c.limiter.Wait(ctx)
 
 		go func(authzURL string) {
-			authz, err := c.core.Authorizations.Get(authzURL)
+			authz, err := c.core.Authorizations.GetWithContext(ctx, authzURL)
 			if err != nil {
 				errc <- domainError{Domain: authz.Identifier.Value, Error: err}
 				return
@@ -48,9 +50,12 @@ func (c *Certifier) getAuthorizations(order acme.ExtendedOrder) ([]acme.Authoriz
 	return responses, failures.Join()
 }
 
-func (c *Certifier) deactivateAuthorizations(order acme.ExtendedOrder, force bool) {
-	for _, authzURL := range order.Authorizations {
-		auth, err := c.core.Authorizations.Get(authzURL)
+func (c *Certifier) deactivateAuthorizations(ctx context.Context, authzURLs []string, force bool) {
+	// The deactivation must happen even if the context has been cancelled.
+	ctx = context.WithoutCancel(ctx)
+
+	for _, authzURL := range authzURLs {
+		auth, err := c.core.Authorizations.GetWithContext(ctx, authzURL)
 		if err != nil {
 			log.Infof("Unable to get the authorization for %s: %v", authzURL, err)
 			continue
@@ -62,8 +67,80 @@ func (c *Certifier) deactivateAuthorizations(order acme.ExtendedOrder, force boo
 		}
 
 		log.Infof("Deactivating auth: %s", authzURL)
-		if c.core.Authorizations.Deactivate(authzURL) != nil {
+		if c.core.Authorizations.DeactivateWithContext(ctx, authzURL) != nil {
 			log.Infof("Unable to deactivate the authorization: %s", authzURL)
 		}
 	}
 }
+
+// PreAuthorize pre-authorizes the domains by using the newAuthz endpoint of the server, and solves the related challenges.
+// Once the authorizations are valid, the certificates for these domains can be obtained without solving new challenges
+// until the authorizations expire.
+//
+// A wildcard domain (*.example.com) cannot be pre-authorized:
+// the newAuthz endpoint must not be used for a wildcard identifier (RFC 8555 section 7.4.1),
+// a wildcard domain is only authorized through an order.
+// https://www.rfc-editor.org/rfc/rfc8555.html#section-7.4.1
+func (c *Certifier) PreAuthorize(domains []string) ([]acme.ExtendedAuthorization, error) {
+	return c.PreAuthorizeWithContext(context.Background(), domains)
+}
+
+// PreAuthorizeWithContext pre-authorizes the domains by using the newAuthz endpoint of the server, and solves the related challenges.
+// See PreAuthorize.
+func (c *Certifier) PreAuthorizeWithContext(ctx context.Context, domains []string) ([]acme.ExtendedAuthorization, error) {
+	if len(domains) == 0 {
+		return nil, errors.New("no domains to pre-authorize")
+	}
+
+	domains = sanitizeDomain(domains)
+
+	for _, domain := range domains {
+		if strings.HasPrefix(domain, "*.") {
+			return nil, fmt.Errorf("the wildcard domain %s cannot be pre-authorized (RFC 8555 section 7.4.1)", domain)
+		}
+	}
+
+	log.Infof("[%s] acme: Pre-authorizing domains", strings.Join(domains, ", "))
+
+	failures := newObtainError()
+
+	var authzURLs []string
+	var authorizations []acme.Authorization
+
+	for _, domain := range domains {
+		authz, err := c.core.Authorizations.NewWithContext(ctx, domain)
+		if err != nil {
+			failures.Add(domain, err)
+			continue
+		}
+
+		log.Infof("[%s] AuthURL: %s", domain, authz.Location)
+
+		authzURLs = append(authzURLs, authz.Location)
+		authorizations = append(authorizations, authz.Authorization)
+	}
+
+	err := c.solve(ctx, authorizations)
+	if err != nil {
+		// Relinquishes the authorizations that are not valid.
+		c.deactivateAuthorizations(ctx, authzURLs, false)
+
+		failures.AddFrom(err, domains)
+
+		return nil, failures.Join()
+	}
+
+	var results []acme.ExtendedAuthorization
+
+	for _, authzURL := range authzURLs {
+		authz, err := c.core.Authorizations.GetWithContext(ctx, authzURL)
+		if err != nil {
+			failures.Add(authzURL, err)
+			continue
+		}
+
+		results = append(results, acme.ExtendedAuthorization{Authorization: authz, Location: authzURL})
+	}
+
+	return results, failures.Join()
+}
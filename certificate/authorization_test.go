@@ -0,0 +1,151 @@
+package certificate
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_PreAuthorize(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("/newAuthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Location", apiURL+"/authz/1")
+		w.WriteHeader(http.StatusCreated)
+
+		err := tester.WriteJSONResponse(w, acme.Authorization{
+			Status:     acme.StatusPending,
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	mux.HandleFunc("/authz/1", func(w http.ResponseWriter, _ *http.Request) {
+		err := tester.WriteJSONResponse(w, acme.Authorization{
+			Status:     acme.StatusValid,
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	authorizations, err := certifier.PreAuthorize([]string{"example.com"})
+	require.NoError(t, err)
+
+	expected := []acme.ExtendedAuthorization{{
+		Authorization: acme.Authorization{
+			Status:     acme.StatusValid,
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		},
+		Location: apiURL + "/authz/1",
+	}}
+
+	assert.Equal(t, expected, authorizations)
+}
+
+func TestCertifier_PreAuthorize_wildcard(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	var newAuthzCount int
+
+	mux.HandleFunc("/newAuthz", func(w http.ResponseWriter, _ *http.Request) {
+		newAuthzCount++
+
+		http.Error(w, "unexpected newAuthz request", http.StatusBadRequest)
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	_, err = certifier.PreAuthorize([]string{"example.com", "*.example.com"})
+	require.EqualError(t, err, "the wildcard domain *.example.com cannot be pre-authorized (RFC 8555 section 7.4.1)")
+
+	assert.Zero(t, newAuthzCount)
+}
+
+func TestCertifier_PreAuthorize_solveError(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	mux.HandleFunc("/newAuthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Location", apiURL+"/authz/1")
+		w.WriteHeader(http.StatusCreated)
+
+		err := tester.WriteJSONResponse(w, acme.Authorization{
+			Status:     acme.StatusPending,
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	var deactivated bool
+
+	mux.HandleFunc("/authz/1", func(w http.ResponseWriter, r *http.Request) {
+		status := acme.StatusPending
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// POST-as-GET requests have an empty payload.
+		if !strings.Contains(string(body), `"payload":""`) {
+			deactivated = true
+			status = acme.StatusDeactivated
+		}
+
+		err = tester.WriteJSONResponse(w, acme.Authorization{
+			Status:     status,
+			Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &resolverMock{error: errors.New("solve error")}, CertifierOptions{KeyType: certcrypto.RSA2048})
+
+	_, err = certifier.PreAuthorize([]string{"example.com"})
+	require.EqualError(t, err, "error: one or more domains had a problem:\nexample.com: solve error")
+
+	assert.True(t, deactivated)
+}
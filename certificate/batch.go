@@ -0,0 +1,81 @@
+package certificate
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// BatchOptions options used by Certifier.ObtainBatch.
+type BatchOptions struct {
+	// Workers is the maximum number of certificates obtained concurrently (default: 1).
+	Workers int
+}
+
+// BatchResult the result of an ObtainRequest of a batch.
+type BatchResult struct {
+	Request ObtainRequest
+
+	// Resource is the certificate, nil if Err is not nil.
+	Resource *Resource
+	Err      error
+}
+
+// ObtainBatch obtains several certificates, with a limited number of concurrent orders.
+//
+// The requests to the CA are limited by the OverallRequestLimit of the Certifier,
+// and the DNS challenges of a same zone share one propagation wait.
+// The challenge providers must support concurrent calls if the number of workers is greater than 1.
+//
+// The results are in the same order as the requests.
+func (c *Certifier) ObtainBatch(requests []ObtainRequest, options *BatchOptions) []BatchResult {
+	return c.ObtainBatchWithContext(context.Background(), requests, options)
+}
+
+// ObtainBatchWithContext obtains several certificates, with a limited number of concurrent orders.
+//
+// The requests to the CA are limited by the OverallRequestLimit of the Certifier,
+// and the DNS challenges of a same zone share one propagation wait.
+// The challenge providers must support concurrent calls if the number of workers is greater than 1.
+//
+// The results are in the same order as the requests.
+func (c *Certifier) ObtainBatchWithContext(ctx context.Context, requests []ObtainRequest, options *BatchOptions) []BatchResult {
+	workers := 1
+	if options != nil && options.Workers > 0 {
+		workers = options.Workers
+	}
+
+	ctx = dns01.WithSharedPropagationWait(ctx)
+
+	results := make([]BatchResult, len(requests))
+	for i, request := range requests {
+		results[i].Request = request
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(workers, len(requests)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i].Resource, results[i].Err = c.ObtainWithContext(ctx, results[i].Request)
+			}
+		}()
+	}
+
+	for i := range requests {
+		indexes <- i
+	}
+
+	close(indexes)
+
+	wg.Wait()
+
+	return results
+}
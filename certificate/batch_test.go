@@ -0,0 +1,106 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester/acmeserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_ObtainBatch(t *testing.T) {
+	_, dirURL := acmeserver.Start(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", dirURL, "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	resolver := &validatingResolver{core: core, delay: 300 * time.Millisecond}
+
+	certifier := NewCertifier(core, resolver, CertifierOptions{KeyType: certcrypto.EC256})
+
+	requests := []ObtainRequest{
+		{Domains: []string{"a.example.com"}},
+		{Domains: []string{"b.example.com", "www.b.example.com"}},
+		{Domains: []string{"c.example.com"}},
+		{Domains: []string{"*.*.example.com"}},
+		{Domains: []string{"d.example.com"}},
+	}
+
+	results := certifier.ObtainBatch(requests, &BatchOptions{Workers: 2})
+	require.Len(t, results, len(requests))
+
+	for i, result := range results {
+		assert.Equal(t, requests[i], result.Request)
+
+		if i == 3 {
+			require.Error(t, result.Err)
+			assert.Nil(t, result.Resource)
+
+			continue
+		}
+
+		require.NoError(t, result.Err)
+
+		cert, err := certcrypto.ParsePEMCertificate(result.Resource.Certificate)
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, requests[i].Domains, cert.DNSNames)
+	}
+
+	assert.Equal(t, 2, resolver.maxInFlight)
+}
+
+// validatingResolver asks the server to validate the http-01 challenges.
+type validatingResolver struct {
+	core  *api.Core
+	delay time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (r *validatingResolver) Solve(authorizations []acme.Authorization) error {
+	r.mu.Lock()
+	r.inFlight++
+	r.maxInFlight = max(r.maxInFlight, r.inFlight)
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.inFlight--
+		r.mu.Unlock()
+	}()
+
+	time.Sleep(r.delay)
+
+	for _, authz := range authorizations {
+		chlg, err := challenge.FindChallenge(challenge.HTTP01, authz)
+		if err != nil {
+			return err
+		}
+
+		_, err = r.core.Challenges.New(chlg.URL)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,13 +2,16 @@ package certificate
 
 import (
 	"bytes"
+	"context"
 	"crypto"
 	"crypto/x509"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,6 +23,7 @@ import (
 	"github.com/go-acme/lego/v4/platform/wait"
 	"golang.org/x/crypto/ocsp"
 	"golang.org/x/net/idna"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -49,6 +53,9 @@ type Resource struct {
 	Certificate       []byte `json:"-"`
 	IssuerCertificate []byte `json:"-"`
 	CSR               []byte `json:"-"`
+
+	// DroppedDomains the domains dropped from a partial certificate (ObtainRequest.AllowPartial), with their errors.
+	DroppedDomains map[string]error `json:"-"`
 }
 
 // ObtainRequest The request to obtain certificate.
@@ -64,6 +71,9 @@ type Resource struct {
 //
 // If `AlwaysDeactivateAuthorizations` is true, the authorizations are also relinquished if the obtain request was successful.
 // See https://datatracker.ietf.org/doc/html/rfc8555#section-7.5.2.
+//
+// If `AllowPartial` is true, the domains with failed validations are dropped from the certificate
+// instead of failing the whole certificate: the dropped domains are reported in `Resource.DroppedDomains`.
 type ObtainRequest struct {
 	Domains        []string
 	PrivateKey     crypto.PrivateKey
@@ -86,6 +96,8 @@ type ObtainRequest struct {
 	// order is intended to replace.
 	// - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
 	ReplacesCertID string
+
+	AllowPartial bool
 }
 
 // ObtainForCSRRequest The request to obtain a certificate matching the CSR passed into it.
@@ -121,11 +133,21 @@ type resolver interface {
 	Solve(authorizations []acme.Authorization) error
 }
 
+type resolverWithContext interface {
+	SolveWithContext(ctx context.Context, authorizations []acme.Authorization) error
+}
+
 type CertifierOptions struct {
 	KeyType             certcrypto.KeyType
 	Timeout             time.Duration
 	OverallRequestLimit int
 	DisableCommonName   bool
+
+	// OrderStore allows resuming the in-flight orders (optional).
+	OrderStore OrderStore
+
+	// Notifier receives the events of the certificates: issued, renewed, failed (optional).
+	Notifier Notifier
 }
 
 // Certifier A service to obtain/renew/revoke certificates.
@@ -134,6 +156,9 @@ type Certifier struct {
 	resolver            resolver
 	options             CertifierOptions
 	overallRequestLimit int
+
+	// limiter limits the requests of all the orders (including the concurrent orders of a batch) to overallRequestLimit.
+	limiter *rate.Limiter
 }
 
 // NewCertifier creates a Certifier.
@@ -149,18 +174,36 @@ func NewCertifier(core *api.Core, resolver resolver, options CertifierOptions) *
 		c.overallRequestLimit = DefaultOverallRequestLimit
 	}
 
+	c.limiter = rate.NewLimiter(rate.Limit(c.overallRequestLimit), 1)
+
 	return c
 }
 
 // Obtain tries to obtain a single certificate using all domains passed into it.
 //
-// This function will never return a partial certificate.
+// This function will never return a partial certificate, unless `AllowPartial` is set.
 // If one domain in the list fails, the whole certificate will fail.
 func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
+	return c.ObtainWithContext(context.Background(), request)
+}
+
+// ObtainWithContext tries to obtain a single certificate using all domains passed into it.
+//
+// This function will never return a partial certificate, unless `AllowPartial` is set.
+// If one domain in the list fails, the whole certificate will fail.
+func (c *Certifier) ObtainWithContext(ctx context.Context, request ObtainRequest) (*Resource, error) {
 	if len(request.Domains) == 0 {
 		return nil, errors.New("no domains to obtain a certificate for")
 	}
 
+	cert, err := c.obtain(ctx, request)
+
+	c.notifyResult(ctx, EventIssued, request.Domains[0], request.Domains, cert, err)
+
+	return cert, err
+}
+
+func (c *Certifier) obtain(ctx context.Context, request ObtainRequest) (*Resource, error) {
 	domains := sanitizeDomain(request.Domains)
 
 	if request.Bundle {
@@ -176,42 +219,117 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 		ReplacesCertID: request.ReplacesCertID,
 	}
 
-	order, err := c.core.Orders.NewWithOptions(domains, orderOpts)
-	if err != nil {
-		return nil, err
+	order, privateKey, resumed := c.resumeOrder(ctx, domains, matchPrivateKey(request.PrivateKey))
+	if resumed {
+		if request.PrivateKey == nil {
+			// The private key has been generated by lego for the in-flight order.
+			request.PrivateKey = privateKey
+		}
+	} else {
+		if c.options.OrderStore != nil && request.PrivateKey == nil {
+			// The private key is generated before the order creation to be able to resume it.
+			var err error
+			request.PrivateKey, err = certcrypto.GeneratePrivateKey(c.options.KeyType)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err := c.limiter.Wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		order, err = c.core.Orders.NewWithOptionsAndContext(ctx, domains, orderOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		c.saveOrder(domains, order, request.PrivateKey, nil)
 	}
 
-	authz, err := c.getAuthorizations(order)
+	authz, err := c.getAuthorizations(ctx, order)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
-		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
+		c.deactivateAuthorizations(ctx, order.Authorizations, request.AlwaysDeactivateAuthorizations)
 		return nil, err
 	}
 
-	err = c.resolver.Solve(authz)
+	err = c.solve(ctx, authz)
 	if err != nil {
+		failures := newObtainError()
+		failures.AddFrom(err, domains)
+
+		if request.AllowPartial {
+			// The authorizations which are not valid (failed or still pending) are deactivated:
+			// the valid ones are reused by the next order.
+			c.deactivateAuthorizations(ctx, order.Authorizations, false)
+
+			return c.obtainPartial(ctx, request, domains, failures)
+		}
+
 		// If any challenge fails, return. Do not generate partial SAN certificates.
-		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
-		return nil, err
+		c.deactivateAuthorizations(ctx, order.Authorizations, request.AlwaysDeactivateAuthorizations)
+
+		return nil, failures.Join()
 	}
 
 	log.Infof("[%s] acme: Validations succeeded; requesting certificates", strings.Join(domains, ", "))
 
 	failures := newObtainError()
-	cert, err := c.getForOrder(domains, order, request)
+	cert, err := c.getForOrder(ctx, domains, order, request)
+	if resumed && errors.Is(err, errOrderMismatch) && c.deleteOrder(domains) {
+		log.Warnf("[%s] acme: %v, a new order will be created", domains[0], err)
+		return c.obtain(ctx, request)
+	}
+
 	if err != nil {
 		for _, auth := range authz {
 			failures.Add(challenge.GetTargetedDomain(auth), err)
 		}
+	} else {
+		c.deleteOrder(domains)
 	}
 
 	if request.AlwaysDeactivateAuthorizations {
-		c.deactivateAuthorizations(order, true)
+		c.deactivateAuthorizations(ctx, order.Authorizations, true)
 	}
 
 	return cert, failures.Join()
 }
 
+// obtainPartial obtains a certificate without the domains with failed validations.
+func (c *Certifier) obtainPartial(ctx context.Context, request ObtainRequest, domains []string, failures *obtainError) (*Resource, error) {
+	var remaining []string
+
+	for _, domain := range domains {
+		if _, ok := failures.data[domain]; !ok {
+			remaining = append(remaining, domain)
+		}
+	}
+
+	if len(remaining) == 0 {
+		return nil, failures.Join()
+	}
+
+	log.Warnf("[%s] acme: Dropping the domains with failed validations: %s", domains[0], strings.Join(slices.Sorted(maps.Keys(failures.data)), ", "))
+
+	request.Domains = remaining
+
+	cert, err := c.obtain(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if cert.DroppedDomains == nil {
+		cert.DroppedDomains = make(map[string]error)
+	}
+
+	maps.Copy(cert.DroppedDomains, failures.data)
+
+	return cert, nil
+}
+
 // ObtainForCSR tries to obtain a certificate matching the CSR passed into it.
 //
 // The domains are inferred from the CommonName and SubjectAltNames, if any.
@@ -222,6 +340,19 @@ func (c *Certifier) Obtain(request ObtainRequest) (*Resource, error) {
 // This function will never return a partial certificate.
 // If one domain in the list fails, the whole certificate will fail.
 func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error) {
+	return c.ObtainForCSRWithContext(context.Background(), request)
+}
+
+// ObtainForCSRWithContext tries to obtain a certificate matching the CSR passed into it.
+//
+// The domains are inferred from the CommonName and SubjectAltNames, if any.
+// The private key for this CSR is not required.
+//
+// If bundle is true, the []byte contains both the issuer certificate and your issued certificate as a bundle.
+//
+// This function will never return a partial certificate.
+// If one domain in the list fails, the whole certificate will fail.
+func (c *Certifier) ObtainForCSRWithContext(ctx context.Context, request ObtainForCSRRequest) (*Resource, error) {
 	if request.CSR == nil {
 		return nil, errors.New("cannot obtain resource for CSR: CSR is missing")
 	}
@@ -230,6 +361,14 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 	// start with the common name
 	domains := certcrypto.ExtractDomainsCSR(request.CSR)
 
+	cert, err := c.obtainForCSR(ctx, request, domains)
+
+	c.notifyResult(ctx, EventIssued, "", domains, cert, err)
+
+	return cert, err
+}
+
+func (c *Certifier) obtainForCSR(ctx context.Context, request ObtainForCSRRequest, domains []string) (*Resource, error) {
 	if request.Bundle {
 		log.Infof("[%s] acme: Obtaining bundled SAN certificate given a CSR", strings.Join(domains, ", "))
 	} else {
@@ -243,23 +382,37 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		ReplacesCertID: request.ReplacesCertID,
 	}
 
-	order, err := c.core.Orders.NewWithOptions(domains, orderOpts)
-	if err != nil {
-		return nil, err
+	order, _, resumed := c.resumeOrder(ctx, domains, matchCSR(request.CSR.Raw))
+	if !resumed {
+		err := c.limiter.Wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		order, err = c.core.Orders.NewWithOptionsAndContext(ctx, domains, orderOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		c.saveOrder(domains, order, request.PrivateKey, request.CSR.Raw)
 	}
 
-	authz, err := c.getAuthorizations(order)
+	authz, err := c.getAuthorizations(ctx, order)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
-		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
+		c.deactivateAuthorizations(ctx, order.Authorizations, request.AlwaysDeactivateAuthorizations)
 		return nil, err
 	}
 
-	err = c.resolver.Solve(authz)
+	err = c.solve(ctx, authz)
 	if err != nil {
 		// If any challenge fails, return. Do not generate partial SAN certificates.
-		c.deactivateAuthorizations(order, request.AlwaysDeactivateAuthorizations)
-		return nil, err
+		c.deactivateAuthorizations(ctx, order.Authorizations, request.AlwaysDeactivateAuthorizations)
+
+		failures := newObtainError()
+		failures.AddFrom(err, domains)
+
+		return nil, failures.Join()
 	}
 
 	log.Infof("[%s] acme: Validations succeeded; requesting certificates", strings.Join(domains, ", "))
@@ -271,15 +424,22 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 		privateKey = certcrypto.PEMEncode(request.PrivateKey)
 	}
 
-	cert, err := c.getForCSR(domains, order, request.Bundle, request.CSR.Raw, privateKey, request.PreferredChain)
+	cert, err := c.getForCSR(ctx, domains, order, request.Bundle, request.CSR.Raw, privateKey, request.PreferredChain)
+	if resumed && errors.Is(err, errOrderMismatch) && c.deleteOrder(domains) {
+		log.Warnf("[%s] acme: %v, a new order will be created", domains[0], err)
+		return c.obtainForCSR(ctx, request, domains)
+	}
+
 	if err != nil {
 		for _, auth := range authz {
 			failures.Add(challenge.GetTargetedDomain(auth), err)
 		}
+	} else {
+		c.deleteOrder(domains)
 	}
 
 	if request.AlwaysDeactivateAuthorizations {
-		c.deactivateAuthorizations(order, true)
+		c.deactivateAuthorizations(ctx, order.Authorizations, true)
 	}
 
 	if cert != nil {
@@ -290,7 +450,15 @@ func (c *Certifier) ObtainForCSR(request ObtainForCSRRequest) (*Resource, error)
 	return cert, failures.Join()
 }
 
-func (c *Certifier) getForOrder(domains []string, order acme.ExtendedOrder, request ObtainRequest) (*Resource, error) {
+func (c *Certifier) solve(ctx context.Context, authz []acme.Authorization) error {
+	if r, ok := c.resolver.(resolverWithContext); ok {
+		return r.SolveWithContext(ctx, authz)
+	}
+
+	return c.resolver.Solve(authz)
+}
+
+func (c *Certifier) getForOrder(ctx context.Context, domains []string, order acme.ExtendedOrder, request ObtainRequest) (*Resource, error) {
 	privateKey := request.PrivateKey
 
 	if privateKey == nil {
@@ -336,13 +504,21 @@ func (c *Certifier) getForOrder(domains []string, order acme.ExtendedOrder, requ
 		return nil, err
 	}
 
-	return c.getForCSR(domains, order, request.Bundle, csr, certcrypto.PEMEncode(privateKey), request.PreferredChain)
+	return c.getForCSR(ctx, domains, order, request.Bundle, csr, certcrypto.PEMEncode(privateKey), request.PreferredChain)
 }
 
-func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle bool, csr, privateKeyPem []byte, preferredChain string) (*Resource, error) {
-	respOrder, err := c.core.Orders.UpdateForCSR(order.Finalize, csr)
-	if err != nil {
-		return nil, err
+func (c *Certifier) getForCSR(ctx context.Context, domains []string, order acme.ExtendedOrder, bundle bool, csr, privateKeyPem []byte, preferredChain string) (*Resource, error) {
+	respOrder := order
+
+	// A resumed order can already be finalized.
+	finalized := order.Status == acme.StatusProcessing || order.Status == acme.StatusValid
+
+	if !finalized {
+		var err error
+		respOrder, err = c.core.Orders.UpdateForCSRWithContext(ctx, order.Finalize, csr)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	certRes := &Resource{
@@ -353,13 +529,13 @@ func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle
 
 	if respOrder.Status == acme.StatusValid {
 		// if the certificate is available right away, shortcut!
-		ok, errR := c.checkResponse(respOrder, certRes, bundle, preferredChain)
+		ok, errR := c.checkResponse(ctx, respOrder, certRes, bundle, preferredChain)
 		if errR != nil {
 			return nil, errR
 		}
 
 		if ok {
-			return certRes, nil
+			return c.checkFinalizedOrder(domains, certRes, csr, finalized)
 		}
 	}
 
@@ -368,21 +544,47 @@ func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle
 		timeout = 30 * time.Second
 	}
 
-	err = wait.For("certificate", timeout, timeout/60, func() (bool, error) {
-		ord, errW := c.core.Orders.Get(order.Location)
+	err := wait.ForWithContext(ctx, "certificate", timeout, timeout/60, func() (bool, error) {
+		ord, errW := c.core.Orders.GetWithContext(ctx, order.Location)
 		if errW != nil {
 			return false, errW
 		}
 
-		done, errW := c.checkResponse(ord, certRes, bundle, preferredChain)
+		done, errW := c.checkResponse(ctx, ord, certRes, bundle, preferredChain)
 		if errW != nil {
 			return false, errW
 		}
 
 		return done, nil
 	})
+	if err != nil {
+		return certRes, err
+	}
 
-	return certRes, err
+	return c.checkFinalizedOrder(domains, certRes, csr, finalized)
+}
+
+// checkFinalizedOrder checks that the certificate of a resumed order, finalized by a previous attempt, matches the CSR.
+func (c *Certifier) checkFinalizedOrder(domains []string, certRes *Resource, csr []byte, finalized bool) (*Resource, error) {
+	if !finalized {
+		return certRes, nil
+	}
+
+	certificateRequest, err := x509.ParseCertificateRequest(csr)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	if !samePublicKey(cert.PublicKey, certificateRequest.PublicKey) {
+		return nil, fmt.Errorf("[%s] acme: %w", domains[0], errOrderMismatch)
+	}
+
+	return certRes, nil
 }
 
 // checkResponse checks to see if the certificate is ready and a link is contained in the response.
@@ -393,13 +595,13 @@ func (c *Certifier) getForCSR(domains []string, order acme.ExtendedOrder, bundle
 // The certRes input should already have the Domain (common name) field populated.
 //
 // If bundle is true, the certificate will be bundled with the issuer's cert.
-func (c *Certifier) checkResponse(order acme.ExtendedOrder, certRes *Resource, bundle bool, preferredChain string) (bool, error) {
+func (c *Certifier) checkResponse(ctx context.Context, order acme.ExtendedOrder, certRes *Resource, bundle bool, preferredChain string) (bool, error) {
 	valid, err := checkOrderStatus(order)
 	if err != nil || !valid {
 		return valid, err
 	}
 
-	certs, err := c.core.Certificates.GetAll(order.Certificate, bundle)
+	certs, err := c.core.Certificates.GetAllWithContext(ctx, order.Certificate, bundle)
 	if err != nil {
 		return false, err
 	}
@@ -446,22 +648,54 @@ func (c *Certifier) Revoke(cert []byte) error {
 
 // RevokeWithReason takes a PEM encoded certificate or bundle and tries to revoke it at the CA.
 func (c *Certifier) RevokeWithReason(cert []byte, reason *uint) error {
-	certificates, err := certcrypto.ParsePEMBundle(cert)
+	x509Cert, err := parseRevokedCertificate(cert)
 	if err != nil {
 		return err
 	}
 
+	return c.core.Certificates.Revoke(newRevokeCertMessage(x509Cert, reason))
+}
+
+// RevokeWithKey takes a PEM encoded certificate or bundle and tries to revoke it at the CA.
+// The request is signed with the private key of the certificate, so the certificate can be revoked without the account which issued it.
+func (c *Certifier) RevokeWithKey(cert []byte, privateKey crypto.PrivateKey, reason *uint) error {
+	x509Cert, err := parseRevokedCertificate(cert)
+	if err != nil {
+		return err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type: %T", privateKey)
+	}
+
+	pub, ok := signer.Public().(interface{ Equal(x crypto.PublicKey) bool })
+	if !ok || !pub.Equal(x509Cert.PublicKey) {
+		return errors.New("the private key doesn't match the certificate")
+	}
+
+	return c.core.Certificates.RevokeWithKey(newRevokeCertMessage(x509Cert, reason), privateKey)
+}
+
+func parseRevokedCertificate(cert []byte) (*x509.Certificate, error) {
+	certificates, err := certcrypto.ParsePEMBundle(cert)
+	if err != nil {
+		return nil, err
+	}
+
 	x509Cert := certificates[0]
 	if x509Cert.IsCA {
-		return errors.New("certificate bundle starts with a CA certificate")
+		return nil, errors.New("certificate bundle starts with a CA certificate")
 	}
 
-	revokeMsg := acme.RevokeCertMessage{
-		Certificate: base64.RawURLEncoding.EncodeToString(x509Cert.Raw),
+	return x509Cert, nil
+}
+
+func newRevokeCertMessage(cert *x509.Certificate, reason *uint) acme.RevokeCertMessage {
+	return acme.RevokeCertMessage{
+		Certificate: base64.RawURLEncoding.EncodeToString(cert.Raw),
 		Reason:      reason,
 	}
-
-	return c.core.Certificates.Revoke(revokeMsg)
 }
 
 // RenewOptions options used by Certifier.RenewWithOptions.
@@ -478,6 +712,10 @@ type RenewOptions struct {
 	// Not supported for CSR request.
 	MustStaple     bool
 	EmailAddresses []string
+
+	// A string uniquely identifying the certificate replaced by the new certificate (ARI CertID).
+	// - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
+	ReplacesCertID string
 }
 
 // Renew takes a Resource and tries to renew the certificate.
@@ -510,6 +748,20 @@ func (c *Certifier) Renew(certRes Resource, bundle, mustStaple bool, preferredCh
 //
 // For private key reuse the PrivateKey property of the passed in Resource should be non-nil.
 func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*Resource, error) {
+	return c.RenewWithContext(context.Background(), certRes, options)
+}
+
+// RenewWithContext takes a Resource and tries to renew the certificate.
+//
+// If the renewal process succeeds, the new certificate will be returned in a new CertResource.
+// Please be aware that this function will return a new certificate in ANY case that is not an error.
+// If the server does not provide us with a new cert on a GET request to the CertURL
+// this function will start a new-cert flow where a new certificate gets generated.
+//
+// If bundle is true, the []byte contains both the issuer certificate and your issued certificate as a bundle.
+//
+// For private key reuse the PrivateKey property of the passed in Resource should be non-nil.
+func (c *Certifier) RenewWithContext(ctx context.Context, certRes Resource, options *RenewOptions) (*Resource, error) {
 	// Input certificate is PEM encoded.
 	// Decode it here as we may need the decoded cert later on in the renewal process.
 	// The input may be a bundle or a single certificate.
@@ -545,9 +797,16 @@ func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*
 			request.PreferredChain = options.PreferredChain
 			request.Profile = options.Profile
 			request.AlwaysDeactivateAuthorizations = options.AlwaysDeactivateAuthorizations
+			request.ReplacesCertID = options.ReplacesCertID
 		}
 
-		return c.ObtainForCSR(request)
+		domains := certcrypto.ExtractDomainsCSR(csr)
+
+		renewed, err := c.obtainForCSR(ctx, request, domains)
+
+		c.notifyResult(ctx, EventRenewed, certRes.Domain, domains, renewed, err)
+
+		return renewed, err
 	}
 
 	var privateKey crypto.PrivateKey
@@ -572,9 +831,14 @@ func (c *Certifier) RenewWithOptions(certRes Resource, options *RenewOptions) (*
 		request.EmailAddresses = options.EmailAddresses
 		request.Profile = options.Profile
 		request.AlwaysDeactivateAuthorizations = options.AlwaysDeactivateAuthorizations
+		request.ReplacesCertID = options.ReplacesCertID
 	}
 
-	return c.Obtain(request)
+	renewed, err := c.obtain(ctx, request)
+
+	c.notifyResult(ctx, EventRenewed, certRes.Domain, request.Domains, renewed, err)
+
+	return renewed, err
 }
 
 // GetOCSP takes a PEM encoded cert or cert bundle returning the raw OCSP response,
@@ -1,9 +1,13 @@
 package certificate
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -11,7 +15,9 @@ import (
 	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certcrypto"
+	challengeresolver "github.com/go-acme/lego/v4/challenge/resolver"
 	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/go-acme/lego/v4/platform/tester/acmeserver"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -201,7 +207,7 @@ func Test_checkResponse(t *testing.T) {
 	}
 	certRes := &Resource{}
 
-	valid, err := certifier.checkResponse(order, certRes, true, "")
+	valid, err := certifier.checkResponse(context.Background(), order, certRes, true, "")
 	require.NoError(t, err)
 	assert.True(t, valid)
 	assert.NotNil(t, certRes)
@@ -251,7 +257,7 @@ func Test_checkResponse_issuerRelUp(t *testing.T) {
 	}
 	certRes := &Resource{}
 
-	valid, err := certifier.checkResponse(order, certRes, true, "")
+	valid, err := certifier.checkResponse(context.Background(), order, certRes, true, "")
 	require.NoError(t, err)
 	assert.True(t, valid)
 	assert.NotNil(t, certRes)
@@ -291,7 +297,7 @@ func Test_checkResponse_no_bundle(t *testing.T) {
 	}
 	certRes := &Resource{}
 
-	valid, err := certifier.checkResponse(order, certRes, false, "")
+	valid, err := certifier.checkResponse(context.Background(), order, certRes, false, "")
 	require.NoError(t, err)
 	assert.True(t, valid)
 	assert.NotNil(t, certRes)
@@ -343,7 +349,7 @@ func Test_checkResponse_alternate(t *testing.T) {
 		Domain: "example.com",
 	}
 
-	valid, err := certifier.checkResponse(order, certRes, true, "DST Root CA X3")
+	valid, err := certifier.checkResponse(context.Background(), order, certRes, true, "DST Root CA X3")
 	require.NoError(t, err)
 
 	assert.True(t, valid)
@@ -441,3 +447,87 @@ type resolverMock struct {
 func (r *resolverMock) Solve(_ []acme.Authorization) error {
 	return r.error
 }
+
+func TestCertifier_Obtain_allowPartial(t *testing.T) {
+	validator := acmeserver.ValidatorFunc(func(_ context.Context, req acmeserver.ValidationRequest) error {
+		if req.Identifier.Value == "bad.example.com" {
+			return errors.New("connection refused")
+		}
+
+		return nil
+	})
+
+	_, dirURL := acmeserver.Start(t, acmeserver.WithValidator(validator))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", dirURL, "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	solversManager := challengeresolver.NewSolversManager(core)
+
+	err = solversManager.SetHTTP01Provider(noopProvider{})
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, challengeresolver.NewProber(solversManager), CertifierOptions{KeyType: certcrypto.EC256})
+
+	request := ObtainRequest{Domains: []string{"example.com", "bad.example.com", "www.example.com"}}
+
+	_, err = certifier.Obtain(request)
+	require.Error(t, err)
+
+	request.AllowPartial = true
+
+	certRes, err := certifier.Obtain(request)
+	require.NoError(t, err)
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com", "www.example.com"}, cert.DNSNames)
+
+	require.Len(t, certRes.DroppedDomains, 1)
+	assert.ErrorContains(t, certRes.DroppedDomains["bad.example.com"], acme.IncorrectResponseErr)
+}
+
+func TestCertifier_Obtain_allowPartial_allFailed(t *testing.T) {
+	validator := acmeserver.ValidatorFunc(func(_ context.Context, _ acmeserver.ValidationRequest) error {
+		return errors.New("connection refused")
+	})
+
+	_, dirURL := acmeserver.Start(t, acmeserver.WithValidator(validator))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", dirURL, "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	solversManager := challengeresolver.NewSolversManager(core)
+
+	err = solversManager.SetHTTP01Provider(noopProvider{})
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, challengeresolver.NewProber(solversManager), CertifierOptions{KeyType: certcrypto.EC256})
+
+	_, err = certifier.Obtain(ObtainRequest{Domains: []string{"example.com", "www.example.com"}, AllowPartial: true})
+	require.Error(t, err)
+
+	var obtainErr *ObtainError
+	require.ErrorAs(t, err, &obtainErr)
+	assert.Len(t, obtainErr.Errors, 2)
+}
+
+// noopProvider an HTTP-01 provider, the server validates the challenges with its own validator.
+type noopProvider struct{}
+
+func (noopProvider) Present(_, _, _ string) error { return nil }
+
+func (noopProvider) CleanUp(_, _, _ string) error { return nil }
@@ -1,10 +1,52 @@
 package certificate
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 )
 
+// ObtainError is returned when one or more domains had a problem.
+// The typed ACME errors (i.e. acme.CAAError) can be retrieved with errors.As.
+type ObtainError struct {
+	// Errors by domain.
+	Errors map[string]error
+}
+
+func (e *ObtainError) Error() string {
+	buffer := bytes.NewBufferString("error: one or more domains had a problem:\n")
+
+	for i, domain := range e.domains() {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+
+		_, _ = fmt.Fprintf(buffer, "%s: %s", domain, e.Errors[domain])
+	}
+
+	return buffer.String()
+}
+
+func (e *ObtainError) Unwrap() []error {
+	var errs []error
+	for _, domain := range e.domains() {
+		errs = append(errs, e.Errors[domain])
+	}
+
+	return errs
+}
+
+func (e *ObtainError) domains() []string {
+	var domains []string
+	for domain := range e.Errors {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	return domains
+}
+
 type obtainError struct {
 	data map[string]error
 }
@@ -17,6 +59,23 @@ func (e *obtainError) Add(domain string, err error) {
 	e.data[domain] = err
 }
 
+// AddFrom adds the errors by domain contained in the error (i.e. the resolver errors).
+// If the error doesn't contain errors by domain, the error is added for all the domains.
+func (e *obtainError) AddFrom(err error, domains []string) {
+	var de domainErrors
+	if errors.As(err, &de) {
+		for domain, errD := range de.DomainErrors() {
+			e.Add(domain, errD)
+		}
+
+		return
+	}
+
+	for _, domain := range domains {
+		e.Add(domain, err)
+	}
+}
+
 func (e *obtainError) Join() error {
 	if e == nil {
 		return nil
@@ -26,12 +85,12 @@ func (e *obtainError) Join() error {
 		return nil
 	}
 
-	var err error
-	for d, e := range e.data {
-		err = errors.Join(err, fmt.Errorf("%s: %w", d, e))
-	}
+	return &ObtainError{Errors: e.data}
+}
 
-	return fmt.Errorf("error: one or more domains had a problem:\n%w", err)
+// domainErrors is implemented by the errors containing an error by domain.
+type domainErrors interface {
+	DomainErrors() map[string]error
 }
 
 type domainError struct {
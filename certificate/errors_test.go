@@ -67,3 +67,39 @@ func Test_obtainError_Join_same_domain(t *testing.T) {
 	ca := &CarrotError{}
 	require.ErrorAs(t, err, &ca)
 }
+
+func Test_obtainError_AddFrom(t *testing.T) {
+	failures := newObtainError()
+
+	failures.AddFrom(domainErrorsMock{"example.org": &CarrotError{}}, []string{"example.com", "example.org"})
+
+	err := failures.Join()
+
+	var obtainErr *ObtainError
+	require.ErrorAs(t, err, &obtainErr)
+
+	require.Len(t, obtainErr.Errors, 1)
+
+	ca := &CarrotError{}
+	require.ErrorAs(t, obtainErr.Errors["example.org"], &ca)
+}
+
+func Test_obtainError_AddFrom_notByDomain(t *testing.T) {
+	failures := newObtainError()
+
+	failures.AddFrom(&TomatoError{}, []string{"example.com", "example.org"})
+
+	err := failures.Join()
+
+	require.EqualError(t, err, "error: one or more domains had a problem:\nexample.com: tomato\nexample.org: tomato")
+}
+
+type domainErrorsMock map[string]error
+
+func (e domainErrorsMock) Error() string {
+	return "domain errors"
+}
+
+func (e domainErrorsMock) DomainErrors() map[string]error {
+	return e
+}
@@ -0,0 +1,156 @@
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// EventType the type of certificate event.
+type EventType string
+
+// Certificate events.
+const (
+	// EventIssued a certificate has been obtained (Obtain, ObtainForCSR, ObtainBatch, ObtainSplit).
+	EventIssued EventType = "issued"
+
+	// EventRenewed a certificate has been renewed (Renew, RenewalManager).
+	EventRenewed EventType = "renewed"
+
+	// EventSkipped a certificate doesn't need to be renewed, or its renewal has been deferred by the CA.
+	// This event is not sent by the Certifier.
+	EventSkipped EventType = "skipped"
+
+	// EventFailed a certificate cannot be obtained or renewed.
+	EventFailed EventType = "failed"
+
+	// EventExpiring a certificate is nearing its expiry (RenewalManager).
+	EventExpiring EventType = "expiring"
+
+	// EventRenewalWindowChanged the renewal window suggested by the renewalInfo endpoint (ARI) has changed (RenewalManager).
+	EventRenewalWindowChanged EventType = "renewal_window_changed"
+)
+
+// Event an event of the lifecycle of a certificate.
+type Event struct {
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Domain the name of the certificate (usually the first domain).
+	Domain  string   `json:"domain"`
+	Domains []string `json:"domains,omitempty"`
+
+	// The certificate: the new certificate (issued, renewed), or the current certificate.
+	Serial    string     `json:"serial,omitempty"`
+	Issuer    string     `json:"issuer,omitempty"`
+	NotBefore *time.Time `json:"notBefore,omitempty"`
+	NotAfter  *time.Time `json:"notAfter,omitempty"`
+
+	// RenewalWindow the renewal window suggested by the renewalInfo endpoint (ARI).
+	RenewalWindow *acme.Window `json:"renewalWindow,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// NewEvent creates an event, with the information of the certificate (optional).
+func NewEvent(eventType EventType, domain string, cert *x509.Certificate) *Event {
+	event := &Event{
+		Type:   eventType,
+		Time:   time.Now().UTC(),
+		Domain: domain,
+	}
+
+	if cert == nil {
+		return event
+	}
+
+	event.Domains = certcrypto.ExtractDomains(cert)
+	event.Serial = fmt.Sprintf("%x", cert.SerialNumber)
+	event.Issuer = cert.Issuer.String()
+	event.NotBefore = &cert.NotBefore
+	event.NotAfter = &cert.NotAfter
+
+	return event
+}
+
+// Notifier receives the events of the certificates.
+type Notifier interface {
+	Notify(ctx context.Context, event *Event) error
+}
+
+// NotifierFunc an adapter to use a function as a Notifier.
+type NotifierFunc func(ctx context.Context, event *Event) error
+
+// Notify calls f(ctx, event).
+func (f NotifierFunc) Notify(ctx context.Context, event *Event) error {
+	return f(ctx, event)
+}
+
+// Notifiers sends the events to several notifiers.
+type Notifiers []Notifier
+
+// Notify sends the event to all the notifiers, even if some of them fail.
+func (n Notifiers) Notify(ctx context.Context, event *Event) error {
+	var errs []error
+
+	for _, notifier := range n {
+		errs = append(errs, notifier.Notify(ctx, event))
+	}
+
+	return errors.Join(errs...)
+}
+
+// notify sends an event to the notifier of the Certifier, if any.
+// The failures are only logged: they don't change the result of the operation.
+func (c *Certifier) notify(ctx context.Context, event *Event) {
+	if c.options.Notifier == nil {
+		return
+	}
+
+	err := c.options.Notifier.Notify(context.WithoutCancel(ctx), event)
+	if err != nil {
+		log.Warnf("[%s] notifier: %s event: %v", event.Domain, event.Type, err)
+	}
+}
+
+// notifyResult sends the result of an order: the new certificate, or the error.
+// The name of the certificate is the first domain by default.
+func (c *Certifier) notifyResult(ctx context.Context, eventType EventType, domain string, domains []string, certRes *Resource, err error) {
+	if c.options.Notifier == nil {
+		return
+	}
+
+	if domain == "" && len(domains) > 0 {
+		domain = domains[0]
+	}
+
+	if err != nil {
+		event := NewEvent(EventFailed, domain, nil)
+		event.Domains = domains
+		event.Error = err.Error()
+
+		c.notify(ctx, event)
+
+		return
+	}
+
+	var cert *x509.Certificate
+
+	certificates, errP := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if errP == nil {
+		cert = certificates[0]
+	}
+
+	event := NewEvent(eventType, domain, cert)
+	if cert == nil {
+		event.Domains = domains
+	}
+
+	c.notify(ctx, event)
+}
@@ -0,0 +1,124 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	challengeresolver "github.com/go-acme/lego/v4/challenge/resolver"
+	"github.com/go-acme/lego/v4/platform/tester/acmeserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_notifier(t *testing.T) {
+	validator := acmeserver.ValidatorFunc(func(_ context.Context, req acmeserver.ValidationRequest) error {
+		if req.Identifier.Value == "bad.example.com" {
+			return errors.New("connection refused")
+		}
+
+		return nil
+	})
+
+	_, dirURL := acmeserver.Start(t, acmeserver.WithValidator(validator))
+
+	certifier := setupNotifierTest(t, dirURL)
+
+	var (
+		mu     sync.Mutex
+		events []*Event
+	)
+
+	certifier.options.Notifier = NotifierFunc(func(_ context.Context, event *Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, event)
+
+		return nil
+	})
+
+	_, err := certifier.Obtain(ObtainRequest{Domains: []string{"bad.example.com", "example.com"}})
+	require.Error(t, err)
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com", "www.example.com"}})
+	require.NoError(t, err)
+
+	certRes.Domain = "example"
+
+	renewed, err := certifier.RenewWithOptions(*certRes, nil)
+	require.NoError(t, err)
+
+	require.Len(t, events, 3)
+
+	assert.Equal(t, EventFailed, events[0].Type)
+	assert.Equal(t, "bad.example.com", events[0].Domain)
+	assert.Equal(t, []string{"bad.example.com", "example.com"}, events[0].Domains)
+	assert.NotEmpty(t, events[0].Error)
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, EventIssued, events[1].Type)
+	assert.Equal(t, "example.com", events[1].Domain)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, events[1].Domains)
+	assert.Equal(t, cert.NotAfter, *events[1].NotAfter)
+	assert.Empty(t, events[1].Error)
+
+	renewedCert, err := certcrypto.ParsePEMCertificate(renewed.Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, EventRenewed, events[2].Type)
+	assert.Equal(t, "example", events[2].Domain)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, events[2].Domains)
+	assert.NotEqual(t, events[1].Serial, events[2].Serial)
+	assert.Equal(t, renewedCert.Issuer.String(), events[2].Issuer)
+}
+
+func TestNotifiers_Notify(t *testing.T) {
+	var count int
+
+	notifiers := Notifiers{
+		NotifierFunc(func(_ context.Context, _ *Event) error {
+			count++
+			return errors.New("boom")
+		}),
+		NotifierFunc(func(_ context.Context, _ *Event) error {
+			count++
+			return nil
+		}),
+	}
+
+	err := notifiers.Notify(t.Context(), NewEvent(EventSkipped, "example.com", nil))
+	require.EqualError(t, err, "boom")
+
+	assert.Equal(t, 2, count)
+}
+
+func setupNotifierTest(t *testing.T, dirURL string) *Certifier {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", dirURL, "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	solversManager := challengeresolver.NewSolversManager(core)
+
+	err = solversManager.SetHTTP01Provider(noopProvider{})
+	require.NoError(t, err)
+
+	return NewCertifier(core, challengeresolver.NewProber(solversManager), CertifierOptions{KeyType: certcrypto.EC256})
+}
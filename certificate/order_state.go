@@ -0,0 +1,194 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"slices"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// OrderState the state of an in-flight order.
+// It allows resuming an order after a crash or a timeout, instead of creating a new one.
+type OrderState struct {
+	// The order URL.
+	OrderURL string `json:"orderURL"`
+
+	// The authorization URLs of the order.
+	AuthorizationURLs []string `json:"authorizations,omitempty"`
+
+	// The domains of the order.
+	Domains []string `json:"domains"`
+
+	// PEM encoded private key used to create the CSR.
+	// Empty when the private key is not known (ObtainForCSR without private key).
+	PrivateKey []byte `json:"-"`
+
+	// DER encoded CSR of the order (ObtainForCSR only).
+	CSR []byte `json:"csr,omitempty"`
+}
+
+// OrderStore persists the state of the in-flight orders.
+// The orders are identified by the main domain of the certificate (Resource.Domain).
+type OrderStore interface {
+	// LoadOrder returns the state of the in-flight order, or nil if there is no in-flight order.
+	LoadOrder(domain string) (*OrderState, error)
+	// SaveOrder stores the state of an in-flight order.
+	SaveOrder(domain string, state *OrderState) error
+	// DeleteOrder removes the state of the in-flight order.
+	DeleteOrder(domain string) error
+}
+
+// errOrderMismatch the certificate of a resumed order doesn't match the private key or the CSR of the request.
+var errOrderMismatch = errors.New("the certificate of the in-flight order doesn't match the CSR")
+
+// orderMatcher checks if an in-flight order has been created for the private key or the CSR of a request.
+type orderMatcher func(privateKey crypto.PrivateKey, csr []byte) bool
+
+// resumeOrder returns the in-flight order for the domains, if it can be resumed,
+// and the private key associated with it (can be nil).
+// The order is resumed only if it matches the request:
+// the certificate of a resumed order must match the private key or the CSR of the request.
+func (c *Certifier) resumeOrder(ctx context.Context, domains []string, matches orderMatcher) (acme.ExtendedOrder, crypto.PrivateKey, bool) {
+	store := c.options.OrderStore
+	if store == nil {
+		return acme.ExtendedOrder{}, nil, false
+	}
+
+	state, err := store.LoadOrder(domains[0])
+	if err != nil {
+		log.Warnf("[%s] acme: unable to load the in-flight order: %v", domains[0], err)
+		return acme.ExtendedOrder{}, nil, false
+	}
+
+	if state == nil || state.OrderURL == "" {
+		return acme.ExtendedOrder{}, nil, false
+	}
+
+	if !sameDomains(state.Domains, domains) {
+		log.Infof("[%s] acme: the domains of the in-flight order have changed, a new order will be created", domains[0])
+		return acme.ExtendedOrder{}, nil, false
+	}
+
+	var privateKey crypto.PrivateKey
+	if len(state.PrivateKey) > 0 {
+		privateKey, err = certcrypto.ParsePEMPrivateKey(state.PrivateKey)
+		if err != nil {
+			log.Warnf("[%s] acme: unable to parse the private key of the in-flight order, a new order will be created: %v", domains[0], err)
+			return acme.ExtendedOrder{}, nil, false
+		}
+	}
+
+	if !matches(privateKey, state.CSR) {
+		log.Infof("[%s] acme: the in-flight order has been created for another private key or CSR, a new order will be created", domains[0])
+		return acme.ExtendedOrder{}, nil, false
+	}
+
+	order, err := c.core.Orders.GetWithContext(ctx, state.OrderURL)
+	if err != nil {
+		log.Infof("[%s] acme: unable to get the in-flight order %s, a new order will be created: %v", domains[0], state.OrderURL, err)
+		return acme.ExtendedOrder{}, nil, false
+	}
+
+	switch order.Status {
+	case acme.StatusPending, acme.StatusReady, acme.StatusProcessing, acme.StatusValid:
+	default:
+		log.Infof("[%s] acme: the in-flight order %s cannot be resumed (status=%s), a new order will be created", domains[0], state.OrderURL, order.Status)
+		return acme.ExtendedOrder{}, nil, false
+	}
+
+	order.Location = state.OrderURL
+
+	log.Infof("[%s] acme: Resuming the order %s (status=%s)", domains[0], order.Location, order.Status)
+
+	return order, privateKey, true
+}
+
+// saveOrder stores the state of an in-flight order.
+func (c *Certifier) saveOrder(domains []string, order acme.ExtendedOrder, privateKey crypto.PrivateKey, csr []byte) {
+	store := c.options.OrderStore
+	if store == nil {
+		return
+	}
+
+	state := &OrderState{
+		OrderURL:          order.Location,
+		AuthorizationURLs: order.Authorizations,
+		Domains:           domains,
+		CSR:               csr,
+	}
+
+	if privateKey != nil {
+		state.PrivateKey = certcrypto.PEMEncode(privateKey)
+	}
+
+	err := store.SaveOrder(domains[0], state)
+	if err != nil {
+		log.Warnf("[%s] acme: unable to save the in-flight order: %v", domains[0], err)
+	}
+}
+
+// deleteOrder removes the state of an in-flight order, and reports whether the state has been removed.
+func (c *Certifier) deleteOrder(domains []string) bool {
+	store := c.options.OrderStore
+	if store == nil {
+		return false
+	}
+
+	err := store.DeleteOrder(domains[0])
+	if err != nil {
+		log.Warnf("[%s] acme: unable to delete the in-flight order: %v", domains[0], err)
+		return false
+	}
+
+	return true
+}
+
+// matchPrivateKey returns an orderMatcher for a request with a private key (optional):
+// the order must have been created with the same private key, or with a known private key if the request has none.
+func matchPrivateKey(requestKey crypto.PrivateKey) orderMatcher {
+	return func(privateKey crypto.PrivateKey, _ []byte) bool {
+		if privateKey == nil {
+			// The certificate would not match a new private key.
+			return false
+		}
+
+		return requestKey == nil || samePublicKey(publicKey(requestKey), publicKey(privateKey))
+	}
+}
+
+// matchCSR returns an orderMatcher for a request with a CSR: the order must have been created for the same CSR.
+func matchCSR(requestCSR []byte) orderMatcher {
+	return func(_ crypto.PrivateKey, csr []byte) bool {
+		return bytes.Equal(csr, requestCSR)
+	}
+}
+
+func publicKey(privateKey crypto.PrivateKey) crypto.PublicKey {
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil
+	}
+
+	return signer.Public()
+}
+
+func samePublicKey(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(x crypto.PublicKey) bool })
+
+	return ok && key.Equal(b)
+}
+
+func sameDomains(a, b []string) bool {
+	a = slices.Clone(a)
+	b = slices.Clone(b)
+
+	slices.Sort(a)
+	slices.Sort(b)
+
+	return slices.Equal(a, b)
+}
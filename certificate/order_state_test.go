@@ -0,0 +1,273 @@
+package certificate
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertifier_Obtain_resumeOrder(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	certKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	certPEM, err := certcrypto.GeneratePemCert(certKey, "acme.wtf", nil)
+	require.NoError(t, err)
+
+	mux.HandleFunc("/newOrder", func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("unexpected order creation")
+		http.Error(w, "unexpected order creation", http.StatusBadRequest)
+	})
+
+	mux.HandleFunc("/order/1", func(w http.ResponseWriter, _ *http.Request) {
+		err := tester.WriteJSONResponse(w, acme.Order{
+			Status:      acme.StatusValid,
+			Identifiers: []acme.Identifier{{Type: "dns", Value: "acme.wtf"}},
+			Finalize:    apiURL + "/order/1/finalize",
+			Certificate: apiURL + "/certificate/1",
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	mux.HandleFunc("/order/1/finalize", func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("unexpected order finalization")
+		http.Error(w, "unexpected order finalization", http.StatusBadRequest)
+	})
+
+	mux.HandleFunc("/certificate/1", func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write(certPEM)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	store := &orderStoreMock{states: map[string]*OrderState{
+		"acme.wtf": {
+			OrderURL:   apiURL + "/order/1",
+			Domains:    []string{"acme.wtf"},
+			PrivateKey: certcrypto.PEMEncode(certKey),
+		},
+	}}
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048, OrderStore: store})
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"acme.wtf"}, Bundle: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, apiURL+"/certificate/1", certRes.CertURL)
+	assert.Equal(t, certPEM, certRes.Certificate)
+	assert.Equal(t, certcrypto.PEMEncode(certKey), certRes.PrivateKey)
+
+	assert.Empty(t, store.states)
+}
+
+func TestCertifier_resumeOrder_mismatch(t *testing.T) {
+	certKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	csr, err := certcrypto.GenerateCSR(certKey, "acme.wtf", nil, false)
+	require.NoError(t, err)
+
+	otherCSR, err := certcrypto.GenerateCSR(otherKey, "acme.wtf", nil, false)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		state    *OrderState
+		matches  orderMatcher
+		expected bool
+	}{
+		{
+			desc:     "same private key",
+			state:    &OrderState{PrivateKey: certcrypto.PEMEncode(certKey)},
+			matches:  matchPrivateKey(certKey),
+			expected: true,
+		},
+		{
+			desc:     "private key generated for the order",
+			state:    &OrderState{PrivateKey: certcrypto.PEMEncode(certKey)},
+			matches:  matchPrivateKey(nil),
+			expected: true,
+		},
+		{
+			desc:    "other private key",
+			state:   &OrderState{PrivateKey: certcrypto.PEMEncode(certKey)},
+			matches: matchPrivateKey(otherKey),
+		},
+		{
+			desc:    "unknown private key",
+			state:   &OrderState{},
+			matches: matchPrivateKey(nil),
+		},
+		{
+			desc:     "same CSR",
+			state:    &OrderState{CSR: csr},
+			matches:  matchCSR(csr),
+			expected: true,
+		},
+		{
+			desc:    "other CSR",
+			state:   &OrderState{CSR: csr},
+			matches: matchCSR(otherCSR),
+		},
+		{
+			desc:    "unknown CSR",
+			state:   &OrderState{},
+			matches: matchCSR(csr),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			mux, apiURL := tester.SetupFakeAPI(t)
+
+			mux.HandleFunc("/order/1", func(w http.ResponseWriter, _ *http.Request) {
+				err := tester.WriteJSONResponse(w, acme.Order{Status: acme.StatusPending})
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+			})
+
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			require.NoError(t, err, "Could not generate test key")
+
+			core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+			require.NoError(t, err)
+
+			state := *test.state
+			state.OrderURL = apiURL + "/order/1"
+			state.Domains = []string{"acme.wtf"}
+
+			store := &orderStoreMock{states: map[string]*OrderState{"acme.wtf": &state}}
+
+			certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048, OrderStore: store})
+
+			_, _, resumed := certifier.resumeOrder(t.Context(), []string{"acme.wtf"}, test.matches)
+			assert.Equal(t, test.expected, resumed)
+		})
+	}
+}
+
+func TestCertifier_Obtain_resumeOrder_certificateMismatch(t *testing.T) {
+	mux, apiURL := tester.SetupFakeAPI(t)
+
+	var newOrder bool
+
+	mux.HandleFunc("/newOrder", func(w http.ResponseWriter, _ *http.Request) {
+		newOrder = true
+		http.Error(w, "stop", http.StatusBadRequest)
+	})
+
+	mux.HandleFunc("/order/1", func(w http.ResponseWriter, _ *http.Request) {
+		err := tester.WriteJSONResponse(w, acme.Order{
+			Status:      acme.StatusValid,
+			Identifiers: []acme.Identifier{{Type: "dns", Value: "acme.wtf"}},
+			Finalize:    apiURL + "/order/1/finalize",
+			Certificate: apiURL + "/certificate/1",
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	// The certificate has been issued for another private key.
+	mux.HandleFunc("/certificate/1", func(w http.ResponseWriter, _ *http.Request) {
+		_, err := w.Write([]byte(certResponseMock))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	certKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, "Could not generate test key")
+
+	store := &orderStoreMock{states: map[string]*OrderState{
+		"acme.wtf": {
+			OrderURL:   apiURL + "/order/1",
+			Domains:    []string{"acme.wtf"},
+			PrivateKey: certcrypto.PEMEncode(certKey),
+		},
+	}}
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048, OrderStore: store})
+
+	_, err = certifier.Obtain(ObtainRequest{Domains: []string{"acme.wtf"}, Bundle: true})
+	require.Error(t, err)
+
+	// The wrong certificate is not returned: a new order is created.
+	assert.True(t, newOrder)
+}
+
+func TestCertifier_resumeOrder_domainsChanged(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "Could not generate test key")
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", key)
+	require.NoError(t, err)
+
+	store := &orderStoreMock{states: map[string]*OrderState{
+		"example.com": {
+			OrderURL: apiURL + "/order/1",
+			Domains:  []string{"example.com"},
+		},
+	}}
+
+	certifier := NewCertifier(core, &resolverMock{}, CertifierOptions{KeyType: certcrypto.RSA2048, OrderStore: store})
+
+	_, _, resumed := certifier.resumeOrder(t.Context(), []string{"example.com", "www.example.com"}, func(crypto.PrivateKey, []byte) bool { return true })
+	assert.False(t, resumed)
+}
+
+type orderStoreMock struct {
+	states map[string]*OrderState
+}
+
+func (s *orderStoreMock) LoadOrder(domain string) (*OrderState, error) {
+	return s.states[domain], nil
+}
+
+func (s *orderStoreMock) SaveOrder(domain string, state *OrderState) error {
+	s.states[domain] = state
+	return nil
+}
+
+func (s *orderStoreMock) DeleteOrder(domain string) error {
+	delete(s.states, domain)
+	return nil
+}
@@ -1,6 +1,7 @@
 package certificate
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/asn1"
 	"encoding/base64"
@@ -35,6 +36,15 @@ type RenewalInfoResponse struct {
 //
 // - (4.1-11. Getting Renewal Information) https://www.rfc-editor.org/rfc/rfc9773.html
 func (r *RenewalInfoResponse) ShouldRenewAt(now time.Time, willingToSleep time.Duration) *time.Time {
+	return r.ShouldRenewAtWithNextWake(now, willingToSleep, time.Time{})
+}
+
+// ShouldRenewAtWithNextWake determines the optimal renewal time like ShouldRenewAt,
+// and attempts renewal immediately if the selected time is before the next time that the client would wake up normally (nextWake).
+// A zero nextWake means that the client has no normal wake time.
+//
+// - (4.1-11. Getting Renewal Information) https://www.rfc-editor.org/rfc/rfc9773.html
+func (r *RenewalInfoResponse) ShouldRenewAtWithNextWake(now time.Time, willingToSleep time.Duration, nextWake time.Time) *time.Time {
 	// Explicitly convert all times to UTC.
 	now = now.UTC()
 	start := r.SuggestedWindow.Start.UTC()
@@ -58,7 +68,10 @@ func (r *RenewalInfoResponse) ShouldRenewAt(now time.Time, willingToSleep time.D
 		return &rt
 	}
 
-	// TODO: Otherwise, if the selected time is before the next time that the client would wake up normally, attempt renewal immediately.
+	// Otherwise, if the selected time is before the next time that the client would wake up normally, attempt renewal immediately.
+	if !nextWake.IsZero() && rt.Before(nextWake.UTC()) {
+		return &now
+	}
 
 	// Otherwise, sleep until the next normal wake time, re-check ARI, and return to Step 1.
 	return nil
@@ -73,12 +86,18 @@ func (r *RenewalInfoResponse) ShouldRenewAt(now time.Time, willingToSleep time.D
 //
 // https://www.rfc-editor.org/rfc/rfc9773.html
 func (c *Certifier) GetRenewalInfo(req RenewalInfoRequest) (*RenewalInfoResponse, error) {
+	return c.GetRenewalInfoWithContext(context.Background(), req)
+}
+
+// GetRenewalInfoWithContext sends a request to the ACME server's renewalInfo endpoint to obtain a suggested renewal window.
+// See GetRenewalInfo.
+func (c *Certifier) GetRenewalInfoWithContext(ctx context.Context, req RenewalInfoRequest) (*RenewalInfoResponse, error) {
 	certID, err := MakeARICertID(req.Cert)
 	if err != nil {
 		return nil, fmt.Errorf("error making certID: %w", err)
 	}
 
-	resp, err := c.core.Certificates.GetRenewalInfo(certID)
+	resp, err := c.core.Certificates.GetRenewalInfoWithContext(ctx, certID)
 	if err != nil {
 		return nil, err
 	}
@@ -100,6 +119,19 @@ func (c *Certifier) GetRenewalInfo(req RenewalInfoRequest) (*RenewalInfoResponse
 	return &info, nil
 }
 
+// RenewalDueDate returns the date after which a certificate should be renewed, based on its lifetime:
+// when 1/3rd of the lifetime is left, or 1/2 of the lifetime for short-lived certificates (10 days or less).
+func RenewalDueDate(cert *x509.Certificate) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+
+	var divisor int64 = 3
+	if lifetime.Round(24*time.Hour).Hours()/24.0 <= 10 {
+		divisor = 2
+	}
+
+	return cert.NotAfter.Add(-1 * time.Duration(lifetime.Nanoseconds()/divisor))
+}
+
 // MakeARICertID constructs a certificate identifier as described in RFC 9773, section 4.1.
 func MakeARICertID(leaf *x509.Certificate) (string, error) {
 	if leaf == nil {
@@ -0,0 +1,663 @@
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// DefaultRenewalCheckInterval the default interval between two checks of a certificate.
+const DefaultRenewalCheckInterval = 6 * time.Hour
+
+// renewalRetryDelay the delay before retrying a failed renewal, if the server doesn't provide one.
+const renewalRetryDelay = time.Hour
+
+// DefaultExpiryWarning the default remaining lifetime of a certificate below which an EventExpiring is sent.
+const DefaultExpiryWarning = 7 * 24 * time.Hour
+
+// RenewalManagerOptions options used by RenewalManager.
+type RenewalManagerOptions struct {
+	// CheckInterval the interval between two checks of a certificate, it's the "normal wake time" of the manager
+	// (default: DefaultRenewalCheckInterval).
+	// The renewalInfo endpoint is polled with the interval recommended by the server (Retry-After), if any.
+	CheckInterval time.Duration
+
+	// WillingToSleep the maximum duration the manager is willing to wait for a renewal time suggested by ARI
+	// (default: CheckInterval).
+	// If the suggested time is later, but before the next check, the certificate is renewed immediately.
+	WillingToSleep time.Duration
+
+	// DisableARI disables the renewalInfo endpoint (RFC9773),
+	// the renewal time is only based on the lifetime of the certificates.
+	DisableARI bool
+
+	// ReuseKey reuses the private key of the certificates.
+	ReuseKey bool
+
+	// RenewOptions options used to renew the certificates.
+	RenewOptions *RenewOptions
+
+	// OnRenewed is called with the previous and the renewed certificates after a renewal.
+	OnRenewed func(previous, renewed *Resource)
+
+	// OnGroupRenewed is called with the previous and the renewed parts of a split certificate (AddGroup) after a renewal.
+	// If a part cannot be renewed, it's called with the parts already renewed and the error:
+	// the parts are kept, and the renewal of the split certificate is retried later.
+	OnGroupRenewed func(name string, previous, renewed []*Resource, err error)
+
+	// Lock is called before the renewal of a certificate, to serialize the processes working on the certificate.
+	// The returned function is called once the renewal is done, after OnRenewed (or OnGroupRenewed).
+	// If the lock cannot be taken, the renewal is retried later.
+	Lock func(name string) (unlock func(), err error)
+
+	// OnError is called when a certificate cannot be checked or renewed.
+	OnError func(name string, err error)
+
+	// ExpiryWarning the remaining lifetime of a certificate below which an EventExpiring is sent
+	// to the Notifier of the Certifier (default: DefaultExpiryWarning).
+	// The event is sent once for each certificate.
+	ExpiryWarning time.Duration
+}
+
+// RenewalManager renews certificates when needed.
+//
+// The renewal time is provided by the renewalInfo endpoint (RFC9773) if the server supports it,
+// otherwise it's based on the lifetime of the certificates (see RenewalDueDate).
+//
+// The certificates are identified by their Resource.Domain.
+// The parts of a split certificate (see Certifier.ObtainSplit) are added together (AddGroup), and renewed together.
+//
+// The certificates are checked and renewed one at a time, synchronously:
+// a long renewal (e.g. a slow DNS propagation) delays the checks of the other certificates.
+type RenewalManager struct {
+	certifier *Certifier
+	options   RenewalManagerOptions
+
+	mu      sync.Mutex
+	entries map[string]*renewalEntry
+	noARI   bool
+
+	wakeup chan struct{}
+}
+
+type renewalEntry struct {
+	// parts the certificate, or the parts of a split certificate.
+	parts []*renewalPart
+
+	// split true for a split certificate (AddGroup).
+	split bool
+
+	// maxIdentifiers the maximum number of identifiers of the parts of a split certificate.
+	maxIdentifiers int
+
+	// next the time of the next check (or renewal).
+	next time.Time
+
+	// renewAt the renewal time selected from ARI, if any.
+	renewAt *time.Time
+}
+
+type renewalPart struct {
+	resource *Resource
+	cert     *x509.Certificate
+
+	// window the last renewal window suggested by ARI, if any.
+	window *acme.Window
+
+	// expiring true if the EventExpiring has been sent.
+	expiring bool
+}
+
+func newRenewalPart(certRes *Resource) (*renewalPart, error) {
+	if certRes == nil || certRes.Domain == "" {
+		return nil, errors.New("renewal manager: the certificate must have a name (Resource.Domain)")
+	}
+
+	certificates, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("renewal manager: [%s] %w", certRes.Domain, err)
+	}
+
+	if certificates[0].IsCA {
+		return nil, fmt.Errorf("renewal manager: [%s] certificate bundle starts with a CA certificate", certRes.Domain)
+	}
+
+	return &renewalPart{resource: certRes, cert: certificates[0]}, nil
+}
+
+// NewRenewalManager creates a new RenewalManager.
+func NewRenewalManager(certifier *Certifier, options *RenewalManagerOptions) *RenewalManager {
+	m := &RenewalManager{
+		certifier: certifier,
+		entries:   make(map[string]*renewalEntry),
+		wakeup:    make(chan struct{}, 1),
+	}
+
+	if options != nil {
+		m.options = *options
+	}
+
+	if m.options.CheckInterval <= 0 {
+		m.options.CheckInterval = DefaultRenewalCheckInterval
+	}
+
+	if m.options.WillingToSleep <= 0 {
+		m.options.WillingToSleep = m.options.CheckInterval
+	}
+
+	if m.options.ExpiryWarning <= 0 {
+		m.options.ExpiryWarning = DefaultExpiryWarning
+	}
+
+	m.noARI = m.options.DisableARI
+
+	return m
+}
+
+// Add adds a certificate to the manager, or replaces the certificate with the same name.
+// The certificate is checked as soon as possible.
+func (m *RenewalManager) Add(certRes *Resource) error {
+	part, err := newRenewalPart(certRes)
+	if err != nil {
+		return err
+	}
+
+	m.add(certRes.Domain, &renewalEntry{parts: []*renewalPart{part}})
+
+	return nil
+}
+
+// AddGroup adds the parts of a split certificate (see Certifier.ObtainSplit) to the manager,
+// or replaces the certificate with the same name.
+// The name is the main domain of the split certificate (the name of its first part).
+//
+// The parts are checked together: all the parts are renewed (Certifier.ObtainSplit) if one of them needs to be renewed.
+// Each new part replaces the previous part with which it shares the most domains (SplitOptions.Replaces).
+func (m *RenewalManager) AddGroup(name string, maxIdentifiers int, parts []*Resource) error {
+	if name == "" || len(parts) == 0 {
+		return errors.New("renewal manager: the split certificate must have a name and parts")
+	}
+
+	entry := &renewalEntry{split: true, maxIdentifiers: maxIdentifiers}
+
+	for _, certRes := range parts {
+		part, err := newRenewalPart(certRes)
+		if err != nil {
+			return err
+		}
+
+		entry.parts = append(entry.parts, part)
+	}
+
+	m.add(name, entry)
+
+	return nil
+}
+
+func (m *RenewalManager) add(name string, entry *renewalEntry) {
+	m.mu.Lock()
+	m.entries[name] = entry
+	m.mu.Unlock()
+
+	m.notify()
+}
+
+// Remove removes a certificate from the manager.
+func (m *RenewalManager) Remove(name string) {
+	m.mu.Lock()
+	delete(m.entries, name)
+	m.mu.Unlock()
+
+	m.notify()
+}
+
+// Next returns the time of the next check (or renewal) of a certificate.
+// Returns false if the certificate is unknown.
+func (m *RenewalManager) Next(name string) (time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[name]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return entry.next, true
+}
+
+// Run checks and renews the certificates until the context is canceled.
+// The certificates are processed one at a time, in the goroutine of Run.
+func (m *RenewalManager) Run(ctx context.Context) error {
+	for {
+		name, next, ok := m.nextEntry()
+
+		// Without certificate, the manager waits for a new certificate.
+		timer := time.NewTimer(time.Until(next))
+		if !ok {
+			timer.Stop()
+		}
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+
+		case <-m.wakeup:
+			timer.Stop()
+
+		case <-timer.C:
+			m.process(ctx, name)
+		}
+	}
+}
+
+func (m *RenewalManager) notify() {
+	select {
+	case m.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// nextEntry returns the certificate with the earliest check time.
+func (m *RenewalManager) nextEntry() (string, time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var (
+		name  string
+		next  time.Time
+		found bool
+	)
+
+	for n, entry := range m.entries {
+		if !found || entry.next.Before(next) || (entry.next.Equal(next) && n < name) {
+			name, next, found = n, entry.next, true
+		}
+	}
+
+	return name, next, found
+}
+
+func (m *RenewalManager) process(ctx context.Context, name string) {
+	m.mu.Lock()
+	entry, ok := m.entries[name]
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+
+	renew := entry.renewAt != nil && !entry.renewAt.After(now)
+	if !renew {
+		renew = m.check(ctx, name, entry, now)
+	}
+
+	if renew {
+		m.renew(ctx, name, entry)
+	}
+}
+
+// check schedules the next check of a certificate, and returns true if the certificate must be renewed immediately.
+// The parts of a split certificate are checked together: the earliest check (or renewal) is scheduled.
+func (m *RenewalManager) check(ctx context.Context, name string, entry *renewalEntry, now time.Time) bool {
+	entry.renewAt = nil
+
+	var (
+		renew bool
+		next  time.Time
+	)
+
+	for _, part := range entry.parts {
+		partRenew, partNext, renewAt := m.checkPart(ctx, part, now)
+		if partRenew {
+			renew = true
+			continue
+		}
+
+		if next.IsZero() || partNext.Before(next) {
+			next = partNext
+		}
+
+		if renewAt != nil && (entry.renewAt == nil || renewAt.Before(*entry.renewAt)) {
+			entry.renewAt = renewAt
+		}
+	}
+
+	if renew {
+		entry.renewAt = nil
+		return true
+	}
+
+	// The renewal time is replaced by an earlier check of another part.
+	if entry.renewAt != nil && entry.renewAt.After(next) {
+		entry.renewAt = nil
+	}
+
+	m.schedule(name, entry, next)
+
+	return false
+}
+
+// checkPart returns true if a certificate must be renewed immediately,
+// otherwise the time of its next check (or renewal), and the renewal time selected from ARI, if any.
+func (m *RenewalManager) checkPart(ctx context.Context, part *renewalPart, now time.Time) (bool, time.Time, *time.Time) {
+	name := part.resource.Domain
+
+	if !part.expiring && part.cert.NotAfter.Sub(now) < m.options.ExpiryWarning {
+		part.expiring = true
+
+		m.certifier.notify(ctx, NewEvent(EventExpiring, name, part.cert))
+	}
+
+	if m.useARI() {
+		info, err := m.certifier.GetRenewalInfoWithContext(ctx, RenewalInfoRequest{Cert: part.cert})
+
+		switch {
+		case errors.Is(err, api.ErrNoARI):
+			log.Infof("[%s] acme: %v, the renewal time is based on the certificate lifetime", name, err)
+
+			m.mu.Lock()
+			m.noARI = true
+			m.mu.Unlock()
+
+		case err != nil:
+			m.onError(name, fmt.Errorf("calling renewal info endpoint: %w", err))
+
+		default:
+			m.checkWindow(ctx, name, part, info)
+
+			return m.checkARI(name, info, now)
+		}
+	}
+
+	dueDate := RenewalDueDate(part.cert)
+	if !dueDate.After(now) {
+		return true, time.Time{}, nil
+	}
+
+	return false, minTime(dueDate, now.Add(m.options.CheckInterval)), nil
+}
+
+func (m *RenewalManager) checkARI(name string, info *RenewalInfoResponse, now time.Time) (bool, time.Time, *time.Time) {
+	nextWake := now.Add(m.options.CheckInterval)
+	if info.RetryAfter > 0 {
+		nextWake = now.Add(info.RetryAfter)
+	}
+
+	renewAt := info.ShouldRenewAtWithNextWake(now, m.options.WillingToSleep, nextWake)
+	if renewAt == nil {
+		log.Infof("[%s] acme: renewalInfo endpoint indicates that renewal is not needed", name)
+
+		return false, nextWake, nil
+	}
+
+	if info.ExplanationURL != "" {
+		log.Infof("[%s] acme: renewalInfo endpoint provided an explanation: %s", name, info.ExplanationURL)
+	}
+
+	if !renewAt.After(now) {
+		return true, time.Time{}, nil
+	}
+
+	log.Infof("[%s] acme: renewalInfo endpoint indicates a renewal at %s", name, renewAt)
+
+	return false, *renewAt, renewAt
+}
+
+// checkWindow sends an EventRenewalWindowChanged if the suggested renewal window has changed since the previous check.
+func (m *RenewalManager) checkWindow(ctx context.Context, name string, part *renewalPart, info *RenewalInfoResponse) {
+	window := info.SuggestedWindow
+
+	previous := part.window
+	part.window = &window
+
+	if previous == nil || (previous.Start.Equal(window.Start) && previous.End.Equal(window.End)) {
+		return
+	}
+
+	log.Infof("[%s] acme: the renewal window has changed: %s - %s", name, window.Start, window.End)
+
+	event := NewEvent(EventRenewalWindowChanged, name, part.cert)
+	event.RenewalWindow = &window
+
+	m.certifier.notify(ctx, event)
+}
+
+func (m *RenewalManager) renew(ctx context.Context, name string, entry *renewalEntry) {
+	if m.options.Lock != nil {
+		unlock, err := m.options.Lock(name)
+		if err != nil {
+			m.retryLater(name, entry, fmt.Errorf("lock: %w", err))
+			return
+		}
+
+		// The lock is held until the renewed certificate is saved (OnRenewed, OnGroupRenewed).
+		defer unlock()
+	}
+
+	if entry.split {
+		m.renewGroup(ctx, name, entry)
+		return
+	}
+
+	part := entry.parts[0]
+
+	options := m.renewOptions()
+
+	if m.useARI() {
+		certID, err := MakeARICertID(part.cert)
+		if err == nil {
+			options.ReplacesCertID = certID
+		}
+	}
+
+	certRes := *part.resource
+	if !m.options.ReuseKey {
+		certRes.PrivateKey = nil
+	}
+
+	renewed, err := m.certifier.RenewWithContext(ctx, certRes, &options)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+
+		m.retryLater(name, entry, err)
+
+		return
+	}
+
+	renewed.Domain = name
+
+	renewedPart, err := newRenewalPart(renewed)
+	if err != nil {
+		m.onError(name, err)
+		return
+	}
+
+	m.replace(name, entry, &renewalEntry{parts: []*renewalPart{renewedPart}, next: time.Now()})
+
+	if m.options.OnRenewed != nil {
+		m.options.OnRenewed(part.resource, renewed)
+	}
+}
+
+// renewGroup renews all the parts of a split certificate, as a single certificate.
+func (m *RenewalManager) renewGroup(ctx context.Context, name string, entry *renewalEntry) {
+	options := m.renewOptions()
+
+	request := ObtainRequest{
+		// The name of the split certificate stays the first domain: it's the name of the first part.
+		Domains:                        []string{name},
+		MustStaple:                     options.MustStaple,
+		NotBefore:                      options.NotBefore,
+		NotAfter:                       options.NotAfter,
+		Bundle:                         options.Bundle,
+		PreferredChain:                 options.PreferredChain,
+		EmailAddresses:                 options.EmailAddresses,
+		Profile:                        options.Profile,
+		AlwaysDeactivateAuthorizations: options.AlwaysDeactivateAuthorizations,
+	}
+
+	var (
+		previous []*Resource
+		replaces []SplitPart
+	)
+
+	useARI := m.useARI()
+
+	for _, part := range entry.parts {
+		previous = append(previous, part.resource)
+
+		domains := certcrypto.ExtractDomains(part.cert)
+
+		for _, domain := range domains {
+			if !slices.Contains(request.Domains, domain) {
+				request.Domains = append(request.Domains, domain)
+			}
+		}
+
+		if !useARI {
+			continue
+		}
+
+		certID, err := MakeARICertID(part.cert)
+		if err == nil {
+			replaces = append(replaces, SplitPart{Name: part.resource.Domain, Domains: domains, CertID: certID})
+		}
+	}
+
+	// The parts share the private key of the first part.
+	if m.options.ReuseKey && entry.parts[0].resource.PrivateKey != nil {
+		privateKey, err := certcrypto.ParsePEMPrivateKey(entry.parts[0].resource.PrivateKey)
+		if err != nil {
+			m.onError(name, err)
+			return
+		}
+
+		request.PrivateKey = privateKey
+	}
+
+	log.Infof("[%s] acme: Trying renewal of %d certificates", name, len(entry.parts))
+
+	renewed, err := m.certifier.ObtainSplitWithContext(ctx, request, &SplitOptions{
+		MaxIdentifiers: entry.maxIdentifiers,
+		Replaces:       replaces,
+	})
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	parts := make([]*renewalPart, 0, len(renewed))
+
+	for _, resource := range renewed {
+		part, errP := newRenewalPart(resource)
+		if errP != nil {
+			m.onError(name, errP)
+			return
+		}
+
+		parts = append(parts, part)
+	}
+
+	if err != nil {
+		// The parts already renewed are kept, with the previous parts which are not renewed yet.
+		for _, part := range entry.parts {
+			if !slices.ContainsFunc(renewed, func(r *Resource) bool { return r.Domain == part.resource.Domain }) {
+				parts = append(parts, part)
+			}
+		}
+
+		if len(renewed) > 0 {
+			partial := &renewalEntry{parts: parts, split: true, maxIdentifiers: entry.maxIdentifiers}
+
+			m.replace(name, entry, partial)
+
+			entry = partial
+		}
+
+		m.retryLater(name, entry, err)
+	} else {
+		m.replace(name, entry, &renewalEntry{parts: parts, split: true, maxIdentifiers: entry.maxIdentifiers, next: time.Now()})
+	}
+
+	if len(renewed) > 0 && m.options.OnGroupRenewed != nil {
+		m.options.OnGroupRenewed(name, previous, renewed, err)
+	}
+}
+
+func (m *RenewalManager) renewOptions() RenewOptions {
+	if m.options.RenewOptions != nil {
+		return *m.options.RenewOptions
+	}
+
+	return RenewOptions{}
+}
+
+// replace replaces the entry of a renewed certificate, unless the certificate has been replaced or removed during the renewal.
+func (m *RenewalManager) replace(name string, entry, renewed *renewalEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.entries[name] == entry {
+		m.entries[name] = renewed
+	}
+}
+
+// retryLater schedules a new renewal attempt after a failure.
+func (m *RenewalManager) retryLater(name string, entry *renewalEntry, err error) {
+	delay := renewalRetryDelay
+	if retryAfter, ok := api.GetRetryAfter(err); ok {
+		delay = retryAfter
+	}
+
+	m.onError(name, fmt.Errorf("renewal failed, next attempt in %s: %w", delay, err))
+
+	entry.renewAt = nil
+
+	m.schedule(name, entry, time.Now().Add(delay))
+}
+
+func (m *RenewalManager) schedule(name string, entry *renewalEntry, next time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// The certificate can be replaced or removed during the check.
+	if m.entries[name] == entry {
+		entry.next = next
+	}
+}
+
+func (m *RenewalManager) useARI() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return !m.noARI
+}
+
+func (m *RenewalManager) onError(name string, err error) {
+	log.Warnf("[%s] %v", name, err)
+
+	if m.options.OnError != nil {
+		m.options.OnError(name, err)
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,276 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester/acmeserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewalManager_ari(t *testing.T) {
+	server, certifier := setupRenewalManagerTest(t)
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}, Bundle: true})
+	require.NoError(t, err)
+
+	certRes.Domain = "example"
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	require.NoError(t, err)
+
+	renewed := make(chan *Resource, 1)
+
+	var (
+		mu    sync.Mutex
+		steps []string
+	)
+
+	addStep := func(step string) {
+		mu.Lock()
+		steps = append(steps, step)
+		mu.Unlock()
+	}
+
+	manager := NewRenewalManager(certifier, &RenewalManagerOptions{
+		CheckInterval: time.Hour,
+		OnRenewed: func(previous, res *Resource) {
+			assert.Equal(t, certRes, previous)
+
+			addStep("renewed " + previous.Domain)
+
+			renewed <- res
+		},
+		Lock: func(name string) (func(), error) {
+			addStep("lock " + name)
+
+			return func() { addStep("unlock " + name) }, nil
+		},
+	})
+
+	err = manager.Add(certRes)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	go func() { _ = manager.Run(ctx) }()
+
+	// The certificate is checked but not renewed: the renewal window is later.
+	require.Eventually(t, func() bool {
+		next, ok := manager.Next("example")
+		return ok && !next.IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Empty(t, renewed)
+
+	require.True(t, server.SetRenewalWindow(cert, time.Now().Add(-time.Hour), time.Now().Add(-time.Minute)))
+
+	// The server asks to poll the renewalInfo endpoint every 6 hours: the certificate is added again to be checked immediately.
+	err = manager.Add(certRes)
+	require.NoError(t, err)
+
+	select {
+	case res := <-renewed:
+		assert.Equal(t, "example", res.Domain)
+
+		renewedCert, err := certcrypto.ParsePEMCertificate(res.Certificate)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, cert.SerialNumber, renewedCert.SerialNumber)
+		assert.Equal(t, cert.DNSNames, renewedCert.DNSNames)
+
+	case <-time.After(10 * time.Second):
+		t.Fatal("the certificate has not been renewed")
+	}
+
+	// The lock is held during the renewal, until the renewed certificate is handled.
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(steps) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, []string{"lock example", "renewed example", "unlock example"}, steps)
+}
+
+func TestRenewalManager_AddGroup(t *testing.T) {
+	server, certifier := setupRenewalManagerTest(t)
+
+	parts, err := certifier.ObtainSplit(ObtainRequest{Domains: []string{"example.com", "example.org"}, Bundle: true}, &SplitOptions{MaxIdentifiers: 1})
+	require.NoError(t, err)
+	require.Len(t, parts, 2)
+
+	renewed := make(chan []*Resource, 1)
+
+	manager := NewRenewalManager(certifier, &RenewalManagerOptions{
+		CheckInterval: time.Hour,
+		OnRenewed: func(_, _ *Resource) {
+			t.Error("the parts must be renewed together")
+		},
+		OnGroupRenewed: func(name string, previous, res []*Resource, err error) {
+			assert.Equal(t, "example.com", name)
+			assert.Equal(t, parts, previous)
+			assert.NoError(t, err)
+
+			renewed <- res
+		},
+	})
+
+	err = manager.AddGroup("example.com", 1, parts)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	go func() { _ = manager.Run(ctx) }()
+
+	// The split certificate is a single certificate.
+	require.Eventually(t, func() bool {
+		next, ok := manager.Next("example.com")
+		return ok && !next.IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, ok := manager.Next("example.com-part2")
+	assert.False(t, ok)
+
+	assert.Empty(t, renewed)
+
+	// Only the second part needs to be renewed.
+	cert, err := certcrypto.ParsePEMCertificate(parts[1].Certificate)
+	require.NoError(t, err)
+
+	require.True(t, server.SetRenewalWindow(cert, time.Now().Add(-time.Hour), time.Now().Add(-time.Minute)))
+
+	err = manager.AddGroup("example.com", 1, parts)
+	require.NoError(t, err)
+
+	select {
+	case res := <-renewed:
+		require.Len(t, res, 2)
+
+		for i, part := range res {
+			assert.Equal(t, parts[i].Domain, part.Domain)
+
+			previousCert, err := certcrypto.ParsePEMCertificate(parts[i].Certificate)
+			require.NoError(t, err)
+
+			renewedCert, err := certcrypto.ParsePEMCertificate(part.Certificate)
+			require.NoError(t, err)
+
+			assert.NotEqual(t, previousCert.SerialNumber, renewedCert.SerialNumber)
+			assert.Equal(t, previousCert.DNSNames, renewedCert.DNSNames)
+		}
+
+	case <-time.After(10 * time.Second):
+		t.Fatal("the split certificate has not been renewed")
+	}
+}
+
+func TestRenewalManager_lifetime(t *testing.T) {
+	_, certifier := setupRenewalManagerTest(t)
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}, Bundle: true})
+	require.NoError(t, err)
+
+	manager := NewRenewalManager(certifier, &RenewalManagerOptions{
+		CheckInterval: time.Hour,
+		DisableARI:    true,
+		OnRenewed: func(_, _ *Resource) {
+			t.Error("the certificate must not be renewed")
+		},
+	})
+
+	err = manager.Add(certRes)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	go func() { _ = manager.Run(ctx) }()
+
+	// The due date is after the next check.
+	require.Eventually(t, func() bool {
+		next, ok := manager.Next("example.com")
+		return ok && !next.IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	next, _ := manager.Next("example.com")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), next, time.Minute)
+}
+
+func TestRenewalManager_expiring(t *testing.T) {
+	_, certifier := setupRenewalManagerTest(t)
+
+	certRes, err := certifier.Obtain(ObtainRequest{Domains: []string{"example.com"}, Bundle: true})
+	require.NoError(t, err)
+
+	events := make(chan *Event, 10)
+
+	certifier.options.Notifier = NotifierFunc(func(_ context.Context, event *Event) error {
+		events <- event
+		return nil
+	})
+
+	manager := NewRenewalManager(certifier, &RenewalManagerOptions{
+		CheckInterval: time.Hour,
+		DisableARI:    true,
+		// Longer than the lifetime of the certificate, but the renewal is not due.
+		ExpiryWarning: 10 * 365 * 24 * time.Hour,
+	})
+
+	err = manager.Add(certRes)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	go func() { _ = manager.Run(ctx) }()
+
+	select {
+	case event := <-events:
+		assert.Equal(t, EventExpiring, event.Type)
+		assert.Equal(t, "example.com", event.Domain)
+		assert.Equal(t, []string{"example.com"}, event.Domains)
+		assert.NotEmpty(t, event.Serial)
+		assert.NotNil(t, event.NotAfter)
+
+	case <-time.After(10 * time.Second):
+		t.Fatal("no expiring event")
+	}
+}
+
+func TestRenewalManager_Add_noName(t *testing.T) {
+	manager := NewRenewalManager(&Certifier{}, nil)
+
+	err := manager.Add(&Resource{})
+	require.Error(t, err)
+}
+
+func setupRenewalManagerTest(t *testing.T) (*acmeserver.Server, *Certifier) {
+	t.Helper()
+
+	server, dirURL := acmeserver.Start(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", dirURL, "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	return server, NewCertifier(core, &validatingResolver{core: core}, CertifierOptions{KeyType: certcrypto.EC256})
+}
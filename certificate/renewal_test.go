@@ -201,3 +201,32 @@ func TestRenewalInfoResponse_ShouldRenew(t *testing.T) {
 		assert.Nil(t, rt)
 	})
 }
+
+func TestRenewalInfoResponse_ShouldRenewAtWithNextWake(t *testing.T) {
+	now := time.Now().UTC()
+
+	ri := RenewalInfoResponse{
+		RenewalInfoResponse: acme.RenewalInfoResponse{
+			SuggestedWindow: acme.Window{
+				Start: now.Add(1 * time.Hour),
+				End:   now.Add(2 * time.Hour),
+			},
+		},
+	}
+
+	t.Run("Window is before the next wake time", func(t *testing.T) {
+		rt := ri.ShouldRenewAtWithNextWake(now, 0, now.Add(3*time.Hour))
+		require.NotNil(t, rt)
+		assert.Equal(t, now, *rt)
+	})
+
+	t.Run("Window is after the next wake time", func(t *testing.T) {
+		rt := ri.ShouldRenewAtWithNextWake(now, 0, now.Add(30*time.Minute))
+		assert.Nil(t, rt)
+	})
+
+	t.Run("No next wake time", func(t *testing.T) {
+		rt := ri.ShouldRenewAtWithNextWake(now, 0, time.Time{})
+		assert.Nil(t, rt)
+	})
+}
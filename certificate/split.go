@@ -0,0 +1,208 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// DefaultMaxIdentifiers the default maximum number of identifiers of a certificate (Let's Encrypt limit).
+const DefaultMaxIdentifiers = 100
+
+// SplitOptions options used by Certifier.ObtainSplit.
+type SplitOptions struct {
+	// MaxIdentifiers is the maximum number of identifiers of a certificate (default: DefaultMaxIdentifiers).
+	MaxIdentifiers int
+
+	// Replaces the stored parts replaced by the new parts.
+	// Each new part replaces the stored part with which it shares the most domains:
+	// the domains can move from a part to another when the list of domains changes.
+	Replaces []SplitPart
+}
+
+// SplitPart a stored part of a split certificate.
+type SplitPart struct {
+	// Name the name of the part.
+	Name string
+
+	// Domains the domains of the certificate of the part.
+	Domains []string
+
+	// CertID the ARI CertID of the certificate of the part.
+	// - https://www.rfc-editor.org/rfc/rfc9773.html#section-5
+	CertID string
+}
+
+// ObtainSplit obtains the certificates for the domains of the request,
+// the domains are split into several orders if they exceed the maximum number of identifiers.
+//
+// The resources are named with SplitName: the first one is named after the first domain of the request.
+// If one part fails, the resources of the parts already obtained are returned with the error.
+func (c *Certifier) ObtainSplit(request ObtainRequest, options *SplitOptions) ([]*Resource, error) {
+	return c.ObtainSplitWithContext(context.Background(), request, options)
+}
+
+// ObtainSplitWithContext obtains the certificates for the domains of the request,
+// the domains are split into several orders if they exceed the maximum number of identifiers.
+//
+// The resources are named with SplitName: the first one is named after the first domain of the request.
+// If one part fails, the resources of the parts already obtained are returned with the error.
+func (c *Certifier) ObtainSplitWithContext(ctx context.Context, request ObtainRequest, options *SplitOptions) ([]*Resource, error) {
+	if len(request.Domains) == 0 {
+		return nil, errors.New("no domains to obtain a certificate for")
+	}
+
+	maxIdentifiers := DefaultMaxIdentifiers
+	if options != nil && options.MaxIdentifiers > 0 {
+		maxIdentifiers = options.MaxIdentifiers
+	}
+
+	parts, err := SplitDomains(request.Domains, maxIdentifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	// The parts are in the same zones, the DNS challenges can share the propagation wait.
+	ctx = dns01.WithSharedPropagationWait(ctx)
+
+	var replaces []string
+	if options != nil {
+		replaces = matchReplacedParts(parts, options.Replaces)
+	}
+
+	var resources []*Resource
+
+	for i, domains := range parts {
+		name := SplitName(request.Domains[0], i)
+
+		partRequest := request
+		partRequest.Domains = domains
+		partRequest.ReplacesCertID = ""
+
+		if replaces != nil {
+			partRequest.ReplacesCertID = replaces[i]
+		}
+
+		resource, err := c.ObtainWithContext(ctx, partRequest)
+		if err != nil {
+			return resources, fmt.Errorf("%s: %w", name, err)
+		}
+
+		resource.Domain = name
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// matchReplacedParts returns the CertID of the stored part replaced by each new part.
+// A new part replaces the stored part with which it shares the most domains,
+// a stored part is replaced at most once.
+func matchReplacedParts(parts [][]string, stored []SplitPart) []string {
+	replaces := make([]string, len(parts))
+
+	used := map[string]bool{}
+
+	for i, domains := range parts {
+		var (
+			best      string
+			bestCount int
+		)
+
+		for _, part := range stored {
+			if used[part.Name] || part.CertID == "" {
+				continue
+			}
+
+			count := 0
+
+			for _, domain := range part.Domains {
+				if slices.Contains(domains, domain) {
+					count++
+				}
+			}
+
+			if count > bestCount {
+				best, bestCount = part.Name, count
+				replaces[i] = part.CertID
+			}
+		}
+
+		if best != "" {
+			used[best] = true
+		}
+	}
+
+	return replaces
+}
+
+// SplitName returns the name of a part of a split certificate:
+// the main domain for the first part, the main domain followed by "-partN" for the next ones.
+func SplitName(mainDomain string, index int) string {
+	if index == 0 {
+		return mainDomain
+	}
+
+	return fmt.Sprintf("%s-part%d", mainDomain, index+1)
+}
+
+// SplitDomains partitions the domains into groups of at most maxIdentifiers domains.
+//
+// A domain and its wildcard (example.com and *.example.com) are always in the same group.
+// The first domain stays the first domain of the first group,
+// the other domains are sorted to always produce the same groups.
+func SplitDomains(domains []string, maxIdentifiers int) ([][]string, error) {
+	if maxIdentifiers < 1 {
+		return nil, fmt.Errorf("invalid maximum number of identifiers: %d", maxIdentifiers)
+	}
+
+	// Groups the domains with their wildcard.
+	var bases []string
+	units := map[string][]string{}
+
+	for _, domain := range domains {
+		base := strings.TrimPrefix(domain, "*.")
+
+		if slices.Contains(units[base], domain) {
+			continue
+		}
+
+		if _, ok := units[base]; !ok {
+			bases = append(bases, base)
+		}
+
+		units[base] = append(units[base], domain)
+	}
+
+	if len(bases) == 0 {
+		return nil, nil
+	}
+
+	slices.Sort(bases[1:])
+
+	var groups [][]string
+
+	var current []string
+
+	for _, base := range bases {
+		unit := units[base]
+
+		if len(unit) > maxIdentifiers {
+			return nil, fmt.Errorf("%s: %d identifiers cannot fit in a certificate of %d identifiers", base, len(unit), maxIdentifiers)
+		}
+
+		if len(current)+len(unit) > maxIdentifiers {
+			groups = append(groups, current)
+			current = nil
+		}
+
+		current = append(current, unit...)
+	}
+
+	return append(groups, current), nil
+}
@@ -0,0 +1,214 @@
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/platform/tester/acmeserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDomains(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		domains        []string
+		maxIdentifiers int
+		expected       [][]string
+	}{
+		{
+			desc:           "no split",
+			domains:        []string{"example.com", "b.example.com", "a.example.com"},
+			maxIdentifiers: 3,
+			expected:       [][]string{{"example.com", "a.example.com", "b.example.com"}},
+		},
+		{
+			desc:           "split",
+			domains:        []string{"example.com", "d.example.com", "c.example.com", "b.example.com", "a.example.com"},
+			maxIdentifiers: 2,
+			expected: [][]string{
+				{"example.com", "a.example.com"},
+				{"b.example.com", "c.example.com"},
+				{"d.example.com"},
+			},
+		},
+		{
+			desc:           "wildcard pairs",
+			domains:        []string{"example.com", "a.example.org", "*.example.org", "*.example.com", "example.org"},
+			maxIdentifiers: 3,
+			expected: [][]string{
+				{"example.com", "*.example.com", "a.example.org"},
+				{"*.example.org", "example.org"},
+			},
+		},
+		{
+			desc:           "wildcard first",
+			domains:        []string{"*.example.com", "b.example.com", "example.com"},
+			maxIdentifiers: 2,
+			expected: [][]string{
+				{"*.example.com", "example.com"},
+				{"b.example.com"},
+			},
+		},
+		{
+			desc:           "duplicates",
+			domains:        []string{"example.com", "a.example.com", "example.com", "a.example.com"},
+			maxIdentifiers: 1,
+			expected: [][]string{
+				{"example.com"},
+				{"a.example.com"},
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			groups, err := SplitDomains(test.domains, test.maxIdentifiers)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, groups)
+		})
+	}
+}
+
+func TestSplitDomains_error(t *testing.T) {
+	_, err := SplitDomains([]string{"example.com", "*.example.com"}, 1)
+	require.EqualError(t, err, "example.com: 2 identifiers cannot fit in a certificate of 1 identifiers")
+
+	_, err = SplitDomains([]string{"example.com"}, 0)
+	require.EqualError(t, err, "invalid maximum number of identifiers: 0")
+}
+
+func TestSplitName(t *testing.T) {
+	assert.Equal(t, "example.com", SplitName("example.com", 0))
+	assert.Equal(t, "example.com-part2", SplitName("example.com", 1))
+	assert.Equal(t, "*.example.com-part3", SplitName("*.example.com", 2))
+}
+
+func TestCertifier_ObtainSplit(t *testing.T) {
+	_, dirURL := acmeserver.Start(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", dirURL, "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &validatingResolver{core: core}, CertifierOptions{KeyType: certcrypto.EC256})
+
+	request := ObtainRequest{
+		Domains: []string{"example.com", "c.example.com", "b.example.com", "a.example.com"},
+		Bundle:  true,
+	}
+
+	resources, err := certifier.ObtainSplit(request, &SplitOptions{MaxIdentifiers: 3})
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	expected := map[string][]string{
+		"example.com":       {"example.com", "a.example.com", "b.example.com"},
+		"example.com-part2": {"c.example.com"},
+	}
+
+	for _, resource := range resources {
+		cert, err := certcrypto.ParsePEMCertificate(resource.Certificate)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected[resource.Domain], cert.DNSNames)
+	}
+}
+
+func TestCertifier_ObtainSplit_partialFailure(t *testing.T) {
+	_, dirURL := acmeserver.Start(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", dirURL, "", key)
+	require.NoError(t, err)
+
+	_, err = core.Accounts.New(acme.Account{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	certifier := NewCertifier(core, &validatingResolver{core: core}, CertifierOptions{KeyType: certcrypto.EC256})
+
+	request := ObtainRequest{
+		// The identifier of the second part is rejected by the server.
+		Domains: []string{"example.com", "z.*.example.com", "b.example.com", "a.example.com"},
+		Bundle:  true,
+	}
+
+	resources, err := certifier.ObtainSplit(request, &SplitOptions{MaxIdentifiers: 3})
+	require.ErrorContains(t, err, "example.com-part2: ")
+
+	// The first part is returned with the error.
+	require.Len(t, resources, 1)
+	assert.Equal(t, "example.com", resources[0].Domain)
+
+	cert, err := certcrypto.ParsePEMCertificate(resources[0].Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com", "a.example.com", "b.example.com"}, cert.DNSNames)
+}
+
+func Test_matchReplacedParts(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		parts    [][]string
+		stored   []SplitPart
+		expected []string
+	}{
+		{
+			desc:  "same parts",
+			parts: [][]string{{"example.com", "a.example.com"}, {"b.example.com"}},
+			stored: []SplitPart{
+				{Name: "example.com", Domains: []string{"example.com", "a.example.com"}, CertID: "1"},
+				{Name: "example.com-part2", Domains: []string{"b.example.com"}, CertID: "2"},
+			},
+			expected: []string{"1", "2"},
+		},
+		{
+			desc:  "moved domains",
+			parts: [][]string{{"example.com", "0.example.com"}, {"a.example.com", "b.example.com"}, {"c.example.com"}},
+			stored: []SplitPart{
+				{Name: "example.com", Domains: []string{"example.com", "a.example.com"}, CertID: "1"},
+				{Name: "example.com-part2", Domains: []string{"b.example.com", "c.example.com"}, CertID: "2"},
+			},
+			expected: []string{"1", "2", ""},
+		},
+		{
+			desc:  "reordered parts",
+			parts: [][]string{{"example.com"}, {"c.example.com", "d.example.com"}, {"a.example.com", "b.example.com"}},
+			stored: []SplitPart{
+				{Name: "example.com", Domains: []string{"example.com"}, CertID: "1"},
+				{Name: "example.com-part2", Domains: []string{"a.example.com", "b.example.com"}, CertID: "2"},
+				{Name: "example.com-part3", Domains: []string{"c.example.com", "d.example.com"}, CertID: "3"},
+			},
+			expected: []string{"1", "3", "2"},
+		},
+		{
+			desc:     "no stored parts",
+			parts:    [][]string{{"example.com"}},
+			expected: []string{""},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, matchReplacedParts(test.parts, test.stored))
+		})
+	}
+}
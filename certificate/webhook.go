@@ -0,0 +1,132 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook headers.
+const (
+	// WebhookEventHeader the header containing the type of the event.
+	WebhookEventHeader = "X-Lego-Event"
+
+	// WebhookSignatureHeader the header containing the signature of the timestamp and the body:
+	// "sha256=" followed by the hex encoded HMAC-SHA256 of the timestamp, a dot, and the body.
+	WebhookSignatureHeader = "X-Lego-Signature"
+
+	// WebhookTimestampHeader the header containing the time of the request (Unix time, in seconds), signed with the body.
+	WebhookTimestampHeader = "X-Lego-Timestamp"
+)
+
+// DefaultWebhookTolerance the default maximum difference between the timestamp of a request and the time of its verification.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// WebhookNotifier a Notifier sending the events to an HTTP endpoint:
+// each event is sent as a JSON document (see Event) with a POST request.
+type WebhookNotifier struct {
+	// URL the endpoint.
+	URL string
+
+	// Secret the key used to sign the requests (optional, HMAC-SHA256, see WebhookSignatureHeader).
+	Secret string
+
+	HTTPClient *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier.
+func NewWebhookNotifier(endpoint, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:        endpoint,
+		Secret:     secret,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Notify sends the event to the endpoint.
+func (w *WebhookNotifier) Notify(ctx context.Context, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("webhook: marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(event.Type))
+
+	if w.Secret != "" {
+		timestamp := time.Now()
+
+		req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(w.Secret, timestamp, body))
+	}
+
+	client := w.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+
+		return fmt.Errorf("webhook: unexpected status code: %d: %s", resp.StatusCode, bytes.TrimSpace(raw))
+	}
+
+	return nil
+}
+
+// SignWebhook returns the signature of a webhook body and its timestamp, as sent in the WebhookSignatureHeader.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks the signature of a webhook request (WebhookSignatureHeader),
+// and that its timestamp (WebhookTimestampHeader) is within the tolerance (DefaultWebhookTolerance if zero),
+// to reject the replayed requests.
+func VerifyWebhook(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+
+	seconds, err := strconv.ParseInt(header.Get(WebhookTimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("webhook: invalid timestamp: %w", err)
+	}
+
+	timestamp := time.Unix(seconds, 0)
+
+	if !hmac.Equal([]byte(SignWebhook(secret, timestamp, body)), []byte(header.Get(WebhookSignatureHeader))) {
+		return errors.New("webhook: invalid signature")
+	}
+
+	if diff := time.Since(timestamp).Abs(); diff > tolerance {
+		return fmt.Errorf("webhook: the timestamp is outside of the tolerance (%s): %s", tolerance, timestamp.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
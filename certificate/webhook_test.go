@@ -0,0 +1,140 @@
+package certificate
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	events := make(chan *Event, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+			http.Error(rw, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		err = VerifyWebhook("secret", req.Header, body, 0)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		event := &Event{}
+
+		err = json.Unmarshal(body, event)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if req.Header.Get(WebhookEventHeader) != string(event.Type) {
+			http.Error(rw, "invalid event header", http.StatusBadRequest)
+			return
+		}
+
+		events <- event
+	}))
+	t.Cleanup(server.Close)
+
+	event := &Event{
+		Type:    EventFailed,
+		Time:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Domain:  "example.com",
+		Domains: []string{"example.com", "*.example.com"},
+		Error:   "boom",
+	}
+
+	err := NewWebhookNotifier(server.URL, "secret").Notify(t.Context(), event)
+	require.NoError(t, err)
+
+	assert.Equal(t, event, <-events)
+
+	err = NewWebhookNotifier(server.URL, "other").Notify(t.Context(), event)
+	require.EqualError(t, err, "webhook: unexpected status code: 401: webhook: invalid signature")
+}
+
+func TestSignWebhook(t *testing.T) {
+	signature := SignWebhook("secret", time.Unix(1735689600, 0), []byte(`{"type":"issued"}`))
+
+	assert.Equal(t, "sha256=6c0e9a9cf967d60a892a9a19001dec0b65b04f89e3b7531a245c7e6d91cddbf5", signature)
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"type":"issued"}`)
+
+	now := time.Now()
+
+	testCases := []struct {
+		desc      string
+		timestamp string
+		signature string
+		body      []byte
+		expected  string
+	}{
+		{
+			desc:      "valid",
+			timestamp: strconv.FormatInt(now.Unix(), 10),
+			signature: SignWebhook("secret", now, body),
+			body:      body,
+		},
+		{
+			desc:      "other body",
+			timestamp: strconv.FormatInt(now.Unix(), 10),
+			signature: SignWebhook("secret", now, body),
+			body:      []byte(`{"type":"renewed"}`),
+			expected:  "webhook: invalid signature",
+		},
+		{
+			desc:      "other timestamp",
+			timestamp: strconv.FormatInt(now.Unix()+1, 10),
+			signature: SignWebhook("secret", now, body),
+			body:      body,
+			expected:  "webhook: invalid signature",
+		},
+		{
+			desc:      "replayed",
+			timestamp: strconv.FormatInt(now.Add(-time.Hour).Unix(), 10),
+			signature: SignWebhook("secret", now.Add(-time.Hour), body),
+			body:      body,
+			expected:  "webhook: the timestamp is outside of the tolerance (5m0s): " + now.Add(-time.Hour).UTC().Format(time.RFC3339),
+		},
+		{
+			desc:      "missing timestamp",
+			signature: SignWebhook("secret", now, body),
+			body:      body,
+			expected:  `webhook: invalid timestamp: strconv.ParseInt: parsing "": invalid syntax`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			header := http.Header{}
+			header.Set(WebhookTimestampHeader, test.timestamp)
+			header.Set(WebhookSignatureHeader, test.signature)
+
+			err := VerifyWebhook("secret", header, test.body, 0)
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
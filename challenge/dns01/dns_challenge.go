@@ -1,6 +1,7 @@
 package dns01
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
@@ -30,8 +31,19 @@ const (
 
 type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
 
+// ValidateWithContextFunc is the context-aware counterpart of ValidateFunc.
+type ValidateWithContextFunc func(ctx context.Context, core *api.Core, domain string, chlng acme.Challenge) error
+
 type ChallengeOption func(*Challenge) error
 
+// SetValidateWithContext replaces the validation function by a context-aware one.
+func SetValidateWithContext(validate ValidateWithContextFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.validateCtx = validate
+		return nil
+	}
+}
+
 // CondOption Conditional challenge option.
 func CondOption(condition bool, opt ChallengeOption) ChallengeOption {
 	if !condition {
@@ -45,11 +57,12 @@ func CondOption(condition bool, opt ChallengeOption) ChallengeOption {
 
 // Challenge implements the dns-01 challenge.
 type Challenge struct {
-	core       *api.Core
-	validate   ValidateFunc
-	provider   challenge.Provider
-	preCheck   preCheck
-	dnsTimeout time.Duration
+	core        *api.Core
+	validate    ValidateFunc
+	validateCtx ValidateWithContextFunc
+	provider    challenge.Provider
+	preCheck    preCheck
+	dnsTimeout  time.Duration
 }
 
 func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Provider, opts ...ChallengeOption) *Challenge {
@@ -74,6 +87,12 @@ func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Prov
 // PreSolve just submits the txt record to the dns provider.
 // It does not validate record propagation, or do anything at all with the acme server.
 func (c *Challenge) PreSolve(authz acme.Authorization) error {
+	return c.PreSolveWithContext(context.Background(), authz)
+}
+
+// PreSolveWithContext just submits the txt record to the dns provider.
+// It does not validate record propagation, or do anything at all with the acme server.
+func (c *Challenge) PreSolveWithContext(ctx context.Context, authz acme.Authorization) error {
 	domain := challenge.GetTargetedDomain(authz)
 	log.Infof("[%s] acme: Preparing to solve DNS-01", domain)
 
@@ -92,7 +111,7 @@ func (c *Challenge) PreSolve(authz acme.Authorization) error {
 		return err
 	}
 
-	err = c.provider.Present(authz.Identifier.Value, chlng.Token, keyAuth)
+	err = challenge.Present(ctx, c.provider, authz.Identifier.Value, chlng.Token, keyAuth)
 	if err != nil {
 		return fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)
 	}
@@ -101,6 +120,11 @@ func (c *Challenge) PreSolve(authz acme.Authorization) error {
 }
 
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveWithContext(context.Background(), authz)
+}
+
+// SolveWithContext waits for the record propagation, then asks the ACME server to validate the challenge.
+func (c *Challenge) SolveWithContext(ctx context.Context, authz acme.Authorization) error {
 	domain := challenge.GetTargetedDomain(authz)
 	log.Infof("[%s] acme: Trying to solve DNS-01", domain)
 
@@ -117,6 +141,19 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 
 	info := GetChallengeInfo(authz.Identifier.Value, keyAuth)
 
+	err = c.WaitForPropagation(ctx, domain, info.EffectiveFQDN, info.Value)
+	if err != nil {
+		return err
+	}
+
+	chlng.KeyAuthorization = keyAuth
+
+	return c.Validate(ctx, domain, chlng)
+}
+
+// WaitForPropagation waits for the propagation of the TXT record,
+// according to the timeout of the provider and the propagation options of the challenge.
+func (c *Challenge) WaitForPropagation(ctx context.Context, domain, fqdn, value string) error {
 	var timeout, interval time.Duration
 	switch provider := c.provider.(type) {
 	case challenge.ProviderTimeout:
@@ -127,25 +164,36 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 
 	log.Infof("[%s] acme: Checking DNS record propagation. [nameservers=%s]", domain, strings.Join(recursiveNameservers, ","))
 
-	time.Sleep(interval)
+	err := waitBeforePropagationCheck(ctx, fqdn, interval+c.preCheck.propagationWait)
+	if err != nil {
+		return err
+	}
 
-	err = wait.For("propagation", timeout, interval, func() (bool, error) {
-		stop, errP := c.preCheck.call(domain, info.EffectiveFQDN, info.Value)
+	return wait.ForWithContext(ctx, "propagation", timeout, interval, func() (bool, error) {
+		stop, errP := c.preCheck.call(domain, fqdn, value)
 		if !stop || errP != nil {
 			log.Infof("[%s] acme: Waiting for DNS record propagation.", domain)
 		}
 		return stop, errP
 	})
-	if err != nil {
-		return err
+}
+
+// Validate asks the ACME server to validate the challenge.
+func (c *Challenge) Validate(ctx context.Context, domain string, chlng acme.Challenge) error {
+	if c.validateCtx != nil {
+		return c.validateCtx(ctx, c.core, domain, chlng)
 	}
 
-	chlng.KeyAuthorization = keyAuth
 	return c.validate(c.core, domain, chlng)
 }
 
 // CleanUp cleans the challenge.
 func (c *Challenge) CleanUp(authz acme.Authorization) error {
+	return c.CleanUpWithContext(context.Background(), authz)
+}
+
+// CleanUpWithContext cleans the challenge.
+func (c *Challenge) CleanUpWithContext(ctx context.Context, authz acme.Authorization) error {
 	log.Infof("[%s] acme: Cleaning DNS-01 challenge", challenge.GetTargetedDomain(authz))
 
 	chlng, err := challenge.FindChallenge(challenge.DNS01, authz)
@@ -158,7 +206,7 @@ func (c *Challenge) CleanUp(authz acme.Authorization) error {
 		return err
 	}
 
-	return c.provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
+	return challenge.CleanUp(ctx, c.provider, authz.Identifier.Value, chlng.Token, keyAuth)
 }
 
 func (c *Challenge) Sequential() (bool, time.Duration) {
@@ -192,28 +240,50 @@ type ChallengeInfo struct {
 	Value string
 }
 
+// RecordProvider is implemented by the DNS providers able to create a TXT record from its FQDN and its value.
+// It is used by the challenges which do not use the record of the `dns-01` challenge (i.e. `dns-account-01`),
+// and to create the records which are not related to a challenge (i.e. `dns-persist-01` records).
+// The other DNS providers are supported through AsRecordProvider.
+type RecordProvider interface {
+	PresentRecord(ctx context.Context, domain string, info ChallengeInfo) error
+	CleanUpRecord(ctx context.Context, domain string, info ChallengeInfo) error
+}
+
 // GetChallengeInfo returns information used to create a DNS record which will fulfill the `dns-01` challenge.
+// For the records created through AsRecordProvider, the information of the record is returned.
 func GetChallengeInfo(domain, keyAuth string) ChallengeInfo {
-	keyAuthShaBytes := sha256.Sum256([]byte(keyAuth))
-	// base64URL encoding without padding
-	value := base64.RawURLEncoding.EncodeToString(keyAuthShaBytes[:sha256.Size])
+	if info, ok := records.get(keyAuth); ok {
+		return info
+	}
+
+	return NewChallengeInfo(fmt.Sprintf("_acme-challenge.%s.", domain), GetChallengeValue(keyAuth))
+}
 
+// NewChallengeInfo returns information used to create a DNS record from its FQDN and its value.
+// It is used by the challenges which do not use the record of the `dns-01` challenge (i.e. `dns-account-01`).
+func NewChallengeInfo(fqdn, value string) ChallengeInfo {
 	ok, _ := strconv.ParseBool(os.Getenv("LEGO_DISABLE_CNAME_SUPPORT"))
 
+	effectiveFQDN := fqdn
+	if !ok {
+		effectiveFQDN = followCNAMEs(fqdn)
+	}
+
 	return ChallengeInfo{
 		Value:         value,
-		FQDN:          getChallengeFQDN(domain, false),
-		EffectiveFQDN: getChallengeFQDN(domain, !ok),
+		FQDN:          fqdn,
+		EffectiveFQDN: effectiveFQDN,
 	}
 }
 
-func getChallengeFQDN(domain string, followCNAME bool) string {
-	fqdn := fmt.Sprintf("_acme-challenge.%s.", domain)
-
-	if !followCNAME {
-		return fqdn
-	}
+// GetChallengeValue returns the value of the TXT record for a key authorization.
+func GetChallengeValue(keyAuth string) string {
+	keyAuthShaBytes := sha256.Sum256([]byte(keyAuth))
+	// base64URL encoding without padding
+	return base64.RawURLEncoding.EncodeToString(keyAuthShaBytes[:sha256.Size])
+}
 
+func followCNAMEs(fqdn string) string {
 	// recursion counter so it doesn't spin out of control
 	for range 50 {
 		// Keep following CNAMEs
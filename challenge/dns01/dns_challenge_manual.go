@@ -2,6 +2,7 @@ package dns01
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -11,6 +12,8 @@ const (
 	dnsTemplate = `%s %d IN TXT %q`
 )
 
+var _ RecordProvider = (*DNSProviderManual)(nil)
+
 // DNSProviderManual is an implementation of the ChallengeProvider interface.
 type DNSProviderManual struct{}
 
@@ -20,9 +23,12 @@ func NewDNSProviderManual() (*DNSProviderManual, error) {
 }
 
 // Present prints instructions for manually creating the TXT record.
-func (*DNSProviderManual) Present(domain, token, keyAuth string) error {
-	info := GetChallengeInfo(domain, keyAuth)
+func (d *DNSProviderManual) Present(domain, token, keyAuth string) error {
+	return d.PresentRecord(context.Background(), domain, GetChallengeInfo(domain, keyAuth))
+}
 
+// PresentRecord prints instructions for manually creating the TXT record.
+func (*DNSProviderManual) PresentRecord(_ context.Context, _ string, info ChallengeInfo) error {
 	authZone, err := FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("manual: could not find zone: %w", err)
@@ -41,9 +47,12 @@ func (*DNSProviderManual) Present(domain, token, keyAuth string) error {
 }
 
 // CleanUp prints instructions for manually removing the TXT record.
-func (*DNSProviderManual) CleanUp(domain, token, keyAuth string) error {
-	info := GetChallengeInfo(domain, keyAuth)
+func (d *DNSProviderManual) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpRecord(context.Background(), domain, GetChallengeInfo(domain, keyAuth))
+}
 
+// CleanUpRecord prints instructions for manually removing the TXT record.
+func (*DNSProviderManual) CleanUpRecord(_ context.Context, _ string, info ChallengeInfo) error {
 	authZone, err := FindZoneByFqdn(info.EffectiveFQDN)
 	if err != nil {
 		return fmt.Errorf("manual: could not find zone: %w", err)
@@ -151,6 +151,36 @@ func FindZoneByFqdnCustom(fqdn string, nameservers []string) (string, error) {
 	return soa.zone, nil
 }
 
+// LookupTXT returns the values of the TXT records for the given fqdn, the CNAMEs are followed.
+func LookupTXT(fqdn string) ([]string, error) {
+	return LookupTXTCustom(fqdn, recursiveNameservers)
+}
+
+// LookupTXTCustom returns the values of the TXT records for the given fqdn, the CNAMEs are followed.
+func LookupTXTCustom(fqdn string, nameservers []string) ([]string, error) {
+	r, err := dnsQuery(fqdn, dns.TypeTXT, nameservers, true)
+	if err != nil {
+		return nil, fmt.Errorf("[fqdn=%s] %w", fqdn, err)
+	}
+
+	switch r.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("[fqdn=%s] unexpected response code '%s'", fqdn, dns.RcodeToString[r.Rcode])
+	}
+
+	var values []string
+	for _, rr := range r.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			values = append(values, strings.Join(txt.Txt, ""))
+		}
+	}
+
+	return values, nil
+}
+
 func lookupSoaByFqdn(fqdn string, nameservers []string) (*soaCacheEntry, error) {
 	// Do we have it cached and is it still fresh?
 	entAny, ok := fqdnSoaCache.Load(fqdn)
@@ -44,6 +44,8 @@ func RecursiveNSsPropagationRequirement() ChallengeOption {
 	}
 }
 
+// PropagationWait waits for a fixed duration before each propagation check.
+// If skipCheck is true, the propagation is not checked.
 func PropagationWait(wait time.Duration, skipCheck bool) ChallengeOption {
 	return WrapPreCheck(func(domain, fqdn, value string, check PreCheckFunc) (bool, error) {
 		time.Sleep(wait)
@@ -56,10 +58,32 @@ func PropagationWait(wait time.Duration, skipCheck bool) ChallengeOption {
 	})
 }
 
+// InitialPropagationWait waits for a fixed duration once, before the first propagation check.
+// If skipCheck is true, the propagation is not checked.
+//
+// When the challenges share the propagation wait (WithSharedPropagationWait),
+// each record still waits for the full duration after its own presentation.
+func InitialPropagationWait(wait time.Duration, skipCheck bool) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.preCheck.propagationWait = wait
+
+		if skipCheck {
+			chlg.preCheck.checkFunc = func(_, _, _ string, _ PreCheckFunc) (bool, error) {
+				return true, nil
+			}
+		}
+
+		return nil
+	}
+}
+
 type preCheck struct {
 	// checks DNS propagation before notifying ACME that the DNS challenge is ready.
 	checkFunc WrapPreCheckFunc
 
+	// fixed wait before checking the propagation.
+	propagationWait time.Duration
+
 	// require the TXT record to be propagated to all authoritative name servers
 	requireAuthoritativeNssPropagation bool
 
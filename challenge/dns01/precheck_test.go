@@ -2,6 +2,7 @@ package dns01
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -112,3 +113,66 @@ func TestCheckAuthoritativeNssErr(t *testing.T) {
 		})
 	}
 }
+
+func TestPropagationWait(t *testing.T) {
+	chlg := &Challenge{preCheck: newPreCheck()}
+
+	err := PropagationWait(10*time.Millisecond, false)(chlg)
+	require.NoError(t, err)
+
+	assert.Zero(t, chlg.preCheck.propagationWait)
+
+	var calls int
+	check := func(_, _ string) (bool, error) {
+		calls++
+		return calls > 1, nil
+	}
+
+	start := time.Now()
+
+	for range 2 {
+		_, err = chlg.preCheck.checkFunc("example.com", "_acme-challenge.example.com.", "value", check)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestInitialPropagationWait(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		skipCheck bool
+	}{
+		{
+			desc: "with check",
+		},
+		{
+			desc:      "skip check",
+			skipCheck: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			chlg := &Challenge{preCheck: newPreCheck()}
+
+			err := InitialPropagationWait(time.Minute, test.skipCheck)(chlg)
+			require.NoError(t, err)
+
+			assert.Equal(t, time.Minute, chlg.preCheck.propagationWait)
+
+			if test.skipCheck {
+				require.NotNil(t, chlg.preCheck.checkFunc)
+
+				ok, err := chlg.preCheck.call("example.com", "_acme-challenge.example.com.", "value")
+				require.NoError(t, err)
+				assert.True(t, ok)
+			} else {
+				assert.Nil(t, chlg.preCheck.checkFunc)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package dns01
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/go-acme/lego/v4/challenge"
+)
+
+// recordKeyAuthPrefix the prefix of the key authorizations used by the record provider adapter.
+// A key authorization of a challenge (`token.thumbprint`) cannot start with it.
+const recordKeyAuthPrefix = "lego-record:"
+
+// records the information of the records presented through the record provider adapter, by key authorization.
+var records = &recordRegistry{entries: map[string]*recordEntry{}}
+
+type recordEntry struct {
+	info  ChallengeInfo
+	count int
+}
+
+// recordRegistry the records being presented or cleaned up through the record provider adapter.
+// An entry only exists during the call to the DNS provider.
+type recordRegistry struct {
+	mu      sync.Mutex
+	entries map[string]*recordEntry
+}
+
+func (r *recordRegistry) add(keyAuth string, info ChallengeInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[keyAuth]
+	if !ok {
+		entry = &recordEntry{info: info}
+		r.entries[keyAuth] = entry
+	}
+
+	entry.count++
+}
+
+func (r *recordRegistry) remove(keyAuth string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[keyAuth]
+	if !ok {
+		return
+	}
+
+	entry.count--
+
+	if entry.count <= 0 {
+		delete(r.entries, keyAuth)
+	}
+}
+
+func (r *recordRegistry) get(keyAuth string) (ChallengeInfo, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[keyAuth]
+	if !ok {
+		return ChallengeInfo{}, false
+	}
+
+	return entry.info, true
+}
+
+// AsRecordProvider returns a RecordProvider for the DNS provider.
+// If the DNS provider implements RecordProvider, it is returned as is.
+// Otherwise, the records are created through the Present and CleanUp methods of the DNS provider:
+// during the call, GetChallengeInfo returns the information of the record instead of the `dns-01` record.
+func AsRecordProvider(provider challenge.Provider) RecordProvider {
+	if p, ok := provider.(RecordProvider); ok {
+		return p
+	}
+
+	return &recordProviderAdapter{provider: provider}
+}
+
+// recordProviderAdapter creates arbitrary TXT records with a DNS provider which only supports the `dns-01` record.
+type recordProviderAdapter struct {
+	provider challenge.Provider
+}
+
+func (a *recordProviderAdapter) PresentRecord(ctx context.Context, domain string, info ChallengeInfo) error {
+	token, keyAuth := recordKeyAuth(info)
+
+	records.add(keyAuth, info)
+	defer records.remove(keyAuth)
+
+	return challenge.Present(ctx, a.provider, domain, token, keyAuth)
+}
+
+func (a *recordProviderAdapter) CleanUpRecord(ctx context.Context, domain string, info ChallengeInfo) error {
+	token, keyAuth := recordKeyAuth(info)
+
+	records.add(keyAuth, info)
+	defer records.remove(keyAuth)
+
+	return challenge.CleanUp(ctx, a.provider, domain, token, keyAuth)
+}
+
+// recordKeyAuth returns the token and the key authorization passed to the DNS provider for a record.
+// They are the same for Present and CleanUp, because some DNS providers use the token to find the record to remove.
+func recordKeyAuth(info ChallengeInfo) (token, keyAuth string) {
+	digest := sha256.Sum256([]byte(info.FQDN + " " + info.Value))
+
+	token = hex.EncodeToString(digest[:])
+
+	return token, recordKeyAuthPrefix + token
+}
@@ -0,0 +1,74 @@
+package dns01
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// challengeProviderMock only supports the dns-01 record.
+type challengeProviderMock struct {
+	presented, cleaned ChallengeInfo
+	tokens             []string
+}
+
+func (p *challengeProviderMock) Present(domain, token, keyAuth string) error {
+	p.presented = GetChallengeInfo(domain, keyAuth)
+	p.tokens = append(p.tokens, token)
+
+	return nil
+}
+
+func (p *challengeProviderMock) CleanUp(domain, token, keyAuth string) error {
+	p.cleaned = GetChallengeInfo(domain, keyAuth)
+	p.tokens = append(p.tokens, token)
+
+	return nil
+}
+
+type recordProviderMock struct {
+	challengeProviderMock
+}
+
+func (p *recordProviderMock) PresentRecord(_ context.Context, _ string, _ ChallengeInfo) error {
+	return nil
+}
+
+func (p *recordProviderMock) CleanUpRecord(_ context.Context, _ string, _ ChallengeInfo) error {
+	return nil
+}
+
+func TestAsRecordProvider(t *testing.T) {
+	provider := &challengeProviderMock{}
+
+	info := ChallengeInfo{
+		FQDN:          "_validation-persist.example.com.",
+		EffectiveFQDN: "_validation-persist.example.com.",
+		Value:         "letsencrypt.org; accounturi=https://example.com/acme/acct/1",
+	}
+
+	recordProvider := AsRecordProvider(provider)
+
+	err := recordProvider.PresentRecord(t.Context(), "example.com", info)
+	require.NoError(t, err)
+
+	assert.Equal(t, info, provider.presented)
+
+	err = recordProvider.CleanUpRecord(t.Context(), "example.com", info)
+	require.NoError(t, err)
+
+	assert.Equal(t, info, provider.cleaned)
+
+	require.Len(t, provider.tokens, 2)
+	assert.Equal(t, provider.tokens[0], provider.tokens[1])
+
+	assert.Empty(t, records.entries)
+}
+
+func TestAsRecordProvider_recordProvider(t *testing.T) {
+	provider := &recordProviderMock{}
+
+	assert.Same(t, provider, AsRecordProvider(provider))
+}
@@ -0,0 +1,113 @@
+package dns01
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type sharedWaitsKey struct{}
+
+// clock abstracts the time functions used by the shared propagation waits.
+type clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func())
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) {
+	time.AfterFunc(d, f)
+}
+
+// sharedWait a propagation wait shared by the records of a zone.
+type sharedWait struct {
+	// deadline the end of the wait of the last record which joined the wait.
+	deadline time.Time
+	done     chan struct{}
+}
+
+// sharedWaits the in-flight propagation waits, by zone.
+type sharedWaits struct {
+	mu    sync.Mutex
+	clock clock
+	zones map[string]*sharedWait
+}
+
+// WithSharedPropagationWait returns a context in which the concurrent challenges of a same zone share one propagation wait,
+// instead of waiting one after the other.
+// Each record still waits for the full duration: a record joining an in-flight wait extends it,
+// and all the records of the zone are released together at the end of the wait of the last one.
+func WithSharedPropagationWait(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sharedWaitsKey{}, newSharedWaits(realClock{}))
+}
+
+func newSharedWaits(c clock) *sharedWaits {
+	return &sharedWaits{clock: c, zones: map[string]*sharedWait{}}
+}
+
+// wait starts a wait for the zone, or joins the in-flight wait of the zone.
+// The wait ends at the earliest d after the call.
+func (w *sharedWaits) wait(ctx context.Context, zone string, d time.Duration) error {
+	w.mu.Lock()
+
+	deadline := w.clock.Now().Add(d)
+
+	sw, ok := w.zones[zone]
+	if !ok {
+		sw = &sharedWait{deadline: deadline, done: make(chan struct{})}
+		w.zones[zone] = sw
+
+		w.clock.AfterFunc(d, func() { w.release(zone, sw) })
+	} else if deadline.After(sw.deadline) {
+		// The timer is rescheduled by release when it fires before the new deadline.
+		sw.deadline = deadline
+	}
+
+	w.mu.Unlock()
+
+	select {
+	case <-sw.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release ends the wait of the zone, or reschedules it if the deadline has been extended.
+func (w *sharedWaits) release(zone string, sw *sharedWait) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if remaining := sw.deadline.Sub(w.clock.Now()); remaining > 0 {
+		w.clock.AfterFunc(remaining, func() { w.release(zone, sw) })
+		return
+	}
+
+	delete(w.zones, zone)
+
+	close(sw.done)
+}
+
+func waitBeforePropagationCheck(ctx context.Context, fqdn string, d time.Duration) error {
+	if waits, ok := ctx.Value(sharedWaitsKey{}).(*sharedWaits); ok {
+		zone, err := FindZoneByFqdn(fqdn)
+		if err != nil {
+			// The wait is not shared.
+			zone = fqdn
+		}
+
+		return waits.wait(ctx, zone, d)
+	}
+
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
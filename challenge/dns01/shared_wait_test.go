@@ -0,0 +1,173 @@
+package dns01
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTimer struct {
+	at time.Time
+	f  func()
+}
+
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	calls  int
+	timers []fakeTimer
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls++
+
+	return c.now
+}
+
+func (c *fakeClock) nowCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), f: f})
+}
+
+// Advance moves the clock forward and runs the timers which are due.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+
+	var due []fakeTimer
+
+	pending := c.timers[:0]
+
+	for _, timer := range c.timers {
+		if timer.at.After(c.now) {
+			pending = append(pending, timer)
+		} else {
+			due = append(due, timer)
+		}
+	}
+
+	c.timers = pending
+	c.mu.Unlock()
+
+	for _, timer := range due {
+		timer.f()
+	}
+}
+
+// startWait runs a wait in a goroutine, and returns once the wait is registered.
+func startWait(t *testing.T, clock *fakeClock, waits *sharedWaits, zone string, d time.Duration) <-chan error {
+	t.Helper()
+
+	calls := clock.nowCalls()
+
+	result := make(chan error, 1)
+
+	go func() {
+		result <- waits.wait(t.Context(), zone, d)
+	}()
+
+	// The wait reads the clock while holding the lock, until the wait is registered.
+	require.Eventually(t, func() bool { return clock.nowCalls() > calls }, time.Second, time.Millisecond)
+
+	waits.mu.Lock()
+	defer waits.mu.Unlock()
+
+	require.Contains(t, waits.zones, zone)
+
+	return result
+}
+
+func assertReleased(t *testing.T, result <-chan error) {
+	t.Helper()
+
+	select {
+	case err := <-result:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("the wait has not been released")
+	}
+}
+
+func assertWaiting(t *testing.T, result <-chan error) {
+	t.Helper()
+
+	select {
+	case <-result:
+		t.Fatal("the wait has been released too early")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func Test_sharedWaits_wait(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	waits := newSharedWaits(clock)
+
+	first := startWait(t, clock, waits, "example.com.", 10*time.Second)
+	other := startWait(t, clock, waits, "example.org.", 10*time.Second)
+
+	clock.Advance(5 * time.Second)
+
+	// The second record of example.com. joins the wait, and extends it to its own deadline.
+	second := startWait(t, clock, waits, "example.com.", 10*time.Second)
+
+	clock.Advance(5 * time.Second)
+
+	assertReleased(t, other)
+	assertWaiting(t, first)
+	assertWaiting(t, second)
+
+	clock.Advance(5 * time.Second)
+
+	assertReleased(t, first)
+	assertReleased(t, second)
+
+	assert.Empty(t, waits.zones)
+}
+
+func Test_sharedWaits_wait_shorter(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	waits := newSharedWaits(clock)
+
+	first := startWait(t, clock, waits, "example.com.", 10*time.Second)
+
+	clock.Advance(2 * time.Second)
+
+	// The deadline of the joining record is before the deadline of the in-flight wait.
+	second := startWait(t, clock, waits, "example.com.", 5*time.Second)
+
+	clock.Advance(5 * time.Second)
+
+	assertWaiting(t, first)
+	assertWaiting(t, second)
+
+	clock.Advance(3 * time.Second)
+
+	assertReleased(t, first)
+	assertReleased(t, second)
+}
+
+func Test_sharedWaits_wait_canceled(t *testing.T) {
+	waits := newSharedWaits(&fakeClock{})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := waits.wait(ctx, "example.com.", 10*time.Second)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,159 @@
+package dnsaccount01
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+)
+
+// Challenge implements the dns-account-01 challenge.
+// The DNS providers and the propagation checks of the dns-01 challenge are reused,
+// only the validation domain name is different:
+// the record is created through dns01.AsRecordProvider.
+type Challenge struct {
+	core     *api.Core
+	provider dns01.RecordProvider
+	dns      *dns01.Challenge
+}
+
+// NewChallenge creates a dns-account-01 challenge.
+// The options of the dns-01 challenge (propagation checks, validation function, etc.) are supported.
+func NewChallenge(core *api.Core, validate dns01.ValidateFunc, provider challenge.Provider, opts ...dns01.ChallengeOption) *Challenge {
+	return &Challenge{
+		core:     core,
+		provider: dns01.AsRecordProvider(provider),
+		dns:      dns01.NewChallenge(core, validate, provider, opts...),
+	}
+}
+
+// PreSolve just submits the txt record to the dns provider.
+// It does not validate record propagation, or do anything at all with the acme server.
+func (c *Challenge) PreSolve(authz acme.Authorization) error {
+	return c.PreSolveWithContext(context.Background(), authz)
+}
+
+// PreSolveWithContext just submits the txt record to the dns provider.
+// It does not validate record propagation, or do anything at all with the acme server.
+func (c *Challenge) PreSolveWithContext(ctx context.Context, authz acme.Authorization) error {
+	domain := challenge.GetTargetedDomain(authz)
+	log.Infof("[%s] acme: Preparing to solve DNS-ACCOUNT-01", domain)
+
+	chlng, err := challenge.FindChallenge(challenge.DNSAccount01, authz)
+	if err != nil {
+		return err
+	}
+
+	// Generate the Key Authorization for the challenge
+	keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
+	if err != nil {
+		return err
+	}
+
+	info, err := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", domain, err)
+	}
+
+	err = c.provider.PresentRecord(ctx, authz.Identifier.Value, info)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)
+	}
+
+	return nil
+}
+
+func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveWithContext(context.Background(), authz)
+}
+
+// SolveWithContext waits for the record propagation, then asks the ACME server to validate the challenge.
+func (c *Challenge) SolveWithContext(ctx context.Context, authz acme.Authorization) error {
+	domain := challenge.GetTargetedDomain(authz)
+	log.Infof("[%s] acme: Trying to solve DNS-ACCOUNT-01", domain)
+
+	chlng, err := challenge.FindChallenge(challenge.DNSAccount01, authz)
+	if err != nil {
+		return err
+	}
+
+	// Generate the Key Authorization for the challenge
+	keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
+	if err != nil {
+		return err
+	}
+
+	info, err := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", domain, err)
+	}
+
+	err = c.dns.WaitForPropagation(ctx, domain, info.EffectiveFQDN, info.Value)
+	if err != nil {
+		return err
+	}
+
+	chlng.KeyAuthorization = keyAuth
+
+	return c.dns.Validate(ctx, domain, chlng)
+}
+
+// CleanUp cleans the challenge.
+func (c *Challenge) CleanUp(authz acme.Authorization) error {
+	return c.CleanUpWithContext(context.Background(), authz)
+}
+
+// CleanUpWithContext cleans the challenge.
+func (c *Challenge) CleanUpWithContext(ctx context.Context, authz acme.Authorization) error {
+	log.Infof("[%s] acme: Cleaning DNS-ACCOUNT-01 challenge", challenge.GetTargetedDomain(authz))
+
+	chlng, err := challenge.FindChallenge(challenge.DNSAccount01, authz)
+	if err != nil {
+		return err
+	}
+
+	keyAuth, err := c.core.GetKeyAuthorization(chlng.Token)
+	if err != nil {
+		return err
+	}
+
+	info, err := c.getChallengeInfo(authz.Identifier.Value, keyAuth)
+	if err != nil {
+		return err
+	}
+
+	return c.provider.CleanUpRecord(ctx, authz.Identifier.Value, info)
+}
+
+func (c *Challenge) Sequential() (bool, time.Duration) {
+	return c.dns.Sequential()
+}
+
+func (c *Challenge) getChallengeInfo(domain, keyAuth string) (dns01.ChallengeInfo, error) {
+	accountURL := c.core.GetAccountURL()
+	if accountURL == "" {
+		return dns01.ChallengeInfo{}, errors.New("the account must be registered to use the dns-account-01 challenge")
+	}
+
+	return dns01.NewChallengeInfo(GetChallengeFQDN(accountURL, domain), dns01.GetChallengeValue(keyAuth)), nil
+}
+
+// GetChallengeFQDN returns the validation domain name of the `dns-account-01` challenge:
+// `_[account label]._acme-challenge.[domain].`
+// The account label is the base32 encoding (lowercase) of the first 10 bytes of the SHA-256 digest of the account URL.
+func GetChallengeFQDN(accountURL, domain string) string {
+	digest := sha256.Sum256([]byte(accountURL))
+
+	label := strings.ToLower(base32.StdEncoding.EncodeToString(digest[:10]))
+
+	return fmt.Sprintf("_%s._acme-challenge.%s.", label, domain)
+}
@@ -0,0 +1,162 @@
+package dnsaccount01
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type providerMock struct {
+	presentFQDN, cleanUpFQDN string
+}
+
+func (p *providerMock) Present(_, _, _ string) error {
+	panic("the dns-01 record must not be used")
+}
+
+func (p *providerMock) CleanUp(_, _, _ string) error {
+	panic("the dns-01 record must not be used")
+}
+
+func (p *providerMock) PresentRecord(_ context.Context, _ string, info dns01.ChallengeInfo) error {
+	p.presentFQDN = info.FQDN
+	return nil
+}
+
+func (p *providerMock) CleanUpRecord(_ context.Context, _ string, info dns01.ChallengeInfo) error {
+	p.cleanUpFQDN = info.FQDN
+	return nil
+}
+
+// dns01ProviderMock only supports the dns-01 record.
+type dns01ProviderMock struct {
+	presentFQDN, cleanUpFQDN string
+}
+
+func (p *dns01ProviderMock) Present(domain, _, keyAuth string) error {
+	p.presentFQDN = dns01.GetChallengeInfo(domain, keyAuth).FQDN
+	return nil
+}
+
+func (p *dns01ProviderMock) CleanUp(domain, _, keyAuth string) error {
+	p.cleanUpFQDN = dns01.GetChallengeInfo(domain, keyAuth).FQDN
+	return nil
+}
+
+func TestGetChallengeFQDN(t *testing.T) {
+	// https://datatracker.ietf.org/doc/html/draft-ietf-acme-dns-account-label-00#section-3.1
+	fqdn := GetChallengeFQDN("https://example.com/acme/acct/ExampleAccount", "example.org")
+
+	assert.Equal(t, "_ujmmovf2vn55tgye._acme-challenge.example.org.", fqdn)
+}
+
+func TestChallenge(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	const accountURL = "https://example.com/acme/acct/ExampleAccount"
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", accountURL, privateKey)
+	require.NoError(t, err)
+
+	provider := &providerMock{}
+
+	var checkedFQDN string
+
+	chlg := NewChallenge(core,
+		func(_ *api.Core, _ string, _ acme.Challenge) error { return nil },
+		provider,
+		dns01.WrapPreCheck(func(_, fqdn, _ string, _ dns01.PreCheckFunc) (bool, error) {
+			checkedFQDN = fqdn
+			return true, nil
+		}),
+	)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.org"},
+		Challenges: []acme.Challenge{
+			{Type: challenge.DNSAccount01.String(), Token: "token"},
+		},
+	}
+
+	expected := GetChallengeFQDN(accountURL, "example.org")
+
+	require.NoError(t, chlg.PreSolve(authz))
+	assert.Equal(t, expected, provider.presentFQDN)
+
+	require.NoError(t, chlg.Solve(authz))
+	assert.Equal(t, expected, checkedFQDN)
+
+	require.NoError(t, chlg.CleanUp(authz))
+	assert.Equal(t, expected, provider.cleanUpFQDN)
+
+}
+
+func TestChallenge_PreSolve_unregisteredAccount(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", "", privateKey)
+	require.NoError(t, err)
+
+	chlg := NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error { return nil }, &providerMock{})
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.org"},
+		Challenges: []acme.Challenge{
+			{Type: challenge.DNSAccount01.String(), Token: "token"},
+		},
+	}
+
+	err = chlg.PreSolve(authz)
+	require.EqualError(t, err, "[example.org] acme: the account must be registered to use the dns-account-01 challenge")
+}
+
+func TestChallenge_dns01Provider(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	const accountURL = "https://example.com/acme/acct/ExampleAccount"
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", accountURL, privateKey)
+	require.NoError(t, err)
+
+	provider := &dns01ProviderMock{}
+
+	chlg := NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error { return nil }, provider)
+
+	authz := acme.Authorization{
+		Identifier: acme.Identifier{Value: "example.org"},
+		Challenges: []acme.Challenge{
+			{Type: challenge.DNSAccount01.String(), Token: "token"},
+		},
+	}
+
+	expected := GetChallengeFQDN(accountURL, "example.org")
+
+	require.NoError(t, chlg.PreSolve(authz))
+	assert.Equal(t, expected, provider.presentFQDN)
+
+	require.NoError(t, chlg.CleanUp(authz))
+	assert.Equal(t, expected, provider.cleanUpFQDN)
+}
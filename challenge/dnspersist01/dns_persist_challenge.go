@@ -0,0 +1,149 @@
+package dnspersist01
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/log"
+)
+
+type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
+
+// ValidateWithContextFunc is the context-aware counterpart of ValidateFunc.
+type ValidateWithContextFunc func(ctx context.Context, core *api.Core, domain string, chlng acme.Challenge) error
+
+type ChallengeOption func(*Challenge) error
+
+// SetValidateWithContext replaces the validation function by a context-aware one.
+func SetValidateWithContext(validate ValidateWithContextFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.validateCtx = validate
+		return nil
+	}
+}
+
+// CondOption Conditional challenge option.
+func CondOption(condition bool, opt ChallengeOption) ChallengeOption {
+	if !condition {
+		// NoOp options
+		return func(*Challenge) error {
+			return nil
+		}
+	}
+	return opt
+}
+
+// SetNameservers defines the nameservers used to look up the persistent validation record.
+// By default, the recursive nameservers of the dns01 package are used.
+func SetNameservers(nameservers []string) ChallengeOption {
+	return func(chlg *Challenge) error {
+		if len(nameservers) == 0 {
+			return errors.New("empty list of nameservers")
+		}
+
+		chlg.lookupTXT = func(fqdn string) ([]string, error) {
+			return dns01.LookupTXTCustom(fqdn, nameservers)
+		}
+
+		return nil
+	}
+}
+
+// Challenge implements the dns-persist-01 challenge.
+// The persistent validation record must be created beforehand: no DNS provider is used.
+type Challenge struct {
+	core        *api.Core
+	validate    ValidateFunc
+	validateCtx ValidateWithContextFunc
+	lookupTXT   func(fqdn string) ([]string, error)
+}
+
+func NewChallenge(core *api.Core, validate ValidateFunc, opts ...ChallengeOption) *Challenge {
+	chlg := &Challenge{
+		core:      core,
+		validate:  validate,
+		lookupTXT: dns01.LookupTXT,
+	}
+
+	for _, opt := range opts {
+		err := opt(chlg)
+		if err != nil {
+			log.Infof("challenge option error: %v", err)
+		}
+	}
+
+	return chlg
+}
+
+func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveWithContext(context.Background(), authz)
+}
+
+// SolveWithContext checks the persistent validation record, then asks the ACME server to validate the challenge.
+func (c *Challenge) SolveWithContext(ctx context.Context, authz acme.Authorization) error {
+	domain := challenge.GetTargetedDomain(authz)
+	log.Infof("[%s] acme: Trying to solve DNS-PERSIST-01", domain)
+
+	chlng, err := challenge.FindChallenge(challenge.DNSPersist01, authz)
+	if err != nil {
+		return err
+	}
+
+	err = c.checkRecord(authz, chlng)
+	if err != nil {
+		return fmt.Errorf("[%s] acme: %w", domain, err)
+	}
+
+	if c.validateCtx != nil {
+		return c.validateCtx(ctx, c.core, domain, chlng)
+	}
+
+	return c.validate(c.core, domain, chlng)
+}
+
+// checkRecord checks that a persistent validation record authorizes the account.
+func (c *Challenge) checkRecord(authz acme.Authorization, chlng acme.Challenge) error {
+	accountURL := c.core.GetAccountURL()
+	if accountURL == "" {
+		return errors.New("the account must be registered to use the dns-persist-01 challenge")
+	}
+
+	if len(chlng.IssuerDomainNames) == 0 {
+		return errors.New("the challenge doesn't contain issuer domain names")
+	}
+
+	fqdn := GetRecordFQDN(authz.Identifier.Value)
+
+	values, err := c.lookupTXT(fqdn)
+	if err != nil {
+		return fmt.Errorf("persistent validation record: %w", err)
+	}
+
+	now := time.Now()
+
+	for _, value := range values {
+		record, errP := ParseRecord(value)
+		if errP != nil {
+			log.Infof("[%s] acme: ignored persistent validation record %q: %v", authz.Identifier.Value, value, errP)
+			continue
+		}
+
+		if record.authorizes(chlng.IssuerDomainNames, accountURL, authz.Wildcard, now) {
+			return nil
+		}
+	}
+
+	expected := Record{
+		IssuerDomainName: chlng.IssuerDomainNames[0],
+		AccountURI:       accountURL,
+		Wildcard:         authz.Wildcard,
+	}
+
+	return fmt.Errorf("no persistent validation record authorizes the account: expected TXT record %s with value %q", fqdn, expected)
+}
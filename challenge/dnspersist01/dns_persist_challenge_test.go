@@ -0,0 +1,104 @@
+package dnspersist01
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/challenge"
+	"github.com/go-acme/lego/v4/platform/tester"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallenge_Solve(t *testing.T) {
+	_, apiURL := tester.SetupFakeAPI(t)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	const accountURL = "https://ca.example/acct/123"
+
+	core, err := api.New(http.DefaultClient, "lego-test", apiURL+"/dir", accountURL, privateKey)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		wildcard bool
+		records  []string
+		expected string
+	}{
+		{
+			desc:    "valid record",
+			records: []string{"other.example; accounturi=" + accountURL, "authority.example; accounturi=" + accountURL},
+		},
+		{
+			desc:     "wildcard",
+			wildcard: true,
+			records:  []string{"authority.example; accounturi=" + accountURL + "; policy=wildcard"},
+		},
+		{
+			desc:     "wildcard not allowed",
+			wildcard: true,
+			records:  []string{"authority.example; accounturi=" + accountURL},
+			expected: `[*.example.com] acme: no persistent validation record authorizes the account: expected TXT record _validation-persist.example.com. with value "authority.example; accounturi=https://ca.example/acct/123; policy=wildcard"`,
+		},
+		{
+			desc:     "other account",
+			records:  []string{"authority.example; accounturi=https://ca.example/acct/456"},
+			expected: `[example.com] acme: no persistent validation record authorizes the account: expected TXT record _validation-persist.example.com. with value "authority.example; accounturi=https://ca.example/acct/123"`,
+		},
+		{
+			desc:     "expired record",
+			records:  []string{"authority.example; accounturi=" + accountURL + "; persistUntil=1000"},
+			expected: `[example.com] acme: no persistent validation record authorizes the account: expected TXT record _validation-persist.example.com. with value "authority.example; accounturi=https://ca.example/acct/123"`,
+		},
+		{
+			desc:     "no record",
+			expected: `[example.com] acme: no persistent validation record authorizes the account: expected TXT record _validation-persist.example.com. with value "authority.example; accounturi=https://ca.example/acct/123"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var validated bool
+
+			chlg := NewChallenge(core, func(_ *api.Core, _ string, _ acme.Challenge) error {
+				validated = true
+				return nil
+			})
+
+			chlg.lookupTXT = func(fqdn string) ([]string, error) {
+				if fqdn != "_validation-persist.example.com." {
+					return nil, nil
+				}
+
+				return test.records, nil
+			}
+
+			authz := acme.Authorization{
+				Identifier: acme.Identifier{Type: "dns", Value: "example.com"},
+				Wildcard:   test.wildcard,
+				Challenges: []acme.Challenge{{
+					Type:              challenge.DNSPersist01.String(),
+					IssuerDomainNames: []string{"authority.example", "other.example"},
+				}},
+			}
+
+			err := chlg.Solve(authz)
+			if test.expected != "" {
+				require.EqualError(t, err, test.expected)
+				require.False(t, validated)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, validated)
+		})
+	}
+}
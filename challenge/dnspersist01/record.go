@@ -0,0 +1,115 @@
+package dnspersist01
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+)
+
+// PolicyWildcard the policy allowing the record to authorize wildcard certificates.
+const PolicyWildcard = "wildcard"
+
+// Record a persistent validation record.
+// https://datatracker.ietf.org/doc/draft-ietf-acme-dns-persist/
+type Record struct {
+	// IssuerDomainName the domain name of the CA allowed to issue certificates.
+	IssuerDomainName string
+
+	// AccountURI the URL of the authorized account.
+	AccountURI string
+
+	// Wildcard allows wildcard certificates (policy=wildcard).
+	Wildcard bool
+
+	// PersistUntil the time after which the record must not be used (optional).
+	PersistUntil time.Time
+}
+
+// GetRecordFQDN returns the FQDN of the persistent validation record: `_validation-persist.[domain].`
+func GetRecordFQDN(domain string) string {
+	return fmt.Sprintf("_validation-persist.%s.", domain)
+}
+
+// PresentRecord creates the persistent validation record of the domain through the DNS provider.
+// The record is never cleaned up.
+func PresentRecord(ctx context.Context, provider dns01.RecordProvider, domain string, record Record) error {
+	return provider.PresentRecord(ctx, domain, dns01.NewChallengeInfo(GetRecordFQDN(domain), record.String()))
+}
+
+// String returns the value of the TXT record.
+func (r Record) String() string {
+	parts := []string{r.IssuerDomainName, "accounturi=" + r.AccountURI}
+
+	if r.Wildcard {
+		parts = append(parts, "policy="+PolicyWildcard)
+	}
+
+	if !r.PersistUntil.IsZero() {
+		parts = append(parts, "persistUntil="+strconv.FormatInt(r.PersistUntil.Unix(), 10))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// ParseRecord parses the value of a TXT record.
+// The unknown parameters are ignored.
+func ParseRecord(value string) (Record, error) {
+	parts := strings.Split(value, ";")
+
+	record := Record{IssuerDomainName: strings.ToLower(strings.TrimSpace(parts[0]))}
+	if record.IssuerDomainName == "" {
+		return Record{}, errors.New("missing issuer domain name")
+	}
+
+	for _, part := range parts[1:] {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return Record{}, fmt.Errorf("malformed parameter: %q", part)
+		}
+
+		val = strings.TrimSpace(val)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "accounturi":
+			record.AccountURI = val
+		case "policy":
+			record.Wildcard = strings.EqualFold(val, PolicyWildcard)
+		case "persistuntil":
+			ts, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return Record{}, fmt.Errorf("malformed persistUntil: %w", err)
+			}
+
+			record.PersistUntil = time.Unix(ts, 0)
+		}
+	}
+
+	if record.AccountURI == "" {
+		return Record{}, errors.New("missing accounturi")
+	}
+
+	return record, nil
+}
+
+// authorizes checks if the record authorizes the account for one of the issuer domain names.
+func (r Record) authorizes(issuerDomainNames []string, accountURI string, wildcard bool, now time.Time) bool {
+	if !slices.ContainsFunc(issuerDomainNames, func(name string) bool { return strings.EqualFold(name, r.IssuerDomainName) }) {
+		return false
+	}
+
+	if r.AccountURI != accountURI {
+		return false
+	}
+
+	if wildcard && !r.Wildcard {
+		return false
+	}
+
+	return r.PersistUntil.IsZero() || now.Before(r.PersistUntil)
+}
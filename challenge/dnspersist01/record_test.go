@@ -0,0 +1,156 @@
+package dnspersist01
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord_String(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		record   Record
+		expected string
+	}{
+		{
+			desc: "simple",
+			record: Record{
+				IssuerDomainName: "authority.example",
+				AccountURI:       "https://ca.example/acct/123",
+			},
+			expected: "authority.example; accounturi=https://ca.example/acct/123",
+		},
+		{
+			desc: "all parameters",
+			record: Record{
+				IssuerDomainName: "authority.example",
+				AccountURI:       "https://ca.example/acct/123",
+				Wildcard:         true,
+				PersistUntil:     time.Unix(1767225600, 0),
+			},
+			expected: "authority.example; accounturi=https://ca.example/acct/123; policy=wildcard; persistUntil=1767225600",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.record.String())
+		})
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    string
+		expected Record
+	}{
+		{
+			desc:  "simple",
+			value: "authority.example; accounturi=https://ca.example/acct/123",
+			expected: Record{
+				IssuerDomainName: "authority.example",
+				AccountURI:       "https://ca.example/acct/123",
+			},
+		},
+		{
+			desc:  "all parameters",
+			value: "Authority.Example;accounturi=https://ca.example/acct/123;Policy=WILDCARD; persistUntil=1767225600; foo=bar",
+			expected: Record{
+				IssuerDomainName: "authority.example",
+				AccountURI:       "https://ca.example/acct/123",
+				Wildcard:         true,
+				PersistUntil:     time.Unix(1767225600, 0),
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			record, err := ParseRecord(test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, record)
+		})
+	}
+}
+
+func TestParseRecord_error(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    string
+		expected string
+	}{
+		{
+			desc:     "empty",
+			value:    "",
+			expected: "missing issuer domain name",
+		},
+		{
+			desc:     "missing account URI",
+			value:    "authority.example; policy=wildcard",
+			expected: "missing accounturi",
+		},
+		{
+			desc:     "malformed parameter",
+			value:    "authority.example; accounturi",
+			expected: `malformed parameter: " accounturi"`,
+		},
+		{
+			desc:     "malformed persistUntil",
+			value:    "authority.example; accounturi=https://ca.example/acct/123; persistUntil=tomorrow",
+			expected: `malformed persistUntil: strconv.ParseInt: parsing "tomorrow": invalid syntax`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseRecord(test.value)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+type recordProviderMock struct {
+	domain string
+	info   dns01.ChallengeInfo
+}
+
+func (p *recordProviderMock) PresentRecord(_ context.Context, domain string, info dns01.ChallengeInfo) error {
+	p.domain = domain
+	p.info = info
+	return nil
+}
+
+func (p *recordProviderMock) CleanUpRecord(_ context.Context, _ string, _ dns01.ChallengeInfo) error {
+	return nil
+}
+
+func TestPresentRecord(t *testing.T) {
+	t.Setenv("LEGO_DISABLE_CNAME_SUPPORT", "true")
+
+	provider := &recordProviderMock{}
+
+	record := Record{
+		IssuerDomainName: "authority.example",
+		AccountURI:       "https://ca.example/acct/123",
+	}
+
+	err := PresentRecord(t.Context(), provider, "example.org", record)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.org", provider.domain)
+	assert.Equal(t, "_validation-persist.example.org.", provider.info.FQDN)
+	assert.Equal(t, "_validation-persist.example.org.", provider.info.EffectiveFQDN)
+	assert.Equal(t, "authority.example; accounturi=https://ca.example/acct/123", provider.info.Value)
+}
@@ -1,6 +1,7 @@
 package http01
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -12,8 +13,19 @@ import (
 
 type ValidateFunc func(core *api.Core, domain string, chlng acme.Challenge) error
 
+// ValidateWithContextFunc is the context-aware counterpart of ValidateFunc.
+type ValidateWithContextFunc func(ctx context.Context, core *api.Core, domain string, chlng acme.Challenge) error
+
 type ChallengeOption func(*Challenge) error
 
+// SetValidateWithContext replaces the validation function by a context-aware one.
+func SetValidateWithContext(validate ValidateWithContextFunc) ChallengeOption {
+	return func(chlg *Challenge) error {
+		chlg.validateCtx = validate
+		return nil
+	}
+}
+
 // SetDelay sets a delay between the start of the HTTP server and the challenge validation.
 func SetDelay(delay time.Duration) ChallengeOption {
 	return func(chlg *Challenge) error {
@@ -28,10 +40,11 @@ func ChallengePath(token string) string {
 }
 
 type Challenge struct {
-	core     *api.Core
-	validate ValidateFunc
-	provider challenge.Provider
-	delay    time.Duration
+	core        *api.Core
+	validate    ValidateFunc
+	validateCtx ValidateWithContextFunc
+	provider    challenge.Provider
+	delay       time.Duration
 }
 
 func NewChallenge(core *api.Core, validate ValidateFunc, provider challenge.Provider, opts ...ChallengeOption) *Challenge {
@@ -56,6 +69,11 @@ func (c *Challenge) SetProvider(provider challenge.Provider) {
 }
 
 func (c *Challenge) Solve(authz acme.Authorization) error {
+	return c.SolveWithContext(context.Background(), authz)
+}
+
+// SolveWithContext manages the provider to validate and solve the challenge.
+func (c *Challenge) SolveWithContext(ctx context.Context, authz acme.Authorization) error {
 	domain := challenge.GetTargetedDomain(authz)
 	log.Infof("[%s] acme: Trying to solve HTTP-01", domain)
 
@@ -70,21 +88,33 @@ func (c *Challenge) Solve(authz acme.Authorization) error {
 		return err
 	}
 
-	err = c.provider.Present(authz.Identifier.Value, chlng.Token, keyAuth)
+	err = challenge.Present(ctx, c.provider, authz.Identifier.Value, chlng.Token, keyAuth)
 	if err != nil {
 		return fmt.Errorf("[%s] acme: error presenting token: %w", domain, err)
 	}
 	defer func() {
-		err := c.provider.CleanUp(authz.Identifier.Value, chlng.Token, keyAuth)
+		err := challenge.CleanUp(context.WithoutCancel(ctx), c.provider, authz.Identifier.Value, chlng.Token, keyAuth)
 		if err != nil {
 			log.Warnf("[%s] acme: cleaning up failed: %v", domain, err)
 		}
 	}()
 
 	if c.delay > 0 {
-		time.Sleep(c.delay)
+		select {
+		case <-time.After(c.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	chlng.KeyAuthorization = keyAuth
+	return c.callValidate(ctx, domain, chlng)
+}
+
+func (c *Challenge) callValidate(ctx context.Context, domain string, chlng acme.Challenge) error {
+	if c.validateCtx != nil {
+		return c.validateCtx(ctx, c.core, domain, chlng)
+	}
+
 	return c.validate(c.core, domain, chlng)
 }
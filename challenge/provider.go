@@ -1,6 +1,9 @@
 package challenge
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // Provider enables implementing a custom challenge
 // provider. Present presents the solution to a challenge available to
@@ -26,3 +29,33 @@ type ProviderTimeout interface {
 	Provider
 	Timeout() (timeout, interval time.Duration)
 }
+
+// ProviderWithContext allows for implementing a
+// Provider that can be cancelled or given a deadline.
+// If a Provider implements ProviderWithContext,
+// the context-aware methods are used instead of Present and CleanUp.
+type ProviderWithContext interface {
+	Provider
+	PresentWithContext(ctx context.Context, domain, token, keyAuth string) error
+	CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error
+}
+
+// Present presents the solution to a challenge,
+// using the context-aware method when the provider supports it.
+func Present(ctx context.Context, provider Provider, domain, token, keyAuth string) error {
+	if p, ok := provider.(ProviderWithContext); ok {
+		return p.PresentWithContext(ctx, domain, token, keyAuth)
+	}
+
+	return provider.Present(domain, token, keyAuth)
+}
+
+// CleanUp cleans the solution of a challenge,
+// using the context-aware method when the provider supports it.
+func CleanUp(ctx context.Context, provider Provider, domain, token, keyAuth string) error {
+	if p, ok := provider.(ProviderWithContext); ok {
+		return p.CleanUpWithContext(ctx, domain, token, keyAuth)
+	}
+
+	return provider.CleanUp(domain, token, keyAuth)
+}
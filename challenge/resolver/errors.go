@@ -12,14 +12,32 @@ type obtainError map[string]error
 func (e obtainError) Error() string {
 	buffer := bytes.NewBufferString("error: one or more domains had a problem:\n")
 
+	for _, domain := range e.domains() {
+		_, _ = fmt.Fprintf(buffer, "[%s] %s\n", domain, e[domain])
+	}
+	return buffer.String()
+}
+
+func (e obtainError) Unwrap() []error {
+	var errs []error
+	for _, domain := range e.domains() {
+		errs = append(errs, e[domain])
+	}
+
+	return errs
+}
+
+// DomainErrors returns the errors by domain.
+func (e obtainError) DomainErrors() map[string]error {
+	return e
+}
+
+func (e obtainError) domains() []string {
 	var domains []string
 	for domain := range e {
 		domains = append(domains, domain)
 	}
 	sort.Strings(domains)
 
-	for _, domain := range domains {
-		_, _ = fmt.Fprintf(buffer, "[%s] %s\n", domain, e[domain])
-	}
-	return buffer.String()
+	return domains
 }
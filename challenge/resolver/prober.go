@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -25,6 +26,19 @@ type cleanup interface {
 	CleanUp(authorization acme.Authorization) error
 }
 
+// Context-aware counterparts of solver, preSolver and cleanup.
+type solverWithContext interface {
+	SolveWithContext(ctx context.Context, authorization acme.Authorization) error
+}
+
+type preSolverWithContext interface {
+	PreSolveWithContext(ctx context.Context, authorization acme.Authorization) error
+}
+
+type cleanupWithContext interface {
+	CleanUpWithContext(ctx context.Context, authorization acme.Authorization) error
+}
+
 type sequential interface {
 	Sequential() (bool, time.Duration)
 }
@@ -48,6 +62,12 @@ func NewProber(solverManager *SolverManager) *Prober {
 // Solve Looks through the challenge combinations to find a solvable match.
 // Then solves the challenges in series and returns.
 func (p *Prober) Solve(authorizations []acme.Authorization) error {
+	return p.SolveWithContext(context.Background(), authorizations)
+}
+
+// SolveWithContext Looks through the challenge combinations to find a solvable match.
+// Then solves the challenges in series and returns.
+func (p *Prober) SolveWithContext(ctx context.Context, authorizations []acme.Authorization) error {
 	failures := make(obtainError)
 
 	var authSolvers []*selectedAuthSolver
@@ -81,9 +101,9 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 		}
 	}
 
-	parallelSolve(authSolvers, failures)
+	parallelSolve(ctx, authSolvers, failures)
 
-	sequentialSolve(authSolversSequential, failures)
+	sequentialSolve(ctx, authSolversSequential, failures)
 
 	// Be careful not to return an empty failures map,
 	// for even an empty obtainError is a non-nil error value
@@ -93,56 +113,56 @@ func (p *Prober) Solve(authorizations []acme.Authorization) error {
 	return nil
 }
 
-func sequentialSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
+func sequentialSolve(ctx context.Context, authSolvers []*selectedAuthSolver, failures obtainError) {
 	for i, authSolver := range authSolvers {
 		// Submit the challenge
 		domain := challenge.GetTargetedDomain(authSolver.authz)
 
-		if solvr, ok := authSolver.solver.(preSolver); ok {
-			err := solvr.PreSolve(authSolver.authz)
-			if err != nil {
-				failures[domain] = err
-				cleanUp(authSolver.solver, authSolver.authz)
-				continue
-			}
+		err := preSolve(ctx, authSolver.solver, authSolver.authz)
+		if err != nil {
+			failures[domain] = err
+			cleanUp(ctx, authSolver.solver, authSolver.authz)
+			continue
 		}
 
 		// Solve challenge
-		err := authSolver.solver.Solve(authSolver.authz)
+		err = solve(ctx, authSolver.solver, authSolver.authz)
 		if err != nil {
 			failures[domain] = err
-			cleanUp(authSolver.solver, authSolver.authz)
+			cleanUp(ctx, authSolver.solver, authSolver.authz)
 			continue
 		}
 
 		// Clean challenge
-		cleanUp(authSolver.solver, authSolver.authz)
+		cleanUp(ctx, authSolver.solver, authSolver.authz)
 
 		if len(authSolvers)-1 > i {
 			solvr := authSolver.solver.(sequential)
 			_, interval := solvr.Sequential()
 			log.Infof("sequence: wait for %s", interval)
-			time.Sleep(interval)
+
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+			}
 		}
 	}
 }
 
-func parallelSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
+func parallelSolve(ctx context.Context, authSolvers []*selectedAuthSolver, failures obtainError) {
 	// For all valid preSolvers, first submit the challenges, so they have max time to propagate
 	for _, authSolver := range authSolvers {
 		authz := authSolver.authz
-		if solvr, ok := authSolver.solver.(preSolver); ok {
-			err := solvr.PreSolve(authz)
-			if err != nil {
-				failures[challenge.GetTargetedDomain(authz)] = err
-			}
+		err := preSolve(ctx, authSolver.solver, authz)
+		if err != nil {
+			failures[challenge.GetTargetedDomain(authz)] = err
 		}
 	}
 
 	defer func() {
 		// Clean all created TXT records
 		for _, authSolver := range authSolvers {
-			cleanUp(authSolver.solver, authSolver.authz)
+			cleanUp(ctx, authSolver.solver, authSolver.authz)
 		}
 	}()
 
@@ -155,19 +175,48 @@ func parallelSolve(authSolvers []*selectedAuthSolver, failures obtainError) {
 			continue
 		}
 
-		err := authSolver.solver.Solve(authz)
+		err := solve(ctx, authSolver.solver, authz)
 		if err != nil {
 			failures[domain] = err
 		}
 	}
 }
 
-func cleanUp(solvr solver, authz acme.Authorization) {
-	if solvr, ok := solvr.(cleanup); ok {
-		domain := challenge.GetTargetedDomain(authz)
-		err := solvr.CleanUp(authz)
-		if err != nil {
-			log.Warnf("[%s] acme: cleaning up failed: %v ", domain, err)
-		}
+func preSolve(ctx context.Context, solvr solver, authz acme.Authorization) error {
+	switch s := solvr.(type) {
+	case preSolverWithContext:
+		return s.PreSolveWithContext(ctx, authz)
+	case preSolver:
+		return s.PreSolve(authz)
+	default:
+		return nil
+	}
+}
+
+func solve(ctx context.Context, solvr solver, authz acme.Authorization) error {
+	if s, ok := solvr.(solverWithContext); ok {
+		return s.SolveWithContext(ctx, authz)
+	}
+
+	return solvr.Solve(authz)
+}
+
+func cleanUp(ctx context.Context, solvr solver, authz acme.Authorization) {
+	// The cleanup must happen even if the context has been cancelled.
+	ctx = context.WithoutCancel(ctx)
+
+	var err error
+
+	switch s := solvr.(type) {
+	case cleanupWithContext:
+		err = s.CleanUpWithContext(ctx, authz)
+	case cleanup:
+		err = s.CleanUp(authz)
+	default:
+		return
+	}
+
+	if err != nil {
+		log.Warnf("[%s] acme: cleaning up failed: %v ", challenge.GetTargetedDomain(authz), err)
 	}
 }
@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
@@ -12,6 +13,8 @@ import (
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/challenge/dnsaccount01"
+	"github.com/go-acme/lego/v4/challenge/dnspersist01"
 	"github.com/go-acme/lego/v4/challenge/http01"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/go-acme/lego/v4/log"
@@ -37,22 +40,41 @@ func NewSolversManager(core *api.Core) *SolverManager {
 
 // SetHTTP01Provider specifies a custom provider p that can solve the given HTTP-01 challenge.
 func (c *SolverManager) SetHTTP01Provider(p challenge.Provider, opts ...http01.ChallengeOption) error {
+	opts = append([]http01.ChallengeOption{http01.SetValidateWithContext(validateWithContext)}, opts...)
 	c.solvers[challenge.HTTP01] = http01.NewChallenge(c.core, validate, p, opts...)
 	return nil
 }
 
 // SetTLSALPN01Provider specifies a custom provider p that can solve the given TLS-ALPN-01 challenge.
 func (c *SolverManager) SetTLSALPN01Provider(p challenge.Provider, opts ...tlsalpn01.ChallengeOption) error {
+	opts = append([]tlsalpn01.ChallengeOption{tlsalpn01.SetValidateWithContext(validateWithContext)}, opts...)
 	c.solvers[challenge.TLSALPN01] = tlsalpn01.NewChallenge(c.core, validate, p, opts...)
 	return nil
 }
 
 // SetDNS01Provider specifies a custom provider p that can solve the given DNS-01 challenge.
 func (c *SolverManager) SetDNS01Provider(p challenge.Provider, opts ...dns01.ChallengeOption) error {
+	opts = append([]dns01.ChallengeOption{dns01.SetValidateWithContext(validateWithContext)}, opts...)
 	c.solvers[challenge.DNS01] = dns01.NewChallenge(c.core, validate, p, opts...)
 	return nil
 }
 
+// SetDNSAccount01Provider specifies a custom provider p that can solve the given DNS-ACCOUNT-01 challenge.
+// The provider and the options are the same as for the DNS-01 challenge.
+func (c *SolverManager) SetDNSAccount01Provider(p challenge.Provider, opts ...dns01.ChallengeOption) error {
+	opts = append([]dns01.ChallengeOption{dns01.SetValidateWithContext(validateWithContext)}, opts...)
+	c.solvers[challenge.DNSAccount01] = dnsaccount01.NewChallenge(c.core, validate, p, opts...)
+	return nil
+}
+
+// SetDNSPersist01 enables the DNS-PERSIST-01 challenge.
+// The persistent validation record must be created beforehand.
+func (c *SolverManager) SetDNSPersist01(opts ...dnspersist01.ChallengeOption) error {
+	opts = append([]dnspersist01.ChallengeOption{dnspersist01.SetValidateWithContext(validateWithContext)}, opts...)
+	c.solvers[challenge.DNSPersist01] = dnspersist01.NewChallenge(c.core, validate, opts...)
+	return nil
+}
+
 // Remove removes a challenge type from the available solvers.
 func (c *SolverManager) Remove(chlgType challenge.Type) {
 	delete(c.solvers, chlgType)
@@ -76,7 +98,11 @@ func (c *SolverManager) chooseSolver(authz acme.Authorization) solver {
 }
 
 func validate(core *api.Core, domain string, chlg acme.Challenge) error {
-	chlng, err := core.Challenges.New(chlg.URL)
+	return validateWithContext(context.Background(), core, domain, chlg)
+}
+
+func validateWithContext(ctx context.Context, core *api.Core, domain string, chlg acme.Challenge) error {
+	chlng, err := core.Challenges.NewWithContext(ctx, chlg.URL)
 	if err != nil {
 		return fmt.Errorf("failed to initiate challenge: %w", err)
 	}
@@ -109,7 +135,7 @@ func validate(core *api.Core, domain string, chlg acme.Challenge) error {
 	// After the path is sent, the ACME server will access our server.
 	// Repeatedly check the server for an updated status on our request.
 	operation := func() error {
-		authz, err := core.Authorizations.Get(chlng.AuthorizationURL)
+		authz, err := core.Authorizations.GetWithContext(ctx, chlng.AuthorizationURL)
 		if err != nil {
 			return backoff.Permanent(err)
 		}
@@ -127,7 +153,7 @@ func validate(core *api.Core, domain string, chlg acme.Challenge) error {
 		return fmt.Errorf("the server didn't respond to our request (status=%s)", authz.Status)
 	}
 
-	return backoff.Retry(operation, bo)
+	return backoff.Retry(operation, backoff.WithContext(bo, ctx))
 }
 
 func checkChallengeStatus(chlng acme.ExtendedChallenge) (bool, error) {
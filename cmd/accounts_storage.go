@@ -6,9 +6,11 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/go-acme/lego/v4/certcrypto"
@@ -22,6 +24,9 @@ const (
 	baseAccountsRootFolderName = "accounts"
 	baseKeysFolderName         = "keys"
 	accountFileName            = "account.json"
+	accountLockFileName        = "account.lock"
+	daemonPIDFileName          = "daemon.pid"
+	stagedKeyExt               = ".new"
 )
 
 // AccountsStorage A storage for account data.
@@ -57,7 +62,10 @@ const (
 //	     │      │             └── CA server ("server" option)
 //	     │      └── root accounts directory
 //	     └── "path" option
+//
+// The files are stored in the storage selected by the "storage" option (local filesystem by default).
 type AccountsStorage struct {
+	storage         Storage
 	userID          string
 	rootPath        string
 	rootUserPath    string
@@ -69,31 +77,59 @@ type AccountsStorage struct {
 // NewAccountsStorage Creates a new AccountsStorage.
 func NewAccountsStorage(ctx *cli.Context) *AccountsStorage {
 	// TODO: move to account struct? Currently MUST pass email.
-	email := getEmail(ctx)
+	return newAccountsStorage(ctx, getEmail(ctx))
+}
 
-	serverURL, err := url.Parse(ctx.String(flgServer))
+// openAccountsStorageFromFlags creates the storage of the account of the "email" option.
+func openAccountsStorageFromFlags(ctx *cli.Context) (*AccountsStorage, error) {
+	email, err := parseEmail(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return openAccountsStorage(ctx, email)
+}
+
+func newAccountsStorage(ctx *cli.Context, email string) *AccountsStorage {
+	accountsStorage, err := openAccountsStorage(ctx, email)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	rootPath := filepath.Join(ctx.String(flgPath), baseAccountsRootFolderName)
+	return accountsStorage
+}
+
+// openAccountsStorage creates the storage of an account, without creating any file.
+func openAccountsStorage(ctx *cli.Context, email string) (*AccountsStorage, error) {
+	serverURL, err := url.Parse(ctx.String(flgServer))
+	if err != nil {
+		return nil, err
+	}
+
+	storage, root, err := openStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rootPath := filepath.Join(root, baseAccountsRootFolderName)
 	serverPath := strings.NewReplacer(":", "_", "/", string(os.PathSeparator)).Replace(serverURL.Host)
 	accountsPath := filepath.Join(rootPath, serverPath)
 	rootUserPath := filepath.Join(accountsPath, email)
 
 	return &AccountsStorage{
+		storage:         storage,
 		userID:          email,
 		rootPath:        rootPath,
 		rootUserPath:    rootUserPath,
 		keysPath:        filepath.Join(rootUserPath, baseKeysFolderName),
 		accountFilePath: filepath.Join(rootUserPath, accountFileName),
 		ctx:             ctx,
-	}
+	}, nil
 }
 
 func (s *AccountsStorage) ExistsAccountFilePath() bool {
-	accountFile := filepath.Join(s.rootUserPath, accountFileName)
-	if _, err := os.Stat(accountFile); os.IsNotExist(err) {
+	_, err := s.storage.ReadFile(s.accountFilePath)
+	if errors.Is(err, os.ErrNotExist) {
 		return false
 	} else if err != nil {
 		log.Fatal(err)
@@ -102,11 +138,65 @@ func (s *AccountsStorage) ExistsAccountFilePath() bool {
 }
 
 func (s *AccountsStorage) GetRootPath() string {
-	return s.rootPath
+	return s.storage.Location(s.rootPath)
 }
 
 func (s *AccountsStorage) GetRootUserPath() string {
-	return s.rootUserPath
+	return s.storage.Location(s.rootUserPath)
+}
+
+// ListAccounts returns the user IDs of the accounts of the CA server.
+func (s *AccountsStorage) ListAccounts() ([]string, error) {
+	serverPath := filepath.Dir(s.rootUserPath)
+
+	names, err := s.storage.List(serverPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs []string
+
+	for _, name := range names {
+		if filepath.Base(name) == accountFileName && filepath.Dir(filepath.Dir(name)) == serverPath {
+			userIDs = append(userIDs, filepath.Base(filepath.Dir(name)))
+		}
+	}
+
+	return userIDs, nil
+}
+
+// ReadAllAccounts reads the accounts of all the CA servers.
+// Returns the accounts indexed by the location of their directory.
+func (s *AccountsStorage) ReadAllAccounts() (map[string]*Account, error) {
+	names, err := s.storage.List(s.rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[string]*Account)
+
+	for _, name := range names {
+		// <root>/<server>/<userID>/account.json
+		rel, err := filepath.Rel(s.rootPath, name)
+		if err != nil || filepath.Base(name) != accountFileName || strings.Count(filepath.ToSlash(rel), "/") != 2 {
+			continue
+		}
+
+		data, err := s.storage.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		var account Account
+		err = json.Unmarshal(data, &account)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts[s.storage.Location(filepath.Dir(name))] = &account
+	}
+
+	return accounts, nil
 }
 
 func (s *AccountsStorage) GetUserID() string {
@@ -119,19 +209,29 @@ func (s *AccountsStorage) Save(account *Account) error {
 		return err
 	}
 
-	return os.WriteFile(s.accountFilePath, jsonBytes, filePerm)
+	return s.storage.WriteFile(s.accountFilePath, jsonBytes)
 }
 
 func (s *AccountsStorage) LoadAccount(privateKey crypto.PrivateKey) *Account {
-	fileBytes, err := os.ReadFile(s.accountFilePath)
+	account, err := s.readAccount(privateKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return account
+}
+
+// readAccount reads the account file, and recovers the registration if needed.
+func (s *AccountsStorage) readAccount(privateKey crypto.PrivateKey) (*Account, error) {
+	fileBytes, err := s.storage.ReadFile(s.accountFilePath)
 	if err != nil {
-		log.Fatalf("Could not load file for account %s: %v", s.userID, err)
+		return nil, fmt.Errorf("could not load file for account %s: %w", s.userID, err)
 	}
 
 	var account Account
 	err = json.Unmarshal(fileBytes, &account)
 	if err != nil {
-		log.Fatalf("Could not parse file for account %s: %v", s.userID, err)
+		return nil, fmt.Errorf("could not parse file for account %s: %w", s.userID, err)
 	}
 
 	account.key = privateKey
@@ -139,68 +239,197 @@ func (s *AccountsStorage) LoadAccount(privateKey crypto.PrivateKey) *Account {
 	if account.Registration == nil || account.Registration.Body.Status == "" {
 		reg, err := tryRecoverRegistration(s.ctx, privateKey)
 		if err != nil {
-			log.Fatalf("Could not load account for %s. Registration is nil: %#v", s.userID, err)
+			return nil, fmt.Errorf("could not load account for %s, the registration is nil: %w", s.userID, err)
 		}
 
 		account.Registration = reg
 		err = s.Save(&account)
 		if err != nil {
-			log.Fatalf("Could not save account for %s. Registration is nil: %#v", s.userID, err)
+			return nil, fmt.Errorf("could not save account for %s: %w", s.userID, err)
 		}
 	}
 
-	return &account
+	return &account, nil
 }
 
 func (s *AccountsStorage) GetPrivateKey(keyType certcrypto.KeyType) crypto.PrivateKey {
-	accKeyPath := filepath.Join(s.keysPath, s.userID+".key")
+	privateKey, err := s.getOrCreatePrivateKey(keyType)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return privateKey
+}
+
+// getOrCreatePrivateKey reads the private key of the account, the key is created if it doesn't exist.
+func (s *AccountsStorage) getOrCreatePrivateKey(keyType certcrypto.KeyType) (crypto.PrivateKey, error) {
+	accKeyPath := s.getPrivateKeyPath()
 
-	if _, err := os.Stat(accKeyPath); os.IsNotExist(err) {
+	privateKey, err := s.ReadPrivateKey()
+	if errors.Is(err, os.ErrNotExist) {
 		log.Printf("No key found for account %s. Generating a %s key.", s.userID, keyType)
-		s.createKeysFolder()
 
-		privateKey, err := generatePrivateKey(accKeyPath, keyType)
+		privateKey, err = certcrypto.GeneratePrivateKey(keyType)
 		if err != nil {
-			log.Fatalf("Could not generate RSA private account key for account %s: %v", s.userID, err)
+			return nil, fmt.Errorf("could not generate the private account key for account %s: %w", s.userID, err)
 		}
 
-		log.Printf("Saved key to %s", accKeyPath)
-		return privateKey
+		err = s.writePrivateKey(accKeyPath, privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not save the private account key for account %s: %w", s.userID, err)
+		}
+
+		log.Printf("Saved key to %s", s.storage.Location(accKeyPath))
+		return privateKey, nil
 	}
 
-	privateKey, err := loadPrivateKey(accKeyPath)
 	if err != nil {
-		log.Fatalf("Could not load RSA private key from file %s: %v", accKeyPath, err)
+		return nil, fmt.Errorf("could not load the private key from file %s: %w", s.storage.Location(accKeyPath), err)
 	}
 
-	return privateKey
+	return privateKey, nil
 }
 
-func (s *AccountsStorage) createKeysFolder() {
-	if err := createNonExistingFolder(s.keysPath); err != nil {
-		log.Fatalf("Could not check/create directory for account %s: %v", s.userID, err)
+// Lock takes the exclusive lock of the account, to create or change the account (e.g. its key):
+// it waits for the lego processes using the account (RLock).
+// The lock is released by calling the returned function.
+func (s *AccountsStorage) Lock() func() {
+	unlock, err := s.lock()
+	if err != nil {
+		log.Fatal(err)
 	}
+
+	return unlock
+}
+
+func (s *AccountsStorage) lock() (func(), error) {
+	return s.wrapUnlock(s.storage.Lock(filepath.Join(s.rootUserPath, accountLockFileName)))
 }
 
-func generatePrivateKey(file string, keyType certcrypto.KeyType) (crypto.PrivateKey, error) {
-	privateKey, err := certcrypto.GeneratePrivateKey(keyType)
+// RLock takes the shared lock of the account: the lego processes using the account run concurrently,
+// but the account cannot be changed (Lock) until the lock is released by calling the returned function.
+func (s *AccountsStorage) RLock() func() {
+	unlock, err := s.rLock()
 	if err != nil {
-		return nil, err
+		log.Fatal(err)
+	}
+
+	return unlock
+}
+
+func (s *AccountsStorage) rLock() (func(), error) {
+	return s.wrapUnlock(s.storage.RLock(filepath.Join(s.rootUserPath, accountLockFileName)))
+}
+
+// tryLock takes the exclusive lock of the account (Lock) without waiting:
+// the account can be used by a long-running lego process (daemon), the error names it.
+func (s *AccountsStorage) tryLock() (func(), error) {
+	unlock, ok, err := s.storage.TryLock(filepath.Join(s.rootUserPath, accountLockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("could not lock the account %s: %w", s.userID, err)
+	}
+
+	if !ok {
+		if pid, errP := s.readDaemonPID(); errP == nil {
+			return nil, fmt.Errorf("the account %s is used by the lego daemon (PID %d): stop it, then retry", s.userID, pid)
+		}
+
+		return nil, fmt.Errorf("the account %s is used by another lego process: retry when it has ended", s.userID)
+	}
+
+	return s.wrapUnlock(unlock, nil)
+}
+
+// WriteDaemonPID records the PID of the lego daemon using the account, the record is removed by calling the returned function.
+// The daemon holds the shared lock of the account (RLock) while it runs: the PID is reported by tryLock.
+func (s *AccountsStorage) WriteDaemonPID() func() {
+	name := filepath.Join(s.rootUserPath, daemonPIDFileName)
+
+	err := s.storage.WriteFile(name, []byte(strconv.Itoa(os.Getpid())))
+	if err != nil {
+		log.Warnf("Could not save the PID of the daemon to %s: %v", s.storage.Location(name), err)
+	}
+
+	return func() {
+		err := s.storage.Remove(name)
+		if err != nil {
+			log.Warnf("Could not remove the PID file %s: %v", s.storage.Location(name), err)
+		}
 	}
+}
 
-	certOut, err := os.Create(file)
+func (s *AccountsStorage) readDaemonPID() (int, error) {
+	data, err := s.storage.ReadFile(filepath.Join(s.rootUserPath, daemonPIDFileName))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
+
+func (s *AccountsStorage) wrapUnlock(unlock func() error, err error) (func(), error) {
+	if err != nil {
+		return nil, fmt.Errorf("could not lock the account %s: %w", s.userID, err)
+	}
+
+	return func() {
+		err := unlock()
+		if err != nil {
+			log.Warnf("Could not unlock the account %s: %v", s.userID, err)
+		}
+	}, nil
+}
+
+// ReadPrivateKey reads the private key of the account.
+func (s *AccountsStorage) ReadPrivateKey() (crypto.PrivateKey, error) {
+	keyBytes, err := s.storage.ReadFile(s.getPrivateKeyPath())
 	if err != nil {
 		return nil, err
 	}
-	defer certOut.Close()
 
-	pemKey := certcrypto.PEMBlock(privateKey)
-	err = pem.Encode(certOut, pemKey)
+	return parsePrivateKey(keyBytes)
+}
+
+// StagePrivateKey writes the new account key next to the current one, without replacing it.
+// The staged key is kept in the storage until CommitPrivateKey is called,
+// so it cannot be lost if the key change succeeds on the server but not locally.
+func (s *AccountsStorage) StagePrivateKey(privateKey crypto.PrivateKey) error {
+	return s.writePrivateKey(s.getPrivateKeyPath()+stagedKeyExt, privateKey)
+}
+
+// ReadStagedPrivateKey reads the key staged by StagePrivateKey (os.ErrNotExist if there is no staged key).
+func (s *AccountsStorage) ReadStagedPrivateKey() (crypto.PrivateKey, error) {
+	keyBytes, err := s.storage.ReadFile(s.getPrivateKeyPath() + stagedKeyExt)
 	if err != nil {
 		return nil, err
 	}
 
-	return privateKey, nil
+	return parsePrivateKey(keyBytes)
+}
+
+// RemoveStagedPrivateKey removes the key staged by StagePrivateKey.
+func (s *AccountsStorage) RemoveStagedPrivateKey() error {
+	return s.storage.Remove(s.getPrivateKeyPath() + stagedKeyExt)
+}
+
+// GetStagedPrivateKeyPath returns the location of the key staged by StagePrivateKey.
+func (s *AccountsStorage) GetStagedPrivateKeyPath() string {
+	return s.storage.Location(s.getPrivateKeyPath() + stagedKeyExt)
+}
+
+// CommitPrivateKey replaces the current account key by the staged one.
+func (s *AccountsStorage) CommitPrivateKey() error {
+	accKeyPath := s.getPrivateKeyPath()
+
+	return s.storage.Rename(accKeyPath+stagedKeyExt, accKeyPath)
+}
+
+func (s *AccountsStorage) getPrivateKeyPath() string {
+	return filepath.Join(s.keysPath, s.userID+".key")
+}
+
+func (s *AccountsStorage) writePrivateKey(file string, privateKey crypto.PrivateKey) error {
+	return s.storage.WriteFile(file, pem.EncodeToMemory(certcrypto.PEMBlock(privateKey)))
 }
 
 func loadPrivateKey(file string) (crypto.PrivateKey, error) {
@@ -209,7 +438,14 @@ func loadPrivateKey(file string) (crypto.PrivateKey, error) {
 		return nil, err
 	}
 
+	return parsePrivateKey(keyBytes)
+}
+
+func parsePrivateKey(keyBytes []byte) (crypto.PrivateKey, error) {
 	keyBlock, _ := pem.Decode(keyBytes)
+	if keyBlock == nil {
+		return nil, errors.New("invalid PEM block")
+	}
 
 	switch keyBlock.Type {
 	case "RSA PRIVATE KEY":
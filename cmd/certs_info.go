@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+)
+
+// resourceMetadata the content of the metadata file (resourceExt):
+// the resource, and the settings used to obtain the certificate.
+type resourceMetadata struct {
+	*certificate.Resource
+
+	Profile string `json:"profile,omitempty"`
+	Account string `json:"account,omitempty"`
+	Server  string `json:"server,omitempty"`
+}
+
+// storedCertificate a certificate of the storage.
+type storedCertificate struct {
+	// Name the name of the certificate files.
+	Name string
+
+	Certificate *x509.Certificate
+
+	// Chain the issuer certificates: from the bundle, or from the issuer file.
+	Chain []*x509.Certificate
+
+	Metadata *resourceMetadata
+}
+
+// readStoredCertificate reads a certificate, its chain, and its metadata (if any).
+func (s *CertificatesStorage) readStoredCertificate(name string) (*storedCertificate, error) {
+	certificates, err := s.ReadCertificate(name, certExt)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &storedCertificate{
+		Name:        name,
+		Certificate: certificates[0],
+		Chain:       certificates[1:],
+		Metadata:    &resourceMetadata{Resource: &certificate.Resource{}},
+	}
+
+	if len(stored.Chain) == 0 {
+		stored.Chain, err = s.ReadCertificate(name, issuerExt)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	data, err := s.ReadFile(name, resourceExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return stored, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, stored.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return stored, nil
+}
+
+// certificateInfo the description of a certificate (list --format).
+type certificateInfo struct {
+	Name          string    `json:"name"`
+	Domains       []string  `json:"domains"`
+	IPAddresses   []string  `json:"ipAddresses,omitempty"`
+	Serial        string    `json:"serial"`
+	Issuer        string    `json:"issuer"`
+	Chain         []string  `json:"chain,omitempty"`
+	KeyType       string    `json:"keyType"`
+	KeySize       int       `json:"keySize"`
+	NotBefore     time.Time `json:"notBefore"`
+	NotAfter      time.Time `json:"notAfter"`
+	DaysRemaining int       `json:"daysRemaining"`
+	ARICertID     string    `json:"ariCertId,omitempty"`
+	Profile       string    `json:"profile,omitempty"`
+	Account       string    `json:"account,omitempty"`
+	Server        string    `json:"server,omitempty"`
+	Path          string    `json:"path"`
+}
+
+// certificateInfoHeader the header of the CSV format, in the order of certificateInfo.record.
+var certificateInfoHeader = []string{
+	"name", "domains", "ip_addresses", "serial", "issuer", "chain", "key_type", "key_size",
+	"not_before", "not_after", "days_remaining", "ari_cert_id", "profile", "account", "server", "path",
+}
+
+func newCertificateInfo(certsStorage *CertificatesStorage, stored *storedCertificate, now time.Time) (*certificateInfo, error) {
+	cert := stored.Certificate
+
+	name, err := certcrypto.GetCertificateMainDomain(cert)
+	if err != nil && len(cert.IPAddresses) > 0 {
+		// A certificate for IP addresses only.
+		name, err = cert.IPAddresses[0].String(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keyType, keySize := publicKeyInfo(cert)
+
+	info := &certificateInfo{
+		Name:          name,
+		Domains:       cert.DNSNames,
+		Serial:        fmt.Sprintf("%x", cert.SerialNumber),
+		Issuer:        cert.Issuer.String(),
+		KeyType:       keyType,
+		KeySize:       keySize,
+		NotBefore:     cert.NotBefore.UTC(),
+		NotAfter:      cert.NotAfter.UTC(),
+		DaysRemaining: daysRemaining(cert, now),
+		Profile:       stored.Metadata.Profile,
+		Account:       stored.Metadata.Account,
+		Server:        stored.Metadata.Server,
+		Path:          certsStorage.GetFileName(stored.Name, certExt),
+	}
+
+	for _, ip := range cert.IPAddresses {
+		info.IPAddresses = append(info.IPAddresses, ip.String())
+	}
+
+	for _, issuer := range stored.Chain {
+		info.Chain = append(info.Chain, issuer.Subject.String())
+	}
+
+	// The certificates without Authority Key Identifier don't have an ARI CertID.
+	info.ARICertID, _ = certificate.MakeARICertID(cert)
+
+	return info, nil
+}
+
+// record returns the CSV record of the certificate, the lists are separated by semicolons.
+func (i *certificateInfo) record() []string {
+	return []string{
+		i.Name,
+		strings.Join(i.Domains, ";"),
+		strings.Join(i.IPAddresses, ";"),
+		i.Serial,
+		i.Issuer,
+		strings.Join(i.Chain, ";"),
+		i.KeyType,
+		strconv.Itoa(i.KeySize),
+		i.NotBefore.Format(time.RFC3339),
+		i.NotAfter.Format(time.RFC3339),
+		strconv.Itoa(i.DaysRemaining),
+		i.ARICertID,
+		i.Profile,
+		i.Account,
+		i.Server,
+		i.Path,
+	}
+}
+
+// publicKeyInfo returns the type and the size (in bits) of the public key of a certificate.
+func publicKeyInfo(cert *x509.Certificate) (string, int) {
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return cert.PublicKeyAlgorithm.String(), pub.N.BitLen()
+	case *ecdsa.PublicKey:
+		return cert.PublicKeyAlgorithm.String(), pub.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return cert.PublicKeyAlgorithm.String(), ed25519.PublicKeySize * 8
+	default:
+		return cert.PublicKeyAlgorithm.String(), 0
+	}
+}
+
+// daysRemaining returns the number of days before the expiry of a certificate (rounded down).
+func daysRemaining(cert *x509.Certificate, now time.Time) int {
+	return int(cert.NotAfter.Sub(now).Hours() / 24)
+}
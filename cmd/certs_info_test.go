@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificatesStorage_readStoredCertificate(t *testing.T) {
+	storage := &CertificatesStorage{
+		rootPath: t.TempDir(),
+		profile:  "tlsserver",
+		account:  "admin@example.com",
+		server:   "https://acme.example.com/directory",
+	}
+
+	certPEM, keyPEM := generateTestCertificate(t, "example.com")
+	issuerPEM, _ := generateTestCertificate(t, "ca.example.com")
+
+	storage.SaveResource(&certificate.Resource{
+		Domain:            "example.com",
+		CertURL:           "https://acme.example.com/cert/1",
+		Certificate:       certPEM,
+		IssuerCertificate: issuerPEM,
+		PrivateKey:        keyPEM,
+	})
+
+	stored, err := storage.readStoredCertificate("example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", stored.Name)
+	assert.Equal(t, []string{"example.com"}, stored.Certificate.DNSNames)
+
+	require.Len(t, stored.Chain, 1)
+	assert.Equal(t, "CN=ca.example.com", stored.Chain[0].Subject.String())
+
+	assert.Equal(t, "https://acme.example.com/cert/1", stored.Metadata.CertURL)
+	assert.Equal(t, "tlsserver", stored.Metadata.Profile)
+	assert.Equal(t, "admin@example.com", stored.Metadata.Account)
+	assert.Equal(t, "https://acme.example.com/directory", stored.Metadata.Server)
+
+	now := time.Now()
+
+	info, err := newCertificateInfo(storage, stored, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", info.Name)
+	assert.Equal(t, "1", info.Serial)
+	assert.Equal(t, []string{"CN=ca.example.com"}, info.Chain)
+	assert.Equal(t, "ECDSA", info.KeyType)
+	assert.Equal(t, 256, info.KeySize)
+	assert.Equal(t, 0, info.DaysRemaining)
+	assert.Equal(t, "tlsserver", info.Profile)
+	assert.Equal(t, "admin@example.com", info.Account)
+	assert.Equal(t, storage.GetFileName("example.com", certExt), info.Path)
+
+	assert.Len(t, info.record(), len(certificateInfoHeader))
+}
+
+func TestCertificatesStorage_readStoredCertificate_noMetadata(t *testing.T) {
+	storage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	certPEM, _ := generateTestCertificate(t, "example.com")
+
+	err := storage.WriteFile("example.com", certExt, certPEM)
+	require.NoError(t, err)
+
+	stored, err := storage.readStoredCertificate("example.com")
+	require.NoError(t, err)
+
+	assert.Empty(t, stored.Chain)
+	assert.Empty(t, stored.Metadata.Profile)
+}
+
+func Test_publicKeyInfo(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	edPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc         string
+		cert         *x509.Certificate
+		expectedType string
+		expectedSize int
+	}{
+		{
+			desc:         "RSA",
+			cert:         &x509.Certificate{PublicKeyAlgorithm: x509.RSA, PublicKey: &rsaKey.PublicKey},
+			expectedType: "RSA",
+			expectedSize: 2048,
+		},
+		{
+			desc:         "ECDSA",
+			cert:         &x509.Certificate{PublicKeyAlgorithm: x509.ECDSA, PublicKey: &ecKey.PublicKey},
+			expectedType: "ECDSA",
+			expectedSize: 384,
+		},
+		{
+			desc:         "Ed25519",
+			cert:         &x509.Certificate{PublicKeyAlgorithm: x509.Ed25519, PublicKey: edPublicKey},
+			expectedType: "Ed25519",
+			expectedSize: 256,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			keyType, keySize := publicKeyInfo(test.cert)
+
+			assert.Equal(t, test.expectedType, keyType)
+			assert.Equal(t, test.expectedSize, keySize)
+		})
+	}
+}
+
+func Test_daysRemaining(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		desc     string
+		notAfter time.Time
+		expected int
+	}{
+		{desc: "30 days", notAfter: now.Add(30 * 24 * time.Hour), expected: 30},
+		{desc: "rounded down", notAfter: now.Add(30*24*time.Hour - time.Minute), expected: 29},
+		{desc: "expired", notAfter: now.Add(-48 * time.Hour), expected: -2},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, daysRemaining(&x509.Certificate{NotAfter: test.notAfter}, now))
+		})
+	}
+}
+
+func Test_newCertificateInfo_ipAddresses(t *testing.T) {
+	storage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		IPAddresses:  []net.IP{net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	stored := &storedCertificate{
+		Name:        "192.0.2.1",
+		Certificate: cert,
+		Metadata:    &resourceMetadata{Resource: &certificate.Resource{}},
+	}
+
+	info, err := newCertificateInfo(storage, stored, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, "192.0.2.1", info.Name)
+	assert.Equal(t, "2a", info.Serial)
+	assert.Equal(t, []string{"192.0.2.1", "2001:db8::1"}, info.IPAddresses)
+	assert.Equal(t, "192.0.2.1;2001:db8::1", info.record()[2])
+}
@@ -10,9 +10,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strconv"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
@@ -34,8 +33,14 @@ const (
 	pemExt      = ".pem"
 	pfxExt      = ".pfx"
 	resourceExt = ".json"
+	orderExt    = ".order.json"
+	orderKeyExt = ".order.key"
+	groupExt    = ".group.json"
+	lockExt     = ".lock"
 )
 
+var _ certificate.OrderStore = (*CertificatesStorage)(nil)
+
 // CertificatesStorage a certificates' storage.
 //
 // rootPath:
@@ -49,75 +54,125 @@ const (
 //	./.lego/archives/
 //	     │      └── archived certificates directory
 //	     └── "path" option
+//
+// With the "certbot-layout" option, the certificates are also stored with the Certbot layout (see saveLive):
+//
+//	./.lego/live/example.com/
+//	./.lego/versions/example.com/
+//
+// The files are stored in the storage selected by the "storage" option (local filesystem by default).
 type CertificatesStorage struct {
-	rootPath    string
-	archivePath string
-	pem         bool
-	pfx         bool
-	pfxPassword string
-	pfxFormat   string
-	filename    string // Deprecated
+	storage      Storage
+	rootPath     string
+	archivePath  string
+	livePath     string
+	versionsPath string
+	certbot      bool
+	archiveKeep  int
+	pem          bool
+	pfx          bool
+	pfxPassword  string
+	pfxFormat    string
+	filename     string // Deprecated
+
+	// The metadata stored with the certificates.
+	profile string
+	account string
+	server  string
 }
 
 // NewCertificatesStorage create a new certificates storage.
 func NewCertificatesStorage(ctx *cli.Context) *CertificatesStorage {
+	certsStorage, err := openCertificatesStorage(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return certsStorage
+}
+
+// openCertificatesStorage creates a new certificates storage.
+func openCertificatesStorage(ctx *cli.Context) (*CertificatesStorage, error) {
 	pfxFormat := ctx.String(flgPFXFormat)
 
 	switch pfxFormat {
 	case "DES", "RC2", "SHA256":
 	default:
-		log.Fatalf("Invalid PFX format: %s", pfxFormat)
+		return nil, fmt.Errorf("invalid PFX format: %s", pfxFormat)
 	}
 
-	return &CertificatesStorage{
-		rootPath:    filepath.Join(ctx.String(flgPath), baseCertificatesFolderName),
-		archivePath: filepath.Join(ctx.String(flgPath), baseArchivesFolderName),
-		pem:         ctx.Bool(flgPEM),
-		pfx:         ctx.Bool(flgPFX),
-		pfxPassword: ctx.String(flgPFXPass),
-		pfxFormat:   pfxFormat,
-		filename:    ctx.String(flgFilename),
+	storage, root, err := openStorage(ctx)
+	if err != nil {
+		return nil, err
 	}
-}
 
-func (s *CertificatesStorage) CreateRootFolder() {
-	err := createNonExistingFolder(s.rootPath)
-	if err != nil {
-		log.Fatalf("Could not check/create path: %v", err)
+	certbot := ctx.Bool(flgCertbotLayout)
+	if _, ok := storage.(linkStorage); certbot && !ok {
+		return nil, fmt.Errorf("the --%s option requires a storage supporting symbolic links (local storage)", flgCertbotLayout)
 	}
+
+	return &CertificatesStorage{
+		storage:      storage,
+		rootPath:     filepath.Join(root, baseCertificatesFolderName),
+		archivePath:  filepath.Join(root, baseArchivesFolderName),
+		livePath:     filepath.Join(root, baseLiveFolderName),
+		versionsPath: filepath.Join(root, baseVersionsFolderName),
+		certbot:      certbot,
+		archiveKeep:  ctx.Int(flgArchiveKeep),
+		pem:          ctx.Bool(flgPEM),
+		pfx:          ctx.Bool(flgPFX),
+		pfxPassword:  ctx.String(flgPFXPass),
+		pfxFormat:    pfxFormat,
+		filename:     ctx.String(flgFilename),
+		profile:      ctx.String(flgProfile),
+		account:      ctx.String(flgEmail),
+		server:       ctx.String(flgServer),
+	}, nil
+}
+
+func (s *CertificatesStorage) GetRootPath() string {
+	return s.files().Location(s.rootPath)
 }
 
-func (s *CertificatesStorage) CreateArchiveFolder() {
-	err := createNonExistingFolder(s.archivePath)
+// ListCertificates returns the names of the certificates (the files with the extension certExt).
+func (s *CertificatesStorage) ListCertificates() ([]string, error) {
+	names, err := s.files().List(s.rootPath)
 	if err != nil {
-		log.Fatalf("Could not check/create path: %v", err)
+		return nil, err
 	}
-}
 
-func (s *CertificatesStorage) GetRootPath() string {
-	return s.rootPath
+	var domains []string
+
+	for _, name := range names {
+		if filepath.Dir(name) != s.rootPath || !strings.HasSuffix(name, certExt) || strings.HasSuffix(name, issuerExt) {
+			continue
+		}
+
+		domains = append(domains, strings.TrimSuffix(filepath.Base(name), certExt))
+	}
+
+	return domains, nil
 }
 
+// SaveResource saves the certificate, its private key and its metadata.
+// The previous version of the certificate, if any, is copied to the archive directory.
+// The files are written together (see Storage.WriteFiles): the certificate cannot be saved without its private key.
 func (s *CertificatesStorage) SaveResource(certRes *certificate.Resource) {
 	domain := certRes.Domain
 
 	// We store the certificate, private key and metadata in different files
 	// as web servers would not be able to work with a combined file.
-	err := s.WriteFile(domain, certExt, certRes.Certificate)
-	if err != nil {
-		log.Fatalf("Unable to save Certificate for domain %s\n\t%v", domain, err)
+	files := map[string][]byte{
+		s.writeFileName(domain, certExt): certRes.Certificate,
 	}
 
 	if certRes.IssuerCertificate != nil {
-		err = s.WriteFile(domain, issuerExt, certRes.IssuerCertificate)
-		if err != nil {
-			log.Fatalf("Unable to save IssuerCertificate for domain %s\n\t%v", domain, err)
-		}
+		files[s.writeFileName(domain, issuerExt)] = certRes.IssuerCertificate
 	}
 
 	// if we were given a CSR, we don't know the private key
 	if certRes.PrivateKey != nil {
-		err = s.WriteCertificateFiles(domain, certRes)
+		err := s.addCertificateFiles(files, domain, certRes)
 		if err != nil {
 			log.Fatalf("Unable to save PrivateKey for domain %s\n\t%v", domain, err)
 		}
@@ -126,15 +181,82 @@ func (s *CertificatesStorage) SaveResource(certRes *certificate.Resource) {
 		log.Fatalf("Unable to save PEM or PFX without private key for domain %s. Are you using a CSR?", domain)
 	}
 
-	jsonBytes, err := json.MarshalIndent(certRes, "", "\t")
+	metadata := &resourceMetadata{
+		Resource: certRes,
+		Profile:  s.profile,
+		Account:  s.account,
+		Server:   s.server,
+	}
+
+	jsonBytes, err := json.MarshalIndent(metadata, "", "\t")
 	if err != nil {
 		log.Fatalf("Unable to marshal CertResource for domain %s\n\t%v", domain, err)
 	}
 
-	err = s.WriteFile(domain, resourceExt, jsonBytes)
+	files[s.writeFileName(domain, resourceExt)] = jsonBytes
+
+	// The replaced version is kept in the archive directory: it can be restored.
+	err = s.copyToArchive(domain)
+	if err != nil {
+		log.Fatalf("Unable to archive the current Certificate for domain %s\n\t%v", domain, err)
+	}
+
+	err = s.files().WriteFiles(files)
+	if err != nil {
+		log.Fatalf("Unable to save Certificate for domain %s\n\t%v", domain, err)
+	}
+
+	if s.certbot {
+		err = s.saveLive(certRes)
+		if err != nil {
+			log.Fatalf("Unable to save the live certificate for domain %s\n\t%v", domain, err)
+		}
+	}
+}
+
+// Lock takes the lock of a certificate, to serialize the lego processes working on the certificate.
+// The lock is released by calling the returned function.
+func (s *CertificatesStorage) Lock(domain string) func() {
+	unlock, err := s.lock(domain)
+	if err != nil {
+		log.Fatalf("Could not lock the certificate %s: %v", domain, err)
+	}
+
+	return unlock
+}
+
+func (s *CertificatesStorage) lock(domain string) (func(), error) {
+	unlock, err := s.files().Lock(s.getFileName(domain, lockExt))
 	if err != nil {
-		log.Fatalf("Unable to save CertResource for domain %s\n\t%v", domain, err)
+		return nil, err
 	}
+
+	return func() {
+		err := unlock()
+		if err != nil {
+			log.Warnf("Could not unlock the certificate %s: %v", domain, err)
+		}
+	}, nil
+}
+
+// ReadPrivateKey reads the private key of a certificate, and checks that the key matches the certificate.
+func (s *CertificatesStorage) ReadPrivateKey(domain string) ([]byte, error) {
+	keyBytes, err := s.ReadFile(domain, keyExt)
+	if err != nil {
+		return nil, err
+	}
+
+	certBytes, err := s.ReadFile(domain, certExt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkKeyMatch(certBytes, keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.GetFileName(domain, keyExt), err)
+	}
+
+	return keyBytes, nil
 }
 
 func (s *CertificatesStorage) ReadResource(domain string) certificate.Resource {
@@ -151,10 +273,148 @@ func (s *CertificatesStorage) ReadResource(domain string) certificate.Resource {
 	return resource
 }
 
-func (s *CertificatesStorage) ExistsFile(domain, extension string) bool {
-	filePath := s.GetFileName(domain, extension)
+// CertificateGroup a certificate split into several certificates (parts).
+type CertificateGroup struct {
+	Domain         string   `json:"domain"`
+	MaxIdentifiers int      `json:"maxIdentifiers"`
+	Parts          []string `json:"parts"`
+}
+
+// SaveGroup saves the parts of a split certificate and the group.
+// The parts of the previous group which are not used anymore are archived.
+func (s *CertificatesStorage) SaveGroup(domain string, maxIdentifiers int, resources []*certificate.Resource) {
+	s.saveGroup(domain, maxIdentifiers, resources, false)
+}
+
+// SavePartialGroup saves the parts obtained before the failure of a split certificate, and the group.
+// The parts of the previous group are kept.
+func (s *CertificatesStorage) SavePartialGroup(domain string, maxIdentifiers int, resources []*certificate.Resource) {
+	s.saveGroup(domain, maxIdentifiers, resources, true)
+}
+
+func (s *CertificatesStorage) saveGroup(domain string, maxIdentifiers int, resources []*certificate.Resource, partial bool) {
+	previous, err := s.ReadGroup(domain)
+	if err != nil {
+		log.Fatalf("Error while loading the group for domain %s\n\t%v", domain, err)
+	}
+
+	group := &CertificateGroup{Domain: domain, MaxIdentifiers: maxIdentifiers}
+
+	for _, resource := range resources {
+		s.SaveResource(resource)
+
+		group.Parts = append(group.Parts, resource.Domain)
+	}
+
+	if previous != nil {
+		for _, part := range previous.Parts {
+			if slices.Contains(group.Parts, part) {
+				continue
+			}
+
+			if partial {
+				group.Parts = append(group.Parts, part)
+				continue
+			}
+
+			err = s.MoveToArchive(part)
+			if err != nil {
+				log.Fatalf("Unable to archive the certificate %s\n\t%v", part, err)
+			}
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(group, "", "\t")
+	if err != nil {
+		log.Fatalf("Unable to marshal the group for domain %s\n\t%v", domain, err)
+	}
+
+	err = s.WriteFile(domain, groupExt, jsonBytes)
+	if err != nil {
+		log.Fatalf("Unable to save the group for domain %s\n\t%v", domain, err)
+	}
+}
+
+// ReadGroup reads the group of a split certificate.
+// Returns nil if the certificate is not split.
+func (s *CertificatesStorage) ReadGroup(domain string) (*CertificateGroup, error) {
+	raw, err := s.ReadFile(domain, groupExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var group CertificateGroup
+	err = json.Unmarshal(raw, &group)
+	if err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// LoadOrder loads the state of the in-flight order of a domain.
+// Implements certificate.OrderStore.
+func (s *CertificatesStorage) LoadOrder(domain string) (*certificate.OrderState, error) {
+	raw, err := s.ReadFile(domain, orderExt)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state certificate.OrderState
+	err = json.Unmarshal(raw, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	// The private key is stored in a dedicated file, the state only references it.
+	state.PrivateKey, err = s.ReadFile(domain, orderKeyExt)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// SaveOrder saves the state of the in-flight order of a domain.
+// Implements certificate.OrderStore.
+func (s *CertificatesStorage) SaveOrder(domain string, state *certificate.OrderState) error {
+	if len(state.PrivateKey) > 0 {
+		err := s.WriteFile(domain, orderKeyExt, state.PrivateKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	raw, err := json.MarshalIndent(state, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return s.WriteFile(domain, orderExt, raw)
+}
+
+// DeleteOrder deletes the state of the in-flight order of a domain.
+// Implements certificate.OrderStore.
+func (s *CertificatesStorage) DeleteOrder(domain string) error {
+	for _, ext := range []string{orderExt, orderKeyExt} {
+		err := s.files().Remove(s.getFileName(domain, ext))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+func (s *CertificatesStorage) ExistsFile(domain, extension string) bool {
+	_, err := s.ReadFile(domain, extension)
+	if errors.Is(err, os.ErrNotExist) {
 		return false
 	} else if err != nil {
 		log.Fatal(err)
@@ -163,14 +423,33 @@ func (s *CertificatesStorage) ExistsFile(domain, extension string) bool {
 }
 
 func (s *CertificatesStorage) ReadFile(domain, extension string) ([]byte, error) {
-	return os.ReadFile(s.GetFileName(domain, extension))
+	return s.files().ReadFile(s.getFileName(domain, extension))
+}
+
+// readSavedFile reads a file written by SaveResource, the name can be forced by the "filename" option.
+func (s *CertificatesStorage) readSavedFile(domain, extension string) ([]byte, error) {
+	return s.files().ReadFile(s.writeFileName(domain, extension))
 }
 
+// GetFileName returns the location of a file: the path of the file for the local storage.
 func (s *CertificatesStorage) GetFileName(domain, extension string) string {
+	return s.files().Location(s.getFileName(domain, extension))
+}
+
+func (s *CertificatesStorage) getFileName(domain, extension string) string {
 	filename := sanitizedDomain(domain) + extension
 	return filepath.Join(s.rootPath, filename)
 }
 
+// files returns the storage of the files (local filesystem by default).
+func (s *CertificatesStorage) files() Storage {
+	if s.storage == nil {
+		return &fileStorage{}
+	}
+
+	return s.storage
+}
+
 func (s *CertificatesStorage) ReadCertificate(domain, extension string) ([]*x509.Certificate, error) {
 	content, err := s.ReadFile(domain, extension)
 	if err != nil {
@@ -182,6 +461,11 @@ func (s *CertificatesStorage) ReadCertificate(domain, extension string) ([]*x509
 }
 
 func (s *CertificatesStorage) WriteFile(domain, extension string, data []byte) error {
+	return s.files().WriteFile(s.writeFileName(domain, extension), data)
+}
+
+// writeFileName returns the name of a file to write, the name can be forced by the "filename" option.
+func (s *CertificatesStorage) writeFileName(domain, extension string) string {
 	var baseFileName string
 	if s.filename != "" {
 		baseFileName = s.filename
@@ -189,53 +473,53 @@ func (s *CertificatesStorage) WriteFile(domain, extension string, data []byte) e
 		baseFileName = sanitizedDomain(domain)
 	}
 
-	filePath := filepath.Join(s.rootPath, baseFileName+extension)
-
-	return os.WriteFile(filePath, data, filePerm)
+	return filepath.Join(s.rootPath, baseFileName+extension)
 }
 
-func (s *CertificatesStorage) WriteCertificateFiles(domain string, certRes *certificate.Resource) error {
-	err := s.WriteFile(domain, keyExt, certRes.PrivateKey)
-	if err != nil {
-		return fmt.Errorf("unable to save key file: %w", err)
-	}
+// addCertificateFiles adds the private key, and the PEM and PFX files (if enabled) to the files to write.
+func (s *CertificatesStorage) addCertificateFiles(files map[string][]byte, domain string, certRes *certificate.Resource) error {
+	files[s.writeFileName(domain, keyExt)] = certRes.PrivateKey
 
 	if s.pem {
-		err = s.WriteFile(domain, pemExt, bytes.Join([][]byte{certRes.Certificate, certRes.PrivateKey}, nil))
-		if err != nil {
-			return fmt.Errorf("unable to save PEM file: %w", err)
-		}
+		files[s.writeFileName(domain, pemExt)] = bytes.Join([][]byte{certRes.Certificate, certRes.PrivateKey}, nil)
 	}
 
 	if s.pfx {
-		err = s.WritePFXFile(domain, certRes)
+		pfxBytes, err := s.encodePFX(domain, certRes)
 		if err != nil {
 			return fmt.Errorf("unable to save PFX file: %w", err)
 		}
+
+		files[s.writeFileName(domain, pfxExt)] = pfxBytes
 	}
 
 	return nil
 }
 
-func (s *CertificatesStorage) WritePFXFile(domain string, certRes *certificate.Resource) error {
+func (s *CertificatesStorage) encodePFX(domain string, certRes *certificate.Resource) ([]byte, error) {
+	return encodePKCS12(domain, certRes, s.pfxFormat, s.pfxPassword)
+}
+
+// encodePKCS12 encodes the certificate, its chain, and its private key to PKCS#12.
+func encodePKCS12(domain string, certRes *certificate.Resource, pfxFormat, password string) ([]byte, error) {
 	certPemBlock, _ := pem.Decode(certRes.Certificate)
 	if certPemBlock == nil {
-		return fmt.Errorf("unable to parse Certificate for domain %s", domain)
+		return nil, fmt.Errorf("unable to parse Certificate for domain %s", domain)
 	}
 
 	cert, err := x509.ParseCertificate(certPemBlock.Bytes)
 	if err != nil {
-		return fmt.Errorf("unable to load Certificate for domain %s: %w", domain, err)
+		return nil, fmt.Errorf("unable to load Certificate for domain %s: %w", domain, err)
 	}
 
 	certChain, err := getCertificateChain(certRes)
 	if err != nil {
-		return fmt.Errorf("unable to get certificate chain for domain %s: %w", domain, err)
+		return nil, fmt.Errorf("unable to get certificate chain for domain %s: %w", domain, err)
 	}
 
 	keyPemBlock, _ := pem.Decode(certRes.PrivateKey)
 	if keyPemBlock == nil {
-		return fmt.Errorf("unable to parse PrivateKey for domain %s", domain)
+		return nil, fmt.Errorf("unable to parse PrivateKey for domain %s", domain)
 	}
 
 	var privateKey crypto.Signer
@@ -245,51 +529,112 @@ func (s *CertificatesStorage) WritePFXFile(domain string, certRes *certificate.R
 	case "RSA PRIVATE KEY":
 		privateKey, keyErr = x509.ParsePKCS1PrivateKey(keyPemBlock.Bytes)
 		if keyErr != nil {
-			return fmt.Errorf("unable to load RSA PrivateKey for domain %s: %w", domain, keyErr)
+			return nil, fmt.Errorf("unable to load RSA PrivateKey for domain %s: %w", domain, keyErr)
 		}
 	case "EC PRIVATE KEY":
 		privateKey, keyErr = x509.ParseECPrivateKey(keyPemBlock.Bytes)
 		if keyErr != nil {
-			return fmt.Errorf("unable to load EC PrivateKey for domain %s: %w", domain, keyErr)
+			return nil, fmt.Errorf("unable to load EC PrivateKey for domain %s: %w", domain, keyErr)
 		}
 	default:
-		return fmt.Errorf("unsupported PrivateKey type '%s' for domain %s", keyPemBlock.Type, domain)
+		return nil, fmt.Errorf("unsupported PrivateKey type '%s' for domain %s", keyPemBlock.Type, domain)
 	}
 
-	encoder, err := getPFXEncoder(s.pfxFormat)
+	encoder, err := getPFXEncoder(pfxFormat)
 	if err != nil {
-		return fmt.Errorf("PFX encoder: %w", err)
+		return nil, fmt.Errorf("PFX encoder: %w", err)
 	}
 
-	pfxBytes, err := encoder.Encode(privateKey, cert, certChain, s.pfxPassword)
+	pfxBytes, err := encoder.Encode(privateKey, cert, certChain, password)
 	if err != nil {
-		return fmt.Errorf("unable to encode PFX data for domain %s: %w", domain, err)
+		return nil, fmt.Errorf("unable to encode PFX data for domain %s: %w", domain, err)
 	}
 
-	return s.WriteFile(domain, pfxExt, pfxBytes)
+	return pfxBytes, nil
 }
 
+// MoveToArchive moves the files of a certificate to the archive directory.
+// With the Certbot layout, the live directory is removed, the versions are kept.
 func (s *CertificatesStorage) MoveToArchive(domain string) error {
-	baseFilename := filepath.Join(s.rootPath, sanitizedDomain(domain))
+	if s.certbot {
+		err := s.removeLive(domain)
+		if err != nil {
+			return err
+		}
+	}
 
-	matches, err := filepath.Glob(baseFilename + ".*")
+	files, err := s.currentFiles(domain)
 	if err != nil {
 		return err
 	}
 
-	for _, oldFile := range matches {
-		if strings.TrimSuffix(oldFile, filepath.Ext(oldFile)) != baseFilename && oldFile != baseFilename+issuerExt {
+	id, err := s.archiveID(domain)
+	if err != nil {
+		return err
+	}
+
+	for _, oldFile := range files {
+		err = s.files().Rename(oldFile, s.archiveFileName(id, oldFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.pruneArchives(domain)
+}
+
+// currentFiles returns the files of a certificate: the files to archive.
+func (s *CertificatesStorage) currentFiles(domain string) ([]string, error) {
+	baseFilename := filepath.Join(s.rootPath, sanitizedDomain(domain))
+
+	names, err := s.files().List(s.rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+
+	for _, name := range names {
+		if !strings.HasPrefix(name, baseFilename+".") {
 			continue
 		}
 
-		date := strconv.FormatInt(time.Now().Unix(), 10)
-		filename := date + "." + filepath.Base(oldFile)
-		newFile := filepath.Join(s.archivePath, filename)
+		// The lock file is not archived: it can be held by the current process.
+		if name == baseFilename+lockExt {
+			continue
+		}
 
-		err = os.Rename(oldFile, newFile)
-		if err != nil {
-			return err
+		if strings.TrimSuffix(name, filepath.Ext(name)) != baseFilename &&
+			name != baseFilename+issuerExt && name != baseFilename+groupExt {
+			continue
 		}
+
+		files = append(files, name)
+	}
+
+	return files, nil
+}
+
+// checkKeyMatch checks that the private key matches the public key of the certificate.
+func checkKeyMatch(certBytes, keyBytes []byte) error {
+	cert, err := certcrypto.ParsePEMCertificate(certBytes)
+	if err != nil {
+		return err
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey(keyBytes)
+	if err != nil {
+		return err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return errors.New("unsupported private key")
+	}
+
+	publicKey, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !publicKey.Equal(signer.Public()) {
+		return errors.New("the private key doesn't match the certificate")
 	}
 
 	return nil
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+)
+
+// archiveExts the extensions of the archived files, the longest extensions first.
+var archiveExts = []string{issuerExt, groupExt, certExt, keyExt, pemExt, pfxExt, resourceExt}
+
+// ArchivedCertificate a version of a certificate in the archive directory.
+//
+//	./.lego/archives/1700000000.example.com.crt
+//	     │      │         │          └── certificate name
+//	     │      │         └── ID: the Unix time of the archiving
+//	     │      └── archived certificates directory
+//	     └── "path" option
+type ArchivedCertificate struct {
+	ID    int64
+	Name  string
+	Files map[string]string // Indexed by extension.
+}
+
+// Date returns the date of the archiving.
+func (a *ArchivedCertificate) Date() time.Time {
+	return time.Unix(a.ID, 0)
+}
+
+// ListArchives returns the archived versions of the certificates, sorted by name and by ID.
+func (s *CertificatesStorage) ListArchives() ([]*ArchivedCertificate, error) {
+	names, err := s.files().List(s.archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]*ArchivedCertificate)
+
+	for _, name := range names {
+		if filepath.Dir(name) != s.archivePath {
+			continue
+		}
+
+		id, certName, ext, ok := parseArchiveFileName(filepath.Base(name))
+		if !ok {
+			continue
+		}
+
+		key := strconv.FormatInt(id, 10) + "." + certName
+
+		version, ok := versions[key]
+		if !ok {
+			version = &ArchivedCertificate{ID: id, Name: certName, Files: make(map[string]string)}
+			versions[key] = version
+		}
+
+		version.Files[ext] = name
+	}
+
+	archives := slices.Collect(maps.Values(versions))
+
+	slices.SortFunc(archives, func(a, b *ArchivedCertificate) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+
+	return archives, nil
+}
+
+// ReadArchive returns an archived version of a certificate.
+func (s *CertificatesStorage) ReadArchive(domain string, id int64) (*ArchivedCertificate, error) {
+	archives, err := s.ListArchives()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, archive := range archives {
+		if archive.Name == sanitizedDomain(domain) && archive.ID == id {
+			return archive, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no archived version %d of the certificate %s: %w", id, domain, os.ErrNotExist)
+}
+
+// RestoreArchive restores an archived version of a certificate.
+// The current version is archived, and its files which are not part of the restored version are removed.
+func (s *CertificatesStorage) RestoreArchive(domain string, id int64) (*certificate.Resource, error) {
+	archive, err := s.ReadArchive(domain, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := archive.Files[certExt]; !ok {
+		return nil, fmt.Errorf("the archived version %d of the certificate %s has no certificate file", id, domain)
+	}
+
+	files := make(map[string][]byte)
+
+	for ext, name := range archive.Files {
+		// The group describes the current parts of a split certificate: it is not restored.
+		if ext == groupExt {
+			continue
+		}
+
+		data, err := s.files().ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		files[s.getFileName(domain, ext)] = data
+	}
+
+	current, err := s.currentFiles(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	// The current version is copied: it's still used until the restored version replaces it.
+	err = s.copyToArchive(domain)
+	if err != nil {
+		return nil, fmt.Errorf("unable to archive the current certificate: %w", err)
+	}
+
+	// The restored files are swapped in together,
+	// the files of the current version which are not restored are removed by the swap (see fileStorage.WriteFiles).
+	err = s.files().WriteFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	// The files which are not part of the swapped set (e.g. the remote storages).
+	for _, name := range current {
+		if _, ok := files[name]; ok || strings.HasSuffix(name, groupExt) {
+			continue
+		}
+
+		err = s.files().Remove(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certRes := &certificate.Resource{}
+
+	if data, ok := files[s.getFileName(domain, resourceExt)]; ok {
+		err = json.Unmarshal(data, certRes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certRes.Domain = domain
+	certRes.Certificate = files[s.getFileName(domain, certExt)]
+	certRes.IssuerCertificate = files[s.getFileName(domain, issuerExt)]
+	certRes.PrivateKey = files[s.getFileName(domain, keyExt)]
+
+	if s.certbot {
+		err = s.saveLive(certRes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return certRes, nil
+}
+
+// copyToArchive copies the current version of a certificate to the archive directory.
+func (s *CertificatesStorage) copyToArchive(domain string) error {
+	files, err := s.currentFiles(domain)
+	if err != nil {
+		return err
+	}
+
+	// The group is archived only with the main certificate (MoveToArchive).
+	files = slices.DeleteFunc(files, func(name string) bool {
+		return strings.HasSuffix(name, groupExt)
+	})
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	if s.archivePath == "" {
+		return fmt.Errorf("no archive directory for the certificate %s", domain)
+	}
+
+	id, err := s.archiveID(domain)
+	if err != nil {
+		return err
+	}
+
+	archived := make(map[string][]byte)
+
+	for _, name := range files {
+		data, err := s.files().ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		archived[s.archiveFileName(id, name)] = data
+	}
+
+	// The copies are written like the certificate files: the permissions of the local files are 0o600 (filePerm).
+	err = s.files().WriteFiles(archived)
+	if err != nil {
+		return err
+	}
+
+	return s.pruneArchives(domain)
+}
+
+// pruneArchives removes the oldest archived versions of a certificate, beyond the number of versions to keep (archive.keep option).
+func (s *CertificatesStorage) pruneArchives(domain string) error {
+	if s.archiveKeep <= 0 {
+		return nil
+	}
+
+	archives, err := s.ListArchives()
+	if err != nil {
+		return err
+	}
+
+	// The archives are sorted by ID: the oldest versions first.
+	versions := slices.DeleteFunc(archives, func(archive *ArchivedCertificate) bool {
+		return archive.Name != sanitizedDomain(domain)
+	})
+
+	for _, archive := range versions[:max(0, len(versions)-s.archiveKeep)] {
+		for _, name := range slices.Sorted(maps.Values(archive.Files)) {
+			err = s.files().Remove(name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// archiveID returns the ID of a new archived version of a certificate:
+// the current Unix time, or the next ID if the certificate has already been archived in the same second.
+func (s *CertificatesStorage) archiveID(domain string) (int64, error) {
+	archives, err := s.ListArchives()
+	if err != nil {
+		return 0, err
+	}
+
+	id := time.Now().Unix()
+
+	for _, archive := range archives {
+		if archive.Name == sanitizedDomain(domain) {
+			id = max(id, archive.ID+1)
+		}
+	}
+
+	return id, nil
+}
+
+func (s *CertificatesStorage) archiveFileName(id int64, name string) string {
+	return filepath.Join(s.archivePath, strconv.FormatInt(id, 10)+"."+filepath.Base(name))
+}
+
+// parseArchiveFileName parses the name of an archived file: <ID>.<certificate name><extension>.
+func parseArchiveFileName(filename string) (int64, string, string, bool) {
+	rawID, rest, ok := strings.Cut(filename, ".")
+	if !ok {
+		return 0, "", "", false
+	}
+
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return 0, "", "", false
+	}
+
+	for _, ext := range archiveExts {
+		name, found := strings.CutSuffix(rest, ext)
+		if found && name != "" {
+			return id, name, ext, true
+		}
+	}
+
+	return 0, "", "", false
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificatesStorage_RestoreArchive(t *testing.T) {
+	storage := CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+	}
+
+	firstCert, firstKey := generateTestCertificate(t, "*.example.com")
+
+	storage.SaveResource(&certificate.Resource{
+		Domain:      "*.example.com",
+		CertURL:     "https://example.com/acme/cert/1",
+		Certificate: firstCert,
+		PrivateKey:  firstKey,
+	})
+
+	archives, err := storage.ListArchives()
+	require.NoError(t, err)
+	assert.Empty(t, archives)
+
+	// The second version is saved with a PEM file.
+	storage.pem = true
+
+	secondCert, secondKey := generateTestCertificate(t, "*.example.com")
+
+	storage.SaveResource(&certificate.Resource{
+		Domain:      "*.example.com",
+		CertURL:     "https://example.com/acme/cert/2",
+		Certificate: secondCert,
+		PrivateKey:  secondKey,
+	})
+
+	archives, err = storage.ListArchives()
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+
+	first := archives[0]
+	assert.Equal(t, "_.example.com", first.Name)
+	assert.ElementsMatch(t, []string{certExt, keyExt, resourceExt}, slices.Collect(maps.Keys(first.Files)))
+
+	certRes, err := storage.RestoreArchive("*.example.com", first.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "*.example.com", certRes.Domain)
+	assert.Equal(t, "https://example.com/acme/cert/1", certRes.CertURL)
+	assert.Equal(t, firstCert, certRes.Certificate)
+	assert.Equal(t, firstKey, certRes.PrivateKey)
+
+	data, err := storage.ReadFile("*.example.com", certExt)
+	require.NoError(t, err)
+	assert.Equal(t, firstCert, data)
+
+	// The PEM file is not part of the restored version.
+	assert.NoFileExists(t, filepath.Join(storage.rootPath, "_.example.com.pem"))
+
+	// The replaced version is archived with the next ID.
+	archives, err = storage.ListArchives()
+	require.NoError(t, err)
+	require.Len(t, archives, 2)
+
+	second := archives[1]
+	assert.Greater(t, second.ID, first.ID)
+	assert.ElementsMatch(t, []string{certExt, keyExt, pemExt, resourceExt}, slices.Collect(maps.Keys(second.Files)))
+
+	_, err = storage.RestoreArchive("*.example.com", 42)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCertificatesStorage_pruneArchives(t *testing.T) {
+	storage := CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+		archiveKeep: 2,
+	}
+
+	var certs [][]byte
+
+	for range 4 {
+		certPEM, keyPEM := generateTestCertificate(t, "example.com")
+
+		storage.SaveResource(&certificate.Resource{
+			Domain:      "example.com",
+			Certificate: certPEM,
+			PrivateKey:  keyPEM,
+		})
+
+		certs = append(certs, certPEM)
+	}
+
+	// The oldest version has been removed.
+	archives, err := storage.ListArchives()
+	require.NoError(t, err)
+	require.Len(t, archives, 2)
+
+	for i, archive := range archives {
+		data, errR := os.ReadFile(archive.Files[certExt])
+		require.NoError(t, errR)
+		assert.Equal(t, certs[i+1], data)
+
+		// The archived private key is only readable by the owner.
+		info, errR := os.Stat(archive.Files[keyExt])
+		require.NoError(t, errR)
+
+		if runtime.GOOS != "windows" {
+			assert.Equal(t, filePerm, info.Mode().Perm())
+		}
+	}
+
+	// The files of the removed version are removed (3 files per version).
+	names, err := storage.files().List(storage.archivePath)
+	require.NoError(t, err)
+	assert.Len(t, names, 6)
+
+	if symlinkSets {
+		sets, errR := os.ReadDir(filepath.Join(storage.archivePath, setsDirName))
+		require.NoError(t, errR)
+		assert.Len(t, sets, 2)
+	}
+}
+
+func TestCertificatesStorage_copyToArchive_noArchivePath(t *testing.T) {
+	storage := CertificatesStorage{rootPath: t.TempDir()}
+
+	certPEM, keyPEM := generateTestCertificate(t, "example.com")
+
+	storage.SaveResource(&certificate.Resource{
+		Domain:      "example.com",
+		Certificate: certPEM,
+		PrivateKey:  keyPEM,
+	})
+
+	err := storage.copyToArchive("example.com")
+	require.EqualError(t, err, "no archive directory for the certificate example.com")
+}
+
+func Test_parseArchiveFileName(t *testing.T) {
+	testCases := []struct {
+		filename string
+		id       int64
+		name     string
+		ext      string
+		ok       bool
+	}{
+		{filename: "1700000000.example.com.crt", id: 1700000000, name: "example.com", ext: certExt, ok: true},
+		{filename: "1700000000.example.com.issuer.crt", id: 1700000000, name: "example.com", ext: issuerExt, ok: true},
+		{filename: "1700000000._.example.com.group.json", id: 1700000000, name: "_.example.com", ext: groupExt, ok: true},
+		{filename: "1700000000.example.com.json", id: 1700000000, name: "example.com", ext: resourceExt, ok: true},
+		{filename: "example.com.crt"},
+		{filename: "1700000000.example.com.lock"},
+		{filename: "1700000000.crt"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.filename, func(t *testing.T) {
+			t.Parallel()
+
+			id, name, ext, ok := parseArchiveFileName(test.filename)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.id, id)
+			assert.Equal(t, test.name, name)
+			assert.Equal(t, test.ext, ext)
+		})
+	}
+}
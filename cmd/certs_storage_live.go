@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+)
+
+const (
+	baseLiveFolderName     = "live"
+	baseVersionsFolderName = "versions"
+)
+
+// liveFiles the prefixes of the files of the Certbot layout.
+var liveFiles = []string{"cert", "chain", "fullchain", "privkey"}
+
+var liveVersionRe = regexp.MustCompile(`^cert(\d+)\.pem$`)
+
+// linkStorage a storage supporting symbolic links.
+type linkStorage interface {
+	// SymlinkDir creates, or replaces atomically, a directory of symbolic links (targets indexed by link name).
+	SymlinkDir(dir string, links map[string]string) error
+
+	// RemoveSymlinkDir removes a directory of symbolic links, it's not an error if the directory doesn't exist.
+	RemoveSymlinkDir(dir string) error
+}
+
+// saveLive saves a new version of a certificate with the Certbot layout.
+// Every version is kept, the symbolic links of the live directory point to the last version.
+// The live directory is replaced as a whole: the links are replaced together,
+// and the links which are not part of the new version (e.g. privkey.pem for a CSR) are removed.
+//
+// versionsPath:
+//
+//	./.lego/versions/example.com/cert1.pem, chain1.pem, fullchain1.pem, privkey1.pem, cert2.pem, ...
+//
+// livePath:
+//
+//	./.lego/live/example.com/cert.pem -> ../../versions/example.com/cert2.pem
+func (s *CertificatesStorage) saveLive(certRes *certificate.Resource) error {
+	links, ok := s.files().(linkStorage)
+	if !ok {
+		return errors.New("the storage doesn't support symbolic links")
+	}
+
+	name := sanitizedDomain(certRes.Domain)
+
+	contents, err := liveContents(certRes)
+	if err != nil {
+		return err
+	}
+
+	version, err := s.lastLiveVersion(name)
+	if err != nil {
+		return err
+	}
+
+	version++
+
+	targets := make(map[string]string)
+
+	// The files of a version are not used before the replacement of the live directory.
+	for _, prefix := range liveFiles {
+		data, ok := contents[prefix]
+		if !ok {
+			continue
+		}
+
+		err = s.files().WriteFile(filepath.Join(s.versionsPath, name, liveFileName(prefix, version)), data)
+		if err != nil {
+			return err
+		}
+
+		// The live and versions directories are in the same directory.
+		targets[prefix+".pem"] = filepath.Join("..", "..", baseVersionsFolderName, name, liveFileName(prefix, version))
+	}
+
+	return links.SymlinkDir(filepath.Join(s.livePath, name), targets)
+}
+
+// removeLive removes the live directory of a certificate, the versions are kept.
+func (s *CertificatesStorage) removeLive(domain string) error {
+	links, ok := s.files().(linkStorage)
+	if !ok {
+		return errors.New("the storage doesn't support symbolic links")
+	}
+
+	return links.RemoveSymlinkDir(filepath.Join(s.livePath, sanitizedDomain(domain)))
+}
+
+// GetLivePath returns the location of the live directory of a certificate.
+func (s *CertificatesStorage) GetLivePath(domain string) string {
+	return s.files().Location(filepath.Join(s.livePath, sanitizedDomain(domain)))
+}
+
+// lastLiveVersion returns the last version of a certificate (0 if there is no version).
+func (s *CertificatesStorage) lastLiveVersion(name string) (int, error) {
+	names, err := s.files().List(filepath.Join(s.versionsPath, name))
+	if err != nil {
+		return 0, err
+	}
+
+	var version int
+
+	for _, filename := range names {
+		match := liveVersionRe.FindStringSubmatch(filepath.Base(filename))
+		if match == nil {
+			continue
+		}
+
+		v, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+
+		version = max(version, v)
+	}
+
+	return version, nil
+}
+
+// liveContents returns the content of the files of the Certbot layout, indexed by prefix.
+// A certificate obtained with a CSR has no private key.
+func liveContents(certRes *certificate.Resource) (map[string][]byte, error) {
+	certificates, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	cert := certcrypto.PEMEncode(certcrypto.DERCertificateBytes(certificates[0].Raw))
+
+	// The certificate can be a bundle.
+	chain := certRes.IssuerCertificate
+	if len(certificates) > 1 {
+		chain = nil
+
+		for _, c := range certificates[1:] {
+			chain = append(chain, certcrypto.PEMEncode(certcrypto.DERCertificateBytes(c.Raw))...)
+		}
+	}
+
+	contents := map[string][]byte{
+		"cert":      cert,
+		"chain":     chain,
+		"fullchain": slices.Concat(cert, chain),
+	}
+
+	if certRes.PrivateKey != nil {
+		contents["privkey"] = certRes.PrivateKey
+	}
+
+	return contents, nil
+}
+
+func liveFileName(prefix string, version int) string {
+	return fmt.Sprintf("%s%d.pem", prefix, version)
+}
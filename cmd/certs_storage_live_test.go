@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificatesStorage_certbotLayout(t *testing.T) {
+	root := t.TempDir()
+
+	storage := CertificatesStorage{
+		rootPath:     filepath.Join(root, baseCertificatesFolderName),
+		archivePath:  filepath.Join(root, baseArchivesFolderName),
+		livePath:     filepath.Join(root, baseLiveFolderName),
+		versionsPath: filepath.Join(root, baseVersionsFolderName),
+		certbot:      true,
+	}
+
+	issuerPEM, _ := generateTestCertificate(t, "ca.example.com")
+
+	var certs [][]byte
+
+	for range 2 {
+		certPEM, keyPEM := generateTestCertificate(t, "*.example.com")
+
+		storage.SaveResource(&certificate.Resource{
+			Domain:            "*.example.com",
+			Certificate:       slices.Concat(certPEM, issuerPEM),
+			IssuerCertificate: issuerPEM,
+			PrivateKey:        keyPEM,
+		})
+
+		certs = append(certs, certPEM)
+	}
+
+	versions, err := os.ReadDir(filepath.Join(storage.versionsPath, "_.example.com"))
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range versions {
+		names = append(names, entry.Name())
+	}
+
+	expected := []string{
+		"cert1.pem", "cert2.pem", "chain1.pem", "chain2.pem",
+		"fullchain1.pem", "fullchain2.pem", "privkey1.pem", "privkey2.pem",
+	}
+	assert.Equal(t, expected, names)
+
+	liveDir := filepath.Join(storage.livePath, "_.example.com")
+
+	// The live files point to the last version.
+	for _, prefix := range liveFiles {
+		target, err := os.Readlink(filepath.Join(liveDir, prefix+".pem"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("..", "..", "versions", "_.example.com", prefix+"2.pem"), target)
+	}
+
+	// The live directory is replaced as a whole: only the last directory of links is kept.
+	entries, err := os.ReadDir(storage.livePath)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	target, err := os.Readlink(liveDir)
+	require.NoError(t, err)
+	assert.Contains(t, []string{entries[0].Name(), entries[1].Name()}, target)
+
+	assertFileContent(t, filepath.Join(liveDir, "cert.pem"), certs[1])
+	assertFileContent(t, filepath.Join(liveDir, "chain.pem"), issuerPEM)
+	assertFileContent(t, filepath.Join(liveDir, "fullchain.pem"), slices.Concat(certs[1], issuerPEM))
+
+	// The lego files are still written.
+	assert.FileExists(t, filepath.Join(storage.rootPath, "_.example.com.crt"))
+
+	err = storage.MoveToArchive("*.example.com")
+	require.NoError(t, err)
+
+	assert.NoDirExists(t, liveDir)
+
+	entries, err = os.ReadDir(storage.livePath)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.FileExists(t, filepath.Join(storage.versionsPath, "_.example.com", "cert2.pem"))
+}
+
+func TestCertificatesStorage_certbotLayout_csr(t *testing.T) {
+	root := t.TempDir()
+
+	storage := CertificatesStorage{
+		rootPath:     filepath.Join(root, baseCertificatesFolderName),
+		archivePath:  filepath.Join(root, baseArchivesFolderName),
+		livePath:     filepath.Join(root, baseLiveFolderName),
+		versionsPath: filepath.Join(root, baseVersionsFolderName),
+		certbot:      true,
+	}
+
+	issuerPEM, _ := generateTestCertificate(t, "ca.example.com")
+
+	previousPEM, keyPEM := generateTestCertificate(t, "example.com")
+
+	storage.SaveResource(&certificate.Resource{
+		Domain:            "example.com",
+		Certificate:       previousPEM,
+		IssuerCertificate: issuerPEM,
+		PrivateKey:        keyPEM,
+	})
+
+	liveDir := filepath.Join(storage.livePath, "example.com")
+
+	assertFileContent(t, filepath.Join(liveDir, "privkey.pem"), keyPEM)
+
+	certPEM, _ := generateTestCertificate(t, "example.com")
+
+	storage.SaveResource(&certificate.Resource{
+		Domain:            "example.com",
+		Certificate:       certPEM,
+		IssuerCertificate: issuerPEM,
+	})
+
+	assertFileContent(t, filepath.Join(liveDir, "fullchain.pem"), slices.Concat(certPEM, issuerPEM))
+
+	// The private key of the previous version doesn't match the certificate.
+	_, err := os.Lstat(filepath.Join(liveDir, "privkey.pem"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func assertFileContent(t *testing.T, filename string, expected []byte) {
+	t.Helper()
+
+	data, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(data))
+}
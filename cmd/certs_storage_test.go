@@ -1,11 +1,20 @@
 package cmd
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"os"
 	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -113,3 +122,226 @@ func generateTestFiles(t *testing.T, dir, domain string) []string {
 
 	return filenames
 }
+
+func TestCertificatesStorage_order(t *testing.T) {
+	domain := "*.example.com"
+
+	storage := CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+	}
+
+	state, err := storage.LoadOrder(domain)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	expected := &certificate.OrderState{
+		OrderURL:          "https://example.com/acme/order/1",
+		AuthorizationURLs: []string{"https://example.com/acme/authz/1"},
+		Domains:           []string{"*.example.com", "example.com"},
+		PrivateKey:        []byte("private key"),
+	}
+
+	err = storage.SaveOrder(domain, expected)
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(storage.rootPath, "_.example.com.order.json"))
+	assert.FileExists(t, filepath.Join(storage.rootPath, "_.example.com.order.key"))
+
+	state, err = storage.LoadOrder(domain)
+	require.NoError(t, err)
+	assert.Equal(t, expected, state)
+
+	err = storage.DeleteOrder(domain)
+	require.NoError(t, err)
+
+	root, err := os.ReadDir(storage.rootPath)
+	require.NoError(t, err)
+	assert.Empty(t, root)
+}
+
+func TestCertificatesStorage_group(t *testing.T) {
+	domain := "*.example.com"
+
+	storage := CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+	}
+
+	group, err := storage.ReadGroup(domain)
+	require.NoError(t, err)
+	assert.Nil(t, group)
+
+	var resources []*certificate.Resource
+	for i := range 3 {
+		resources = append(resources, &certificate.Resource{
+			Domain:      certificate.SplitName(domain, i),
+			Certificate: []byte("cert"),
+			PrivateKey:  []byte("key"),
+		})
+	}
+
+	storage.SaveGroup(domain, 2, resources)
+
+	group, err = storage.ReadGroup(domain)
+	require.NoError(t, err)
+
+	expected := &CertificateGroup{
+		Domain:         domain,
+		MaxIdentifiers: 2,
+		Parts:          []string{"*.example.com", "*.example.com-part2", "*.example.com-part3"},
+	}
+	assert.Equal(t, expected, group)
+
+	assert.FileExists(t, filepath.Join(storage.rootPath, "_.example.com.group.json"))
+	assert.FileExists(t, filepath.Join(storage.rootPath, "_.example.com-part3.crt"))
+
+	// Only the first part has been obtained: the other parts are kept.
+	storage.SavePartialGroup(domain, 2, resources[:1])
+
+	group, err = storage.ReadGroup(domain)
+	require.NoError(t, err)
+	assert.Equal(t, expected, group)
+
+	assert.FileExists(t, filepath.Join(storage.rootPath, "_.example.com-part3.crt"))
+
+	// The group shrinks: the unused part is archived.
+	storage.SaveGroup(domain, 3, resources[:2])
+
+	group, err = storage.ReadGroup(domain)
+	require.NoError(t, err)
+
+	expected = &CertificateGroup{
+		Domain:         domain,
+		MaxIdentifiers: 3,
+		Parts:          []string{"*.example.com", "*.example.com-part2"},
+	}
+	assert.Equal(t, expected, group)
+
+	assert.NoFileExists(t, filepath.Join(storage.rootPath, "_.example.com-part3.crt"))
+
+	// The replaced parts are kept in the archive, the unused part is moved (3 files per part).
+	archive, err := storage.files().List(storage.archivePath)
+	require.NoError(t, err)
+	assert.Len(t, archive, 12)
+
+	// The group is archived with the main certificate.
+	err = storage.MoveToArchive(domain)
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, filepath.Join(storage.rootPath, "_.example.com.group.json"))
+}
+
+func TestCertificatesStorage_SaveResource(t *testing.T) {
+	storage := CertificatesStorage{
+		rootPath: t.TempDir(),
+		pem:      true,
+	}
+
+	certPEM, keyPEM := generateTestCertificate(t, "example.com")
+
+	storage.SaveResource(&certificate.Resource{
+		Domain:      "example.com",
+		Certificate: certPEM,
+		PrivateKey:  keyPEM,
+	})
+
+	root, err := os.ReadDir(storage.rootPath)
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range root {
+		names = append(names, entry.Name())
+	}
+
+	expected := []string{"example.com.crt", "example.com.json", "example.com.key", "example.com.pem"}
+
+	if symlinkSets {
+		// The files are links to the current version of the set.
+		expected = append([]string{setsDirName}, expected...)
+
+		versions, errR := os.ReadDir(filepath.Join(storage.rootPath, setsDirName, "example.com.crt"))
+		require.NoError(t, errR)
+		assert.Len(t, versions, 2)
+	}
+
+	// No temporary files.
+	assert.Equal(t, expected, names)
+
+	keyBytes, err := storage.ReadPrivateKey("example.com")
+	require.NoError(t, err)
+	assert.Equal(t, keyPEM, keyBytes)
+}
+
+func TestCertificatesStorage_ReadPrivateKey_mismatch(t *testing.T) {
+	storage := CertificatesStorage{rootPath: t.TempDir()}
+
+	certPEM, _ := generateTestCertificate(t, "example.com")
+	_, otherKeyPEM := generateTestCertificate(t, "example.com")
+
+	err := storage.WriteFile("example.com", certExt, certPEM)
+	require.NoError(t, err)
+
+	err = storage.WriteFile("example.com", keyExt, otherKeyPEM)
+	require.NoError(t, err)
+
+	_, err = storage.ReadPrivateKey("example.com")
+	require.ErrorContains(t, err, "the private key doesn't match the certificate")
+}
+
+func TestCertificatesStorage_Lock(t *testing.T) {
+	storage := CertificatesStorage{
+		rootPath:    t.TempDir(),
+		archivePath: t.TempDir(),
+	}
+
+	unlock := storage.Lock("*.example.com")
+
+	lockFile := filepath.Join(storage.rootPath, "_.example.com.lock")
+	require.FileExists(t, lockFile)
+
+	// The lock is held by another "process" (another open file description).
+	file, err := os.OpenFile(lockFile, os.O_RDWR, 0)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	ok, err := tryLockFile(file, true)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The lock file is not archived.
+	generateTestFiles(t, storage.rootPath, "_.example.com")
+
+	err = storage.MoveToArchive("*.example.com")
+	require.NoError(t, err)
+	assert.FileExists(t, lockFile)
+
+	unlock()
+
+	ok, err = tryLockFile(file, true)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, unlockFile(file))
+}
+
+func generateTestCertificate(t *testing.T, domain string) ([]byte, []byte) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	require.NoError(t, err)
+
+	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der)), certcrypto.PEMEncode(privateKey)
+}
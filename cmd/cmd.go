@@ -10,5 +10,11 @@ func CreateCommands() []*cli.Command {
 		createRenew(),
 		createDNSHelp(),
 		createList(),
+		createKeyChange(),
+		createPreAuthorize(),
+		createDNSPersist(),
+		createDaemon(),
+		createArchive(),
+		createCheck(),
 	}
 }
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgArchiveID = "id"
+)
+
+func createArchive() *cli.Command {
+	return &cli.Command{
+		Name: "archive",
+		Usage: "Manage the archived versions of the certificates." +
+			" A version is archived each time a certificate is replaced, and when a certificate is revoked.",
+		Subcommands: []*cli.Command{
+			{
+				Name: "list",
+				Usage: "Display the archived versions of the certificates." +
+					" All the certificates, or only the certificates named with --" + flgDomains + ".",
+				Action: archiveList,
+			},
+			{
+				Name:   "restore",
+				Usage:  "Restore an archived version of the certificate named with --" + flgDomains + ". The current version is archived.",
+				Action: archiveRestore,
+				Flags: []cli.Flag{
+					&cli.Int64Flag{
+						Name:     flgArchiveID,
+						Usage:    "The ID of the archived version to restore (see 'archive list').",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  flgRenewHook,
+						Usage: "Define a hook. The hook is executed when the certificate is restored.",
+					},
+					&cli.DurationFlag{
+						Name:  flgRenewHookTimeout,
+						Usage: "Define the timeout for the hook execution.",
+						Value: 2 * time.Minute,
+					},
+				},
+			},
+		},
+	}
+}
+
+func archiveList(ctx *cli.Context) error {
+	certsStorage := NewCertificatesStorage(ctx)
+
+	archives, err := certsStorage.ListArchives()
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, domain := range ctx.StringSlice(flgDomains) {
+		names = append(names, sanitizedDomain(domain))
+	}
+
+	if len(names) > 0 {
+		archives = slices.DeleteFunc(archives, func(archive *ArchivedCertificate) bool {
+			return !slices.Contains(names, archive.Name)
+		})
+	}
+
+	if len(archives) == 0 {
+		fmt.Println("No archived certificates found.")
+		return nil
+	}
+
+	fmt.Println("Found the following archived certs:")
+
+	var previous string
+
+	for _, archive := range archives {
+		if archive.Name != previous {
+			fmt.Println("  Certificate Name:", archive.Name)
+			previous = archive.Name
+		}
+
+		fmt.Println("    ID:", archive.ID)
+		fmt.Println("      Archive Date:", archive.Date())
+
+		filename, ok := archive.Files[certExt]
+		if !ok {
+			fmt.Println("      No certificate file.")
+			fmt.Println()
+
+			continue
+		}
+
+		data, err := certsStorage.files().ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		certificates, err := certcrypto.ParsePEMBundle(data)
+		if err != nil {
+			return err
+		}
+
+		cert := certificates[0]
+
+		fmt.Println("      Domains:", strings.Join(cert.DNSNames, ", "))
+		fmt.Println("      Serial Number:", fmt.Sprintf("%x", cert.SerialNumber))
+		fmt.Println("      Issuer:", cert.Issuer.String())
+		fmt.Println("      Expiry Date:", cert.NotAfter)
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func archiveRestore(ctx *cli.Context) error {
+	domains := ctx.StringSlice(flgDomains)
+	if len(domains) == 0 {
+		log.Fatalf("Please specify --%s or -d", flgDomains)
+	}
+
+	domain := domains[0]
+	id := ctx.Int64(flgArchiveID)
+
+	certsStorage := NewCertificatesStorage(ctx)
+
+	defer certsStorage.Lock(domain)()
+
+	certRes, err := certsStorage.RestoreArchive(domain, id)
+	if err != nil {
+		log.Fatalf("Could not restore the version %d of the certificate %s: %v", id, domain, err)
+	}
+
+	log.Infof("[%s] The version %d of the certificate has been restored.", domain, id)
+
+	err = deploy(ctx, certsStorage, certRes)
+	if err != nil {
+		return err
+	}
+
+	hooks := newHookRunner(ctx, ctx.String(flgEmail), ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout))
+
+	return hooks.post(certRes, certsStorage)
+}
@@ -1,22 +1,35 @@
 package cmd
 
 import (
-	"github.com/go-acme/lego/v4/log"
+	"fmt"
+
 	"github.com/urfave/cli/v2"
 )
 
 func Before(ctx *cli.Context) error {
 	if ctx.String(flgPath) == "" {
-		log.Fatalf("Could not determine current working directory. Please pass --%s.", flgPath)
+		return fmt.Errorf("could not determine current working directory: please pass --%s", flgPath)
+	}
+
+	if ctx.String(flgStorage) == "" {
+		err := createNonExistingFolder(ctx.String(flgPath))
+		if err != nil {
+			return fmt.Errorf("could not check/create path: %w", err)
+		}
+	}
+
+	_, err := newDeployers(ctx.StringSlice(flgDeploy))
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", flgDeploy, err)
 	}
 
-	err := createNonExistingFolder(ctx.String(flgPath))
+	err = checkNotifyURLs(ctx.StringSlice(flgNotifyURL))
 	if err != nil {
-		log.Fatalf("Could not check/create path: %v", err)
+		return fmt.Errorf("invalid --%s: %w", flgNotifyURL, err)
 	}
 
 	if ctx.String(flgServer) == "" {
-		log.Fatalf("Could not determine current working server. Please pass --%s.", flgServer)
+		return fmt.Errorf("could not determine current working server: please pass --%s", flgServer)
 	}
 
 	return nil
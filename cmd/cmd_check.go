@@ -0,0 +1,637 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/ocsp"
+)
+
+// Flag names.
+const (
+	flgCheckWarning    = "warning"
+	flgCheckCritical   = "critical"
+	flgCheckARI        = "ari"
+	flgCheckRevocation = "revocation"
+	flgCheckRoots      = "roots"
+)
+
+// Maximum sizes of the revocation responses.
+const (
+	// maxCRLSize the maximum size of a CRL.
+	maxCRLSize = 20 * 1024 * 1024
+
+	// maxOCSPSize the maximum size of an OCSP response.
+	maxOCSPSize = 1024 * 1024
+)
+
+// checkStatus the status of a check, the values are the exit codes of the Nagios plugins.
+type checkStatus int
+
+// Check statuses.
+const (
+	statusOK checkStatus = iota
+	statusWarning
+	statusCritical
+	statusUnknown
+)
+
+func (s checkStatus) String() string {
+	switch s {
+	case statusOK:
+		return "OK"
+	case statusWarning:
+		return "WARNING"
+	case statusCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s checkStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// severity the order of the statuses: CRITICAL > WARNING > UNKNOWN > OK.
+func (s checkStatus) severity() int {
+	switch s {
+	case statusOK:
+		return 0
+	case statusUnknown:
+		return 1
+	case statusWarning:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// worst returns the most severe status.
+func worst(a, b checkStatus) checkStatus {
+	if b.severity() > a.severity() {
+		return b
+	}
+
+	return a
+}
+
+// checkResult the result of a check of a certificate.
+type checkResult struct {
+	Name    string      `json:"name"`
+	Status  checkStatus `json:"status"`
+	Message string      `json:"message"`
+}
+
+// certificateCheck the results of the checks of a certificate.
+type certificateCheck struct {
+	Name          string         `json:"name"`
+	Status        checkStatus    `json:"status"`
+	Domains       []string       `json:"domains,omitempty"`
+	Serial        string         `json:"serial,omitempty"`
+	NotAfter      *time.Time     `json:"notAfter,omitempty"`
+	DaysRemaining *int           `json:"daysRemaining,omitempty"`
+	Path          string         `json:"path,omitempty"`
+	Checks        []*checkResult `json:"checks"`
+}
+
+func (c *certificateCheck) add(name string, status checkStatus, msg string, args ...any) {
+	c.Status = worst(c.Status, status)
+	c.Checks = append(c.Checks, &checkResult{Name: name, Status: status, Message: fmt.Sprintf(msg, args...)})
+}
+
+// checkReport the report of the check command.
+type checkReport struct {
+	Status       checkStatus         `json:"status"`
+	Message      string              `json:"message"`
+	Certificates []*certificateCheck `json:"certificates"`
+}
+
+func (r *checkReport) add(check *certificateCheck) {
+	r.Status = worst(r.Status, check.Status)
+	r.Certificates = append(r.Certificates, check)
+}
+
+func createCheck() *cli.Command {
+	return &cli.Command{
+		Name: "check",
+		Usage: "Check the stored certificates: private key, chain, and expiry date." +
+			" All the certificates, or only the certificates named with --" + flgDomains + "." +
+			" The exit code follows the Nagios plugins: 0 (OK), 1 (WARNING), 2 (CRITICAL), 3 (UNKNOWN).",
+		Action: check,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  flgCheckWarning,
+				Usage: "The number of days left on a certificate to report a warning.",
+				Value: 30,
+			},
+			&cli.IntFlag{
+				Name:  flgCheckCritical,
+				Usage: "The number of days left on a certificate to report a critical status.",
+				Value: 7,
+			},
+			&cli.BoolFlag{
+				Name:  flgCheckARI,
+				Usage: "Check the renewal window suggested by the renewalInfo endpoint (ARI) of the server.",
+			},
+			&cli.BoolFlag{
+				Name:  flgCheckRevocation,
+				Usage: "Check the revocation status of the certificates (OCSP, or CRL if the certificate has no OCSP server).",
+			},
+			&cli.StringSliceFlag{
+				Name:  flgCheckRoots,
+				Usage: "The root certificates (PEM files) used to validate the chains, in addition to the system roots.",
+			},
+			&cli.StringFlag{
+				Name:  flgFormat,
+				Usage: "The output format: text or json.",
+				Value: formatText,
+			},
+		},
+	}
+}
+
+func check(ctx *cli.Context) error {
+	format := ctx.String(flgFormat)
+	if format != formatText && format != formatJSON {
+		return cli.Exit(fmt.Sprintf("unsupported format: %s", format), int(statusUnknown))
+	}
+
+	report, err := checkCertificates(ctx)
+	if err != nil {
+		return cli.Exit(err, int(statusUnknown))
+	}
+
+	if format == formatJSON {
+		err = writeJSON(report)
+	} else {
+		writeCheckReport(report, ctx.Int(flgCheckWarning), ctx.Int(flgCheckCritical))
+	}
+
+	if err != nil {
+		return cli.Exit(err, int(statusUnknown))
+	}
+
+	if report.Status == statusOK {
+		return nil
+	}
+
+	return cli.Exit("", int(report.Status))
+}
+
+// checkCertificates checks the certificates of the configuration file,
+// the certificates named with --domains, or all the certificates of the storage.
+func checkCertificates(ctx *cli.Context) (*checkReport, error) {
+	report := &checkReport{Certificates: []*certificateCheck{}}
+
+	checker, err := newCertificateChecker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.IsSet(flgConfig) {
+		err = forEachCertificate(ctx, func(certCtx *cli.Context) error {
+			certsStorage, errS := openCertificatesStorage(certCtx)
+			if errS != nil {
+				return errS
+			}
+
+			checker.setupClient(certCtx)
+
+			report.add(checker.check(certsStorage, certCtx.StringSlice(flgDomains)[0]))
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		certsStorage, err := openCertificatesStorage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		checker.setupClient(ctx)
+
+		names := ctx.StringSlice(flgDomains)
+		if len(names) == 0 {
+			names, err = certsStorage.ListCertificates()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, name := range names {
+			report.add(checker.check(certsStorage, name))
+		}
+	}
+
+	if len(report.Certificates) == 0 {
+		report.Status = statusUnknown
+		report.Message = "no certificates found"
+
+		return report, nil
+	}
+
+	counts := make(map[checkStatus]int)
+	for _, cert := range report.Certificates {
+		counts[cert.Status]++
+	}
+
+	report.Message = fmt.Sprintf("%d certificates: %d critical, %d warning, %d unknown, %d ok",
+		len(report.Certificates), counts[statusCritical], counts[statusWarning], counts[statusUnknown], counts[statusOK])
+
+	return report, nil
+}
+
+// writeCheckReport writes the report in the format of the Nagios plugins:
+// the status, the summary and the performance data (days remaining) on the first line, then the details.
+func writeCheckReport(report *checkReport, warning, critical int) {
+	var perfData []string
+
+	for _, cert := range report.Certificates {
+		if cert.DaysRemaining != nil {
+			perfData = append(perfData, fmt.Sprintf("'%s'=%d;%d:;%d:", cert.Name, *cert.DaysRemaining, warning, critical))
+		}
+	}
+
+	line := fmt.Sprintf("%s - %s", report.Status, report.Message)
+	if len(perfData) > 0 {
+		line += " | " + strings.Join(perfData, " ")
+	}
+
+	fmt.Println(line)
+
+	for _, cert := range report.Certificates {
+		fmt.Printf("[%s] %s\n", cert.Name, cert.Status)
+
+		for _, result := range cert.Checks {
+			fmt.Printf("  %s: %s - %s\n", result.Name, result.Status, result.Message)
+		}
+	}
+}
+
+// certificateChecker checks the certificates.
+type certificateChecker struct {
+	ctx context.Context
+
+	warning  int
+	critical int
+
+	ari        bool
+	revocation bool
+
+	roots *x509.CertPool
+
+	// client the ACME client used to query the renewalInfo endpoint (--ari).
+	client    *lego.Client
+	clientErr error
+
+	httpClient *http.Client
+}
+
+func newCertificateChecker(ctx *cli.Context) (*certificateChecker, error) {
+	checker := &certificateChecker{
+		ctx:        ctx.Context,
+		warning:    ctx.Int(flgCheckWarning),
+		critical:   ctx.Int(flgCheckCritical),
+		ari:        ctx.Bool(flgCheckARI),
+		revocation: ctx.Bool(flgCheckRevocation),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+
+	if roots := ctx.StringSlice(flgCheckRoots); len(roots) > 0 {
+		pool, err := lego.CreateCertPool(roots, true)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %w", flgCheckRoots, err)
+		}
+
+		checker.roots = pool
+	}
+
+	return checker, nil
+}
+
+// setupClient creates the ACME client of the account and the server of the certificates, if needed (--ari).
+// The failures are reported by the ARI checks (UNKNOWN).
+func (c *certificateChecker) setupClient(ctx *cli.Context) {
+	if !c.ari {
+		return
+	}
+
+	account, err := loadCheckAccount(ctx)
+	if err != nil {
+		c.client, c.clientErr = nil, err
+		return
+	}
+
+	// No certificate is obtained: the key type of the certificates is not used.
+	c.client, c.clientErr = createClient(ctx, account, "", certificate.EventIssued)
+}
+
+// loadCheckAccount reads the private key of the account, the account files are not created or changed.
+// The renewalInfo endpoint doesn't require the registration of the account.
+func loadCheckAccount(ctx *cli.Context) (*Account, error) {
+	email := ctx.String(flgEmail)
+	if email == "" {
+		return nil, fmt.Errorf("the --%s check requires an account (--%s)", flgCheckARI, flgEmail)
+	}
+
+	accountsStorage, err := openAccountsStorage(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := accountsStorage.ReadPrivateKey()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("the account %s has no private key", email)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read the private key of the account %s: %w", email, err)
+	}
+
+	return &Account{Email: email, key: privateKey}, nil
+}
+
+// check checks a certificate.
+func (c *certificateChecker) check(certsStorage *CertificatesStorage, name string) *certificateCheck {
+	result := &certificateCheck{Name: name}
+
+	stored, err := certsStorage.readStoredCertificate(name)
+	if errors.Is(err, os.ErrNotExist) {
+		result.add("certificate", statusCritical, "not found")
+		return result
+	}
+	if err != nil {
+		result.add("certificate", statusCritical, "%v", err)
+		return result
+	}
+
+	cert := stored.Certificate
+	now := time.Now()
+
+	days := daysRemaining(cert, now)
+	notAfter := cert.NotAfter.UTC()
+
+	result.Domains = certcrypto.ExtractDomains(cert)
+	result.Serial = fmt.Sprintf("%x", cert.SerialNumber)
+	result.NotAfter = &notAfter
+	result.DaysRemaining = &days
+	result.Path = certsStorage.GetFileName(name, certExt)
+
+	c.checkKey(result, certsStorage, name)
+	c.checkChain(result, stored, now)
+	c.checkExpiry(result, cert, now)
+
+	if c.ari {
+		c.checkARI(result, cert, now)
+	}
+
+	if c.revocation {
+		c.checkRevocation(result, stored)
+	}
+
+	return result
+}
+
+func (c *certificateChecker) checkKey(result *certificateCheck, certsStorage *CertificatesStorage, name string) {
+	_, err := certsStorage.ReadPrivateKey(name)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// The certificates obtained with a CSR don't have a private key file.
+		result.add("key", statusUnknown, "no private key file")
+	case err != nil:
+		result.add("key", statusCritical, "%v", err)
+	default:
+		result.add("key", statusOK, "the private key matches the certificate")
+	}
+}
+
+func (c *certificateChecker) checkChain(result *certificateCheck, stored *storedCertificate, now time.Time) {
+	if len(stored.Chain) == 0 {
+		result.add("chain", statusCritical, "no issuer certificate")
+		return
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, issuer := range stored.Chain {
+		intermediates.AddCert(issuer)
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         c.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		CurrentTime:   now,
+	}
+
+	// The expiry of the certificate is reported by the expiry check.
+	if now.After(stored.Certificate.NotAfter) {
+		opts.CurrentTime = stored.Certificate.NotAfter
+	}
+
+	chains, err := stored.Certificate.Verify(opts)
+	if err != nil {
+		result.add("chain", statusCritical, "%v", err)
+		return
+	}
+
+	root := chains[0][len(chains[0])-1]
+
+	result.add("chain", statusOK, "valid, root: %s", root.Subject)
+}
+
+func (c *certificateChecker) checkExpiry(result *certificateCheck, cert *x509.Certificate, now time.Time) {
+	days := daysRemaining(cert, now)
+
+	switch {
+	case now.After(cert.NotAfter):
+		result.add("expiry", statusCritical, "expired on %s", cert.NotAfter.UTC().Format(time.RFC3339))
+	case days <= c.critical:
+		result.add("expiry", statusCritical, "expires in %d days (%s)", days, cert.NotAfter.UTC().Format(time.RFC3339))
+	case days <= c.warning:
+		result.add("expiry", statusWarning, "expires in %d days (%s)", days, cert.NotAfter.UTC().Format(time.RFC3339))
+	default:
+		result.add("expiry", statusOK, "expires in %d days (%s)", days, cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+}
+
+func (c *certificateChecker) checkARI(result *certificateCheck, cert *x509.Certificate, now time.Time) {
+	if c.clientErr != nil {
+		result.add("ari", statusUnknown, "%v", c.clientErr)
+		return
+	}
+
+	renewalInfo, err := c.client.Certificate.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: cert})
+	if errors.Is(err, api.ErrNoARI) {
+		result.add("ari", statusUnknown, "the server does not support the renewalInfo endpoint")
+		return
+	}
+	if err != nil {
+		result.add("ari", statusUnknown, "%v", err)
+		return
+	}
+
+	window := renewalInfo.SuggestedWindow
+
+	msg := fmt.Sprintf("renewal window: %s - %s", window.Start.UTC().Format(time.RFC3339), window.End.UTC().Format(time.RFC3339))
+	if renewalInfo.ExplanationURL != "" {
+		msg += ", explanation: " + renewalInfo.ExplanationURL
+	}
+
+	switch {
+	case now.After(window.End):
+		result.add("ari", statusCritical, "the renewal window has ended, %s", msg)
+	case now.After(window.Start):
+		result.add("ari", statusWarning, "the renewal window has started, %s", msg)
+	default:
+		result.add("ari", statusOK, "%s", msg)
+	}
+}
+
+func (c *certificateChecker) checkRevocation(result *certificateCheck, stored *storedCertificate) {
+	if len(stored.Certificate.OCSPServer) == 0 {
+		c.checkCRL(result, stored)
+		return
+	}
+
+	if len(stored.Chain) == 0 {
+		result.add("revocation", statusUnknown, "OCSP: no issuer certificate")
+		return
+	}
+
+	resp, err := c.fetchOCSP(stored.Certificate, stored.Chain[0])
+	if err != nil {
+		result.add("revocation", statusUnknown, "OCSP: %v", err)
+		return
+	}
+
+	switch resp.Status {
+	case ocsp.Good:
+		result.add("revocation", statusOK, "not revoked (OCSP)")
+	case ocsp.Revoked:
+		result.add("revocation", statusCritical, "revoked on %s, reason %d (OCSP)", resp.RevokedAt.UTC().Format(time.RFC3339), resp.RevocationReason)
+	default:
+		result.add("revocation", statusUnknown, "unknown status (OCSP)")
+	}
+}
+
+func (c *certificateChecker) checkCRL(result *certificateCheck, stored *storedCertificate) {
+	cert := stored.Certificate
+
+	if len(cert.CRLDistributionPoints) == 0 {
+		result.add("revocation", statusUnknown, "no OCSP server or CRL distribution point")
+		return
+	}
+
+	if len(stored.Chain) == 0 {
+		result.add("revocation", statusUnknown, "CRL: no issuer certificate")
+		return
+	}
+
+	crl, err := c.fetchCRL(cert.CRLDistributionPoints[0])
+	if err != nil {
+		result.add("revocation", statusUnknown, "CRL: %v", err)
+		return
+	}
+
+	err = crl.CheckSignatureFrom(stored.Chain[0])
+	if err != nil {
+		result.add("revocation", statusUnknown, "CRL: %v", err)
+		return
+	}
+
+	index := slices.IndexFunc(crl.RevokedCertificateEntries, func(entry x509.RevocationListEntry) bool {
+		return entry.SerialNumber.Cmp(cert.SerialNumber) == 0
+	})
+
+	if index < 0 {
+		result.add("revocation", statusOK, "not revoked (CRL)")
+		return
+	}
+
+	entry := crl.RevokedCertificateEntries[index]
+
+	result.add("revocation", statusCritical, "revoked on %s, reason %d (CRL)", entry.RevocationTime.UTC().Format(time.RFC3339), entry.ReasonCode)
+}
+
+func (c *certificateChecker) fetchOCSP(cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	body, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := cert.OCSPServer[0]
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/ocsp-request")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status code: %d", endpoint, resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return ocsp.ParseResponseForCert(raw, cert, issuer)
+}
+
+func (c *certificateChecker) fetchCRL(endpoint string) (*x509.RevocationList, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, endpoint, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: unexpected status code: %d", endpoint, resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxCRLSize))
+	if err != nil {
+		return nil, err
+	}
+
+	// The CRLs are usually DER encoded, but some servers use PEM.
+	if block, _ := pem.Decode(raw); block != nil {
+		raw = block.Bytes
+	}
+
+	return x509.ParseRevocationList(raw)
+}
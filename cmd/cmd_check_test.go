@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"flag"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestCertificateChecker_check(t *testing.T) {
+	ca, caKey := generateTestCA(t)
+
+	testCases := []struct {
+		desc           string
+		notAfter       time.Duration
+		otherKey       bool
+		noKey          bool
+		untrusted      bool
+		expectedStatus checkStatus
+		expectedChecks map[string]checkStatus
+	}{
+		{
+			desc:           "valid",
+			notAfter:       90 * 24 * time.Hour,
+			expectedStatus: statusOK,
+			expectedChecks: map[string]checkStatus{"key": statusOK, "chain": statusOK, "expiry": statusOK},
+		},
+		{
+			desc:           "warning",
+			notAfter:       20 * 24 * time.Hour,
+			expectedStatus: statusWarning,
+			expectedChecks: map[string]checkStatus{"key": statusOK, "chain": statusOK, "expiry": statusWarning},
+		},
+		{
+			desc:           "critical",
+			notAfter:       5 * 24 * time.Hour,
+			expectedStatus: statusCritical,
+			expectedChecks: map[string]checkStatus{"key": statusOK, "chain": statusOK, "expiry": statusCritical},
+		},
+		{
+			desc:           "expired",
+			notAfter:       -time.Hour,
+			expectedStatus: statusCritical,
+			expectedChecks: map[string]checkStatus{"key": statusOK, "chain": statusOK, "expiry": statusCritical},
+		},
+		{
+			desc:           "key mismatch",
+			notAfter:       90 * 24 * time.Hour,
+			otherKey:       true,
+			expectedStatus: statusCritical,
+			expectedChecks: map[string]checkStatus{"key": statusCritical, "chain": statusOK, "expiry": statusOK},
+		},
+		{
+			desc:           "no key",
+			notAfter:       90 * 24 * time.Hour,
+			noKey:          true,
+			expectedStatus: statusUnknown,
+			expectedChecks: map[string]checkStatus{"key": statusUnknown, "chain": statusOK, "expiry": statusOK},
+		},
+		{
+			desc:           "untrusted chain",
+			notAfter:       90 * 24 * time.Hour,
+			untrusted:      true,
+			expectedStatus: statusCritical,
+			expectedChecks: map[string]checkStatus{"key": statusOK, "chain": statusCritical, "expiry": statusOK},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			storage := &CertificatesStorage{rootPath: t.TempDir()}
+
+			certPEM, keyPEM := generateTestLeaf(t, ca, caKey, "example.com", time.Now().Add(test.notAfter))
+
+			if test.otherKey {
+				_, keyPEM = generateTestCertificate(t, "example.com")
+			}
+
+			files := map[string][]byte{
+				storage.GetFileName("example.com", certExt):   certPEM,
+				storage.GetFileName("example.com", issuerExt): certcrypto.PEMEncode(certcrypto.DERCertificateBytes(ca.Raw)),
+			}
+
+			if !test.noKey {
+				files[storage.GetFileName("example.com", keyExt)] = keyPEM
+			}
+
+			require.NoError(t, storage.files().WriteFiles(files))
+
+			checker := &certificateChecker{warning: 30, critical: 7, roots: x509.NewCertPool()}
+
+			if !test.untrusted {
+				checker.roots.AddCert(ca)
+			}
+
+			result := checker.check(storage, "example.com")
+
+			assert.Equal(t, test.expectedStatus, result.Status)
+
+			checks := make(map[string]checkStatus)
+			for _, r := range result.Checks {
+				checks[r.Name] = r.Status
+			}
+
+			assert.Equal(t, test.expectedChecks, checks)
+		})
+	}
+}
+
+func TestCertificateChecker_check_notFound(t *testing.T) {
+	checker := &certificateChecker{warning: 30, critical: 7}
+
+	result := checker.check(&CertificatesStorage{rootPath: t.TempDir()}, "example.com")
+
+	assert.Equal(t, statusCritical, result.Status)
+	require.Len(t, result.Checks, 1)
+	assert.Equal(t, "not found", result.Checks[0].Message)
+}
+
+func Test_check_setupErrors(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		storage   string
+		pfxFormat string
+		certbot   bool
+		expected  string
+	}{
+		{
+			desc:      "invalid storage",
+			storage:   "ftp://example.com/lego",
+			pfxFormat: "RC2",
+			expected:  `could not create the storage "ftp://example.com/lego": unsupported scheme "ftp"`,
+		},
+		{
+			desc:      "invalid PFX format",
+			pfxFormat: "AES",
+			expected:  "invalid PFX format: AES",
+		},
+		{
+			desc:      "certbot layout with a remote storage",
+			storage:   "consul://127.0.0.1:8500/lego",
+			pfxFormat: "RC2",
+			certbot:   true,
+			expected:  "the --certbot-layout option requires a storage supporting symbolic links (local storage)",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			set := flag.NewFlagSet("check", flag.ContinueOnError)
+			set.String(flgFormat, formatText, "")
+			set.String(flgPath, t.TempDir(), "")
+			set.String(flgStorage, test.storage, "")
+			set.String(flgPFXFormat, test.pfxFormat, "")
+			set.Bool(flgCertbotLayout, test.certbot, "")
+
+			err := check(cli.NewContext(cli.NewApp(), set, nil))
+			require.EqualError(t, err, test.expected)
+
+			var exitErr cli.ExitCoder
+			require.ErrorAs(t, err, &exitErr)
+			assert.Equal(t, int(statusUnknown), exitErr.ExitCode())
+		})
+	}
+}
+
+func TestCertificateChecker_checkCRL(t *testing.T) {
+	ca, caKey := generateTestCA(t)
+
+	certPEM, _ := generateTestLeaf(t, ca, caKey, "example.com", time.Now().Add(24*time.Hour))
+
+	cert, err := certcrypto.ParsePEMCertificate(certPEM)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		revoked  []x509.RevocationListEntry
+		expected checkStatus
+	}{
+		{
+			desc:     "not revoked",
+			expected: statusOK,
+		},
+		{
+			desc: "revoked",
+			revoked: []x509.RevocationListEntry{
+				{SerialNumber: cert.SerialNumber, RevocationTime: time.Now().Add(-time.Hour), ReasonCode: 1},
+			},
+			expected: statusCritical,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+				Number:                    big.NewInt(1),
+				ThisUpdate:                time.Now(),
+				NextUpdate:                time.Now().Add(time.Hour),
+				RevokedCertificateEntries: test.revoked,
+			}, ca, caKey)
+			require.NoError(t, err)
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				_, _ = rw.Write(crl)
+			}))
+			t.Cleanup(server.Close)
+
+			leaf := *cert
+			leaf.CRLDistributionPoints = []string{server.URL}
+
+			checker := &certificateChecker{ctx: t.Context(), httpClient: server.Client()}
+
+			result := &certificateCheck{Name: "example.com"}
+
+			checker.checkCRL(result, &storedCertificate{Certificate: &leaf, Chain: []*x509.Certificate{ca}})
+
+			assert.Equal(t, test.expected, result.Status)
+		})
+	}
+}
+
+func TestCertificateChecker_checkRevocation_ocsp(t *testing.T) {
+	ca, caKey := generateTestCA(t)
+
+	certPEM, _ := generateTestLeaf(t, ca, caKey, "example.com", time.Now().Add(24*time.Hour))
+
+	cert, err := certcrypto.ParsePEMCertificate(certPEM)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		status   int
+		expected checkStatus
+	}{
+		{
+			desc:     "not revoked",
+			status:   ocsp.Good,
+			expected: statusOK,
+		},
+		{
+			desc:     "revoked",
+			status:   ocsp.Revoked,
+			expected: statusCritical,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				body, errR := io.ReadAll(req.Body)
+				require.NoError(t, errR)
+
+				ocspReq, errR := ocsp.ParseRequest(body)
+				require.NoError(t, errR)
+
+				resp, errR := ocsp.CreateResponse(ca, ca, ocsp.Response{
+					Status:       test.status,
+					SerialNumber: ocspReq.SerialNumber,
+					ThisUpdate:   time.Now().Add(-time.Hour),
+					NextUpdate:   time.Now().Add(time.Hour),
+					RevokedAt:    time.Now().Add(-time.Hour),
+				}, caKey)
+				require.NoError(t, errR)
+
+				_, _ = rw.Write(resp)
+			}))
+			t.Cleanup(server.Close)
+
+			leaf := *cert
+			leaf.OCSPServer = []string{server.URL}
+
+			// No ACME client: OCSP is queried directly.
+			checker := &certificateChecker{ctx: t.Context(), httpClient: server.Client()}
+
+			result := &certificateCheck{Name: "example.com"}
+
+			checker.checkRevocation(result, &storedCertificate{Certificate: &leaf, Chain: []*x509.Certificate{ca}})
+
+			assert.Equal(t, test.expected, result.Status)
+		})
+	}
+}
+
+func Test_loadCheckAccount(t *testing.T) {
+	newContext := func(t *testing.T, email string) *cli.Context {
+		t.Helper()
+
+		set := flag.NewFlagSet("check", flag.ContinueOnError)
+		set.String(flgEmail, email, "")
+		set.String(flgServer, "https://acme.example.com/directory", "")
+		set.String(flgPath, t.TempDir(), "")
+		set.String(flgStorage, "", "")
+
+		return cli.NewContext(cli.NewApp(), set, nil)
+	}
+
+	t.Run("no email", func(t *testing.T) {
+		_, err := loadCheckAccount(newContext(t, ""))
+		require.EqualError(t, err, "the --ari check requires an account (--email)")
+	})
+
+	t.Run("no private key", func(t *testing.T) {
+		ctx := newContext(t, "test@example.com")
+
+		_, err := loadCheckAccount(ctx)
+		require.EqualError(t, err, "the account test@example.com has no private key")
+
+		// The account files are not created.
+		entries, err := os.ReadDir(ctx.String(flgPath))
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("private key", func(t *testing.T) {
+		ctx := newContext(t, "test@example.com")
+
+		accountsStorage, err := openAccountsStorage(ctx, "test@example.com")
+		require.NoError(t, err)
+
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		require.NoError(t, accountsStorage.writePrivateKey(accountsStorage.getPrivateKeyPath(), privateKey))
+
+		account, err := loadCheckAccount(ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, "test@example.com", account.Email)
+		assert.True(t, privateKey.Equal(account.GetPrivateKey()))
+	})
+}
+
+func Test_worst(t *testing.T) {
+	assert.Equal(t, statusWarning, worst(statusOK, statusWarning))
+	assert.Equal(t, statusWarning, worst(statusUnknown, statusWarning))
+	assert.Equal(t, statusCritical, worst(statusCritical, statusUnknown))
+	assert.Equal(t, statusUnknown, worst(statusOK, statusUnknown))
+}
+
+func generateTestCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-48 * time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Public(), privateKey)
+	require.NoError(t, err)
+
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return ca, privateKey
+}
+
+func generateTestLeaf(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, domain string, notAfter time.Time) ([]byte, []byte) {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, privateKey.Public(), caKey)
+	require.NoError(t, err)
+
+	return certcrypto.PEMEncode(certcrypto.DERCertificateBytes(der)), certcrypto.PEMEncode(privateKey)
+}
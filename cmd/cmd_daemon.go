@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgCheckInterval = "check-interval"
+)
+
+func createDaemon() *cli.Command {
+	return &cli.Command{
+		Name: "daemon",
+		Usage: "Renew the certificates when needed, as a long-running process." +
+			" All the certificates of the storage are renewed, or only the certificates named with --" + flgDomains + "." +
+			" The parts of a split certificate (--" + flgMaxIdentifiers + ") are renewed together, when one of them needs to be renewed.",
+		Action: daemon,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  flgCheckInterval,
+				Usage: "The interval between two checks of a certificate. The interval recommended by the renewalInfo endpoint is used, if any.",
+				Value: certificate.DefaultRenewalCheckInterval,
+			},
+			&cli.BoolFlag{
+				Name:  flgARIDisable,
+				Usage: "Do not use the renewalInfo endpoint (RFC9773) to check if a certificate should be renewed.",
+			},
+			&cli.DurationFlag{
+				Name:  flgARIWaitToRenewDuration,
+				Usage: "The maximum duration you're willing to wait for a renewal time returned by the renewalInfo endpoint. By default, the check interval.",
+			},
+			&cli.BoolFlag{
+				Name:  flgReuseKey,
+				Usage: "Used to indicate you want to reuse your current private key for the new certificates.",
+			},
+			&cli.BoolFlag{
+				Name:  flgNoBundle,
+				Usage: "Do not create a certificate bundle by adding the issuers certificate to the new certificate.",
+			},
+			&cli.BoolFlag{
+				Name: flgMustStaple,
+				Usage: "Include the OCSP must staple TLS extension in the CSR and generated certificate." +
+					" Only works if the CSR is generated by lego.",
+			},
+			&cli.StringFlag{
+				Name: flgPreferredChain,
+				Usage: "If the CA offers multiple certificate chains, prefer the chain with an issuer matching this Subject Common Name." +
+					" If no match, the default offered chain will be used.",
+			},
+			&cli.StringFlag{
+				Name:  flgProfile,
+				Usage: "If the CA offers multiple certificate profiles (draft-aaron-acme-profiles), choose this one.",
+			},
+			&cli.BoolFlag{
+				Name:  flgAlwaysDeactivateAuthorizations,
+				Usage: "Force the authorizations to be relinquished even if the certificate request was successful.",
+			},
+			&cli.StringFlag{
+				Name:  flgRenewHook,
+				Usage: "Define a hook. The hook is executed each time a certificate is renewed.",
+			},
+			&cli.StringFlag{
+				Name:  flgFailureHook,
+				Usage: "Define a hook. The hook is executed each time a certificate cannot be checked or renewed.",
+			},
+			&cli.DurationFlag{
+				Name:  flgRenewHookTimeout,
+				Usage: "Define the timeout for the hook execution.",
+				Value: 2 * time.Minute,
+			},
+		},
+	}
+}
+
+func daemon(ctx *cli.Context) error {
+	accountsStorage := NewAccountsStorage(ctx)
+
+	account, keyType, unlock := setupAccount(ctx, accountsStorage)
+
+	defer unlock()
+
+	if account.Registration == nil {
+		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+	}
+
+	client, err := setupClient(ctx, account, keyType, certificate.EventIssued)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	certsStorage := NewCertificatesStorage(ctx)
+
+	hooks := newHookRunner(ctx, account.Email, ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout))
+
+	// The maximum number of identifiers of the split certificates, by name.
+	maxIdentifiers := map[string]int{}
+
+	manager := certificate.NewRenewalManager(client.Certificate, &certificate.RenewalManagerOptions{
+		CheckInterval:  ctx.Duration(flgCheckInterval),
+		WillingToSleep: ctx.Duration(flgARIWaitToRenewDuration),
+		DisableARI:     ctx.Bool(flgARIDisable),
+		ExpiryWarning:  ctx.Duration(flgNotifyExpiryWarning),
+		ReuseKey:       ctx.Bool(flgReuseKey),
+		RenewOptions: &certificate.RenewOptions{
+			Bundle:                         !ctx.Bool(flgNoBundle),
+			PreferredChain:                 ctx.String(flgPreferredChain),
+			Profile:                        ctx.String(flgProfile),
+			AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations),
+			MustStaple:                     ctx.Bool(flgMustStaple),
+		},
+		// The other lego processes wait for the renewal of the certificate.
+		Lock: certsStorage.lock,
+		OnRenewed: func(_, renewed *certificate.Resource) {
+			certsStorage.SaveResource(renewed)
+
+			// The failures are reported by deploy.
+			_ = deploy(ctx, certsStorage, renewed)
+
+			err := hooks.post(renewed, certsStorage)
+			if err != nil {
+				log.Warnf("[%s] The hook has failed: %v", renewed.Domain, err)
+			}
+		},
+		OnGroupRenewed: func(name string, _, renewed []*certificate.Resource, err error) {
+			if err != nil {
+				// The parts already renewed are kept, the renewal is retried.
+				certsStorage.SavePartialGroup(name, maxIdentifiers[name], renewed)
+				return
+			}
+
+			certsStorage.SaveGroup(name, maxIdentifiers[name], renewed)
+
+			// The failures are reported by deployGroup.
+			_ = deployGroup(ctx, certsStorage, renewed)
+
+			err = hooks.postGroup(renewed, certsStorage)
+			if err != nil {
+				log.Warnf("[%s] The hook has failed: %v", name, err)
+			}
+		},
+		OnError: func(name string, err error) {
+			hooks.failure(name, nil, nil, err)
+		},
+	})
+
+	names, err := getDaemonCertificates(ctx, certsStorage)
+	if err != nil {
+		log.Fatalf("Could not list the certificates: %v", err)
+	}
+
+	if len(names) == 0 {
+		log.Fatal("No certificates to renew.")
+	}
+
+	count, err := addDaemonCertificates(manager, certsStorage, names, maxIdentifiers)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Infof("Managing the renewal of %d certificates.", count)
+
+	// The key of the account cannot be changed while the daemon runs: keychange reports the PID of the daemon.
+	defer accountsStorage.WriteDaemonPID()()
+
+	signalCtx, stop := signal.NotifyContext(ctx.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = manager.Run(signalCtx)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	log.Infof("Stopping the renewal of the certificates.")
+
+	return nil
+}
+
+// getDaemonCertificates returns the names of the certificates managed by the daemon:
+// the certificates named with --domains, or all the certificates of the storage.
+func getDaemonCertificates(ctx *cli.Context, certsStorage *CertificatesStorage) ([]string, error) {
+	if ctx.IsSet(flgDomains) {
+		return ctx.StringSlice(flgDomains), nil
+	}
+
+	return certsStorage.ListCertificates()
+}
+
+// addDaemonCertificates adds the certificates to the manager, and returns the number of certificates.
+// The parts of a split certificate are added together, as a single certificate.
+func addDaemonCertificates(manager *certificate.RenewalManager, certsStorage *CertificatesStorage, names []string, maxIdentifiers map[string]int) (int, error) {
+	parts := map[string]bool{}
+
+	var groups []*CertificateGroup
+
+	for _, name := range names {
+		group, err := certsStorage.ReadGroup(name)
+		if err != nil {
+			return 0, fmt.Errorf("error while loading the group for domain %s: %w", name, err)
+		}
+
+		if group == nil {
+			continue
+		}
+
+		groups = append(groups, group)
+
+		for _, part := range group.Parts {
+			parts[sanitizedDomain(part)] = true
+		}
+	}
+
+	for _, group := range groups {
+		var resources []*certificate.Resource
+
+		for _, part := range group.Parts {
+			certRes, err := readCertificateResource(certsStorage, part)
+			if err != nil {
+				return 0, fmt.Errorf("error while loading the certificate %s: %w", part, err)
+			}
+
+			resources = append(resources, certRes)
+		}
+
+		err := manager.AddGroup(group.Domain, group.MaxIdentifiers, resources)
+		if err != nil {
+			return 0, err
+		}
+
+		maxIdentifiers[group.Domain] = group.MaxIdentifiers
+	}
+
+	count := len(groups)
+
+	for _, name := range names {
+		if parts[sanitizedDomain(name)] {
+			continue
+		}
+
+		certRes, err := readCertificateResource(certsStorage, name)
+		if err != nil {
+			return 0, fmt.Errorf("error while loading the certificate %s: %w", name, err)
+		}
+
+		err = manager.Add(certRes)
+		if err != nil {
+			return 0, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// readCertificateResource reads the resource, the certificate, the issuer, and the private key (if any) of a certificate.
+func readCertificateResource(certsStorage *CertificatesStorage, name string) (*certificate.Resource, error) {
+	certRes := certsStorage.ReadResource(name)
+
+	var err error
+
+	certRes.Certificate, err = certsStorage.ReadFile(name, certExt)
+	if err != nil {
+		return nil, err
+	}
+
+	certRes.IssuerCertificate, err = certsStorage.ReadFile(name, issuerExt)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// A certificate obtained with a CSR has no private key.
+	certRes.PrivateKey, err = certsStorage.ReadPrivateKey(name)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return &certRes, nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readCertificateResource(t *testing.T) {
+	storage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	certPEM, keyPEM := generateTestCertificate(t, "*.example.com")
+
+	files := map[string]string{
+		"_.example.com.json": `{"domain": "*.example.com", "certUrl": "https://example.com/cert/1"}`,
+		"_.example.com.crt":  string(certPEM),
+		"_.example.com.key":  string(keyPEM),
+	}
+
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(storage.rootPath, name), []byte(content), 0o600)
+		require.NoError(t, err)
+	}
+
+	certRes, err := readCertificateResource(storage, "_.example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, "*.example.com", certRes.Domain)
+	assert.Equal(t, "https://example.com/cert/1", certRes.CertURL)
+	assert.Equal(t, certPEM, certRes.Certificate)
+	assert.Equal(t, keyPEM, certRes.PrivateKey)
+	assert.Nil(t, certRes.IssuerCertificate)
+}
+
+func Test_addDaemonCertificates(t *testing.T) {
+	storage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	files := map[string]string{
+		"example.com.group.json": `{"domain": "example.com", "maxIdentifiers": 1, "parts": ["example.com", "example.com-part2"]}`,
+	}
+
+	for _, domain := range []string{"example.com", "example.com-part2", "example.org"} {
+		certPEM, keyPEM := generateTestCertificate(t, domain)
+
+		files[domain+".json"] = `{"domain": "` + domain + `"}`
+		files[domain+".crt"] = string(certPEM)
+		files[domain+".key"] = string(keyPEM)
+	}
+
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(storage.rootPath, name), []byte(content), 0o600)
+		require.NoError(t, err)
+	}
+
+	manager := certificate.NewRenewalManager(&certificate.Certifier{}, nil)
+
+	maxIdentifiers := map[string]int{}
+
+	count, err := addDaemonCertificates(manager, storage, []string{"example.com", "example.com-part2", "example.org"}, maxIdentifiers)
+	require.NoError(t, err)
+
+	// The parts of the split certificate are a single certificate.
+	assert.Equal(t, 2, count)
+	assert.Equal(t, map[string]int{"example.com": 1}, maxIdentifiers)
+
+	for _, name := range []string{"example.com", "example.org"} {
+		_, ok := manager.Next(name)
+		assert.True(t, ok, name)
+	}
+
+	_, ok := manager.Next("example.com-part2")
+	assert.False(t, ok)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/challenge/dnspersist01"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/go-acme/lego/v4/providers/dns"
+	"github.com/urfave/cli/v2"
+)
+
+// Flag names.
+const (
+	flgIssuer       = "issuer"
+	flgPersistUntil = "persist-until"
+)
+
+func createDNSPersist() *cli.Command {
+	return &cli.Command{
+		Name: "dnspersist",
+		Usage: "Print the persistent validation records (DNS-PERSIST-01) which authorize the account for the domains." +
+			" The records are created if a DNS provider is defined (--" + flgDNS + ").",
+		Before: func(ctx *cli.Context) error {
+			if len(ctx.StringSlice(flgDomains)) == 0 {
+				log.Fatal("Please specify --domains/-d")
+			}
+			return nil
+		},
+		Action: dnsPersist,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     flgIssuer,
+				Usage:    "The issuer domain name of the CA (i.e. letsencrypt.org).",
+				Required: true,
+			},
+			&cli.TimestampFlag{
+				Name:   flgPersistUntil,
+				Usage:  "Set the time after which the records must not be used (RFC3339 format).",
+				Layout: time.RFC3339,
+			},
+		},
+	}
+}
+
+func dnsPersist(ctx *cli.Context) error {
+	account, _, unlock := setupAccount(ctx, NewAccountsStorage(ctx))
+
+	defer unlock()
+
+	if account.Registration == nil {
+		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+	}
+
+	var provider dns01.RecordProvider
+
+	if ctx.IsSet(flgDNS) {
+		p, err := dns.NewDNSChallengeProviderByName(ctx.String(flgDNS))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		provider = dns01.AsRecordProvider(p)
+	}
+
+	for _, domain := range ctx.StringSlice(flgDomains) {
+		record := dnspersist01.Record{
+			IssuerDomainName: strings.ToLower(ctx.String(flgIssuer)),
+			AccountURI:       account.Registration.URI,
+			Wildcard:         strings.HasPrefix(domain, "*."),
+			PersistUntil:     getTime(ctx, flgPersistUntil),
+		}
+
+		domain = strings.TrimPrefix(domain, "*.")
+		fqdn := dnspersist01.GetRecordFQDN(domain)
+
+		fmt.Printf("%s TXT %q\n", fqdn, record)
+
+		if provider == nil {
+			continue
+		}
+
+		err := dnspersist01.PresentRecord(ctx.Context, provider, domain, record)
+		if err != nil {
+			log.Fatalf("[%s] Could not create the persistent validation record: %v", domain, err)
+		}
+
+		log.Printf("[%s] The persistent validation record has been created.", domain)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+func createKeyChange() *cli.Command {
+	return &cli.Command{
+		Name:   "keychange",
+		Usage:  "Roll over the key of an account",
+		Action: keyChange,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  flgPrivateKey,
+				Usage: "Path to the new private key (in PEM encoding) for the account. By default, the private key is generated.",
+			},
+		},
+	}
+}
+
+func keyChange(ctx *cli.Context) error {
+	accountsStorage := NewAccountsStorage(ctx)
+
+	// The other lego processes wait for the new key, the key is not changed while the account is used (see setupAccount).
+	// The lock is not awaited: the daemon uses the account until it is stopped.
+	unlock, err := accountsStorage.tryLock()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer unlock()
+
+	keyType := getKeyType(ctx)
+
+	account, err := loadAccount(accountsStorage, keyType)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if account.Registration == nil {
+		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+	}
+
+	committed, err := recoverStagedKey(ctx, accountsStorage, account)
+	if err != nil {
+		log.Fatalf("Could not recover the interrupted key change of account %s: %v", account.Email, err)
+	}
+
+	if committed {
+		return nil
+	}
+
+	client := newClient(ctx, account, keyType)
+
+	var newKey crypto.PrivateKey
+
+	if ctx.IsSet(flgPrivateKey) {
+		newKey, err = loadPrivateKey(ctx.String(flgPrivateKey))
+		if err != nil {
+			log.Fatalf("Could not load the new private key for account %s: %v", account.Email, err)
+		}
+	} else {
+		log.Printf("Generating a new %s key for account %s.", keyType, account.Email)
+
+		newKey, err = certcrypto.GeneratePrivateKey(keyType)
+		if err != nil {
+			log.Fatalf("Could not generate the new private key for account %s: %v", account.Email, err)
+		}
+	}
+
+	err = accountsStorage.StagePrivateKey(newKey)
+	if err != nil {
+		log.Fatalf("Could not save the new private key for account %s: %v", account.Email, err)
+	}
+
+	err = client.Registration.ChangeKey(newKey)
+	if err != nil {
+		log.Fatalf("Could not change the key of account %s: %v\n"+
+			"The new key is kept in %s: if the server has applied the change, run keychange again to use it.",
+			account.Email, err, accountsStorage.GetStagedPrivateKeyPath())
+	}
+
+	err = accountsStorage.CommitPrivateKey()
+	if err != nil {
+		log.Fatalf("The key of account %s has been changed, but the new key %s cannot replace the old one: %v",
+			account.Email, accountsStorage.GetStagedPrivateKeyPath(), err)
+	}
+
+	log.Printf("The key of account %s has been changed.", account.Email)
+
+	return nil
+}
+
+// recoverStagedKey handles the key staged by an interrupted key change (e.g. the response of the server has been lost).
+// If the server already uses the staged key, it replaces the current key, and true is returned.
+// If the server doesn't know the staged key, the staged key is removed.
+func recoverStagedKey(ctx *cli.Context, accountsStorage *AccountsStorage, account *Account) (bool, error) {
+	stagedKey, err := accountsStorage.ReadStagedPrivateKey()
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("staged key %s: %w", accountsStorage.GetStagedPrivateKeyPath(), err)
+	}
+
+	reg, err := tryRecoverRegistration(ctx, stagedKey)
+
+	var notExist *acme.AccountDoesNotExistError
+
+	switch {
+	case errors.As(err, &notExist):
+		log.Printf("Removing the staged key %s of an interrupted key change: the server doesn't use it.", accountsStorage.GetStagedPrivateKeyPath())
+
+		return false, accountsStorage.RemoveStagedPrivateKey()
+
+	case err != nil:
+		// The staged key is kept: it may be the key used by the server.
+		return false, fmt.Errorf("staged key %s: %w", accountsStorage.GetStagedPrivateKeyPath(), err)
+
+	case reg.URI != account.Registration.URI:
+		return false, fmt.Errorf("the staged key %s is used by another account: %s", accountsStorage.GetStagedPrivateKeyPath(), reg.URI)
+	}
+
+	err = accountsStorage.CommitPrivateKey()
+	if err != nil {
+		return false, err
+	}
+
+	log.Printf("The key of account %s had already been changed on the server: the staged key is now the key of the account.", account.Email)
+
+	return true, nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/platform/tester/acmeserver"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_recoverStagedKey(t *testing.T) {
+	_, dirURL := acmeserver.Start(t)
+
+	testCases := []struct {
+		desc      string
+		applied   bool
+		committed bool
+	}{
+		{
+			desc:      "key changed on the server",
+			applied:   true,
+			committed: true,
+		},
+		{
+			desc:    "key not changed on the server",
+			applied: false,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			set := flag.NewFlagSet("keychange", flag.ContinueOnError)
+			set.String(flgServer, dirURL, "")
+			set.String(flgPath, t.TempDir(), "")
+			set.String(flgStorage, "", "")
+			set.String(flgUserAgent, "", "")
+
+			ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+			accountsStorage, err := openAccountsStorage(ctx, "test@example.com")
+			require.NoError(t, err)
+
+			account := &Account{Email: "test@example.com", key: generateTestAccountKey(t)}
+
+			client := newTestACMEClient(t, dirURL, account)
+
+			account.Registration, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+			require.NoError(t, err)
+
+			require.NoError(t, accountsStorage.writePrivateKey(accountsStorage.getPrivateKeyPath(), account.key))
+
+			newKey := generateTestAccountKey(t)
+
+			require.NoError(t, accountsStorage.StagePrivateKey(newKey))
+
+			if test.applied {
+				require.NoError(t, client.Registration.ChangeKey(newKey))
+			}
+
+			committed, err := recoverStagedKey(ctx, accountsStorage, account)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.committed, committed)
+
+			current, err := accountsStorage.ReadPrivateKey()
+			require.NoError(t, err)
+
+			if test.committed {
+				assert.True(t, newKey.(*ecdsa.PrivateKey).Equal(current))
+			} else {
+				assert.True(t, account.key.(*ecdsa.PrivateKey).Equal(current))
+			}
+
+			_, err = accountsStorage.ReadStagedPrivateKey()
+			require.ErrorIs(t, err, os.ErrNotExist)
+		})
+	}
+}
+
+func TestAccountsStorage_tryLock(t *testing.T) {
+	set := flag.NewFlagSet("keychange", flag.ContinueOnError)
+	set.String(flgServer, "https://ca.example.com/dir", "")
+	set.String(flgPath, t.TempDir(), "")
+	set.String(flgStorage, "", "")
+
+	ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+	accountsStorage, err := openAccountsStorage(ctx, "test@example.com")
+	require.NoError(t, err)
+
+	// Another lego process, with its own lock file descriptor.
+	other, err := openAccountsStorage(ctx, "test@example.com")
+	require.NoError(t, err)
+
+	runlock, err := other.rLock()
+	require.NoError(t, err)
+
+	_, err = accountsStorage.tryLock()
+	require.EqualError(t, err, "the account test@example.com is used by another lego process: retry when it has ended")
+
+	removePID := other.WriteDaemonPID()
+
+	_, err = accountsStorage.tryLock()
+	require.EqualError(t, err, fmt.Sprintf("the account test@example.com is used by the lego daemon (PID %d): stop it, then retry", os.Getpid()))
+
+	removePID()
+	runlock()
+
+	unlock, err := accountsStorage.tryLock()
+	require.NoError(t, err)
+
+	unlock()
+}
+
+func generateTestAccountKey(t *testing.T) crypto.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return key
+}
+
+func newTestACMEClient(t *testing.T, dirURL string, account *Account) *lego.Client {
+	t.Helper()
+
+	config := lego.NewConfig(account)
+	config.CADirURL = dirURL
+
+	client, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	return client
+}
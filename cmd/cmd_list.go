@@ -1,20 +1,34 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
-	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/urfave/cli/v2"
 )
 
 const (
-	flgAccounts = "accounts"
-	flgNames    = "names"
+	flgAccounts       = "accounts"
+	flgNames          = "names"
+	flgOrders         = "orders"
+	flgFormat         = "format"
+	flgExpiringWithin = "expiring-within"
+)
+
+// Output formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatCSV  = "csv"
 )
 
 func createList() *cli.Command {
@@ -33,6 +47,20 @@ func createList() *cli.Command {
 				Aliases: []string{"n"},
 				Usage:   "Display certificate common names only.",
 			},
+			&cli.BoolFlag{
+				Name:    flgOrders,
+				Aliases: []string{"o"},
+				Usage:   "Display the pending and invalid orders of the accounts of the server (requests the ACME server).",
+			},
+			&cli.StringFlag{
+				Name:  flgFormat,
+				Usage: "The output format: text, json, or csv. With --" + flgAccounts + ", only the accounts are displayed in the json and csv formats.",
+				Value: formatText,
+			},
+			&cli.DurationFlag{
+				Name:  flgExpiringWithin,
+				Usage: "Display only the certificates expiring within this duration (e.g. 720h).",
+			},
 			// fake email, needed by NewAccountsStorage
 			&cli.StringFlag{
 				Name:   flgEmail,
@@ -44,7 +72,24 @@ func createList() *cli.Command {
 }
 
 func list(ctx *cli.Context) error {
+	format := ctx.String(flgFormat)
+	if !slices.Contains([]string{formatText, formatJSON, formatCSV}, format) {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	if ctx.IsSet(flgConfig) {
+		return listConfigCertificates(ctx)
+	}
+
+	if ctx.Bool(flgOrders) {
+		return listOrders(ctx)
+	}
+
 	if ctx.Bool(flgAccounts) && !ctx.Bool(flgNames) {
+		if format != formatText {
+			return listAccountInfos(ctx, format)
+		}
+
 		if err := listAccount(ctx); err != nil {
 			return err
 		}
@@ -56,93 +101,344 @@ func list(ctx *cli.Context) error {
 func listCertificates(ctx *cli.Context) error {
 	certsStorage := NewCertificatesStorage(ctx)
 
-	matches, err := filepath.Glob(filepath.Join(certsStorage.GetRootPath(), "*.crt"))
+	matches, err := certsStorage.ListCertificates()
 	if err != nil {
 		return err
 	}
 
+	now := time.Now()
+
+	infos := []*certificateInfo{}
+
+	for _, filename := range matches {
+		stored, err := certsStorage.readStoredCertificate(filename)
+		if err != nil {
+			return err
+		}
+
+		if !expiringWithin(ctx, stored, now) {
+			continue
+		}
+
+		info, err := newCertificateInfo(certsStorage, stored, now)
+		if err != nil {
+			return err
+		}
+
+		infos = append(infos, info)
+	}
+
 	names := ctx.Bool(flgNames)
 
-	if len(matches) == 0 {
-		if !names {
-			fmt.Println("No certificates found.")
+	switch {
+	case names:
+		for _, info := range infos {
+			fmt.Println(info.Name)
 		}
+
 		return nil
+
+	case ctx.String(flgFormat) != formatText:
+		return writeCertificateInfos(ctx.String(flgFormat), infos)
 	}
 
-	if !names {
-		fmt.Println("Found the following certs:")
+	if len(infos) == 0 {
+		fmt.Println("No certificates found.")
+		return nil
 	}
 
-	for _, filename := range matches {
-		if strings.HasSuffix(filename, issuerExt) {
-			continue
+	fmt.Println("Found the following certs:")
+
+	for _, info := range infos {
+		fmt.Println("  Certificate Name:", info.Name)
+		fmt.Println("    Domains:", strings.Join(info.Domains, ", "))
+
+		if len(info.IPAddresses) > 0 {
+			fmt.Println("    IP Addresses:", strings.Join(info.IPAddresses, ", "))
 		}
 
-		data, err := os.ReadFile(filename)
+		fmt.Println("    Expiry Date:", info.NotAfter)
+		fmt.Println("    Certificate Path:", info.Path)
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// expiringWithin checks if a certificate expires within the duration of the --expiring-within option, if any.
+func expiringWithin(ctx *cli.Context, stored *storedCertificate, now time.Time) bool {
+	if !ctx.IsSet(flgExpiringWithin) {
+		return true
+	}
+
+	return stored.Certificate.NotAfter.Before(now.Add(ctx.Duration(flgExpiringWithin)))
+}
+
+// writeCertificateInfos writes the certificates in the json or csv format.
+func writeCertificateInfos(format string, infos []*certificateInfo) error {
+	if format == formatJSON {
+		return writeJSON(infos)
+	}
+
+	records := [][]string{certificateInfoHeader}
+	for _, info := range infos {
+		records = append(records, info.record())
+	}
+
+	return writeCSV(records)
+}
+
+func writeJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+func writeCSV(records [][]string) error {
+	writer := csv.NewWriter(os.Stdout)
+
+	return writer.WriteAll(records)
+}
+
+// listConfigCertificates displays the certificates of the configuration file.
+// With the json and csv formats, only the obtained certificates are displayed.
+func listConfigCertificates(ctx *cli.Context) error {
+	names := ctx.Bool(flgNames)
+	format := ctx.String(flgFormat)
+
+	if format != formatText && !names {
+		return listConfigCertificateInfos(ctx, format)
+	}
+
+	if !names {
+		fmt.Println("Certificates of the configuration file:")
+	}
+
+	now := time.Now()
+
+	return forEachCertificate(ctx, func(certCtx *cli.Context) error {
+		domains := certCtx.StringSlice(flgDomains)
+
+		certsStorage, err := openCertificatesStorage(certCtx)
 		if err != nil {
 			return err
 		}
 
-		pCert, err := certcrypto.ParsePEMCertificate(data)
+		stored, err := certsStorage.readStoredCertificate(domains[0])
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		if ctx.IsSet(flgExpiringWithin) && (stored == nil || !expiringWithin(ctx, stored, now)) {
+			return nil
+		}
+
+		if names {
+			fmt.Println(domains[0])
+			return nil
+		}
+
+		fmt.Println("  Certificate Name:", domains[0])
+		fmt.Println("    Domains:", strings.Join(domains, ", "))
+
+		if stored == nil {
+			fmt.Println("    Not obtained yet.")
+			fmt.Println()
+
+			return nil
+		}
+
+		fmt.Println("    Expiry Date:", stored.Certificate.NotAfter)
+		fmt.Println("    Certificate Path:", certsStorage.GetFileName(domains[0], certExt))
+		fmt.Println()
+
+		return nil
+	})
+}
+
+// listConfigCertificateInfos displays the obtained certificates of the configuration file in the json or csv format.
+func listConfigCertificateInfos(ctx *cli.Context, format string) error {
+	now := time.Now()
+
+	infos := []*certificateInfo{}
+
+	err := forEachCertificate(ctx, func(certCtx *cli.Context) error {
+		certsStorage, err := openCertificatesStorage(certCtx)
 		if err != nil {
 			return err
 		}
 
-		name, err := certcrypto.GetCertificateMainDomain(pCert)
+		stored, err := certsStorage.readStoredCertificate(certCtx.StringSlice(flgDomains)[0])
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
 		if err != nil {
 			return err
 		}
 
-		if names {
-			fmt.Println(name)
-		} else {
-			fmt.Println("  Certificate Name:", name)
-			fmt.Println("    Domains:", strings.Join(pCert.DNSNames, ", "))
-			fmt.Println("    Expiry Date:", pCert.NotAfter)
-			fmt.Println("    Certificate Path:", filename)
-			fmt.Println()
+		if !expiringWithin(ctx, stored, now) {
+			return nil
 		}
+
+		info, err := newCertificateInfo(certsStorage, stored, now)
+		if err != nil {
+			return err
+		}
+
+		infos = append(infos, info)
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return writeCertificateInfos(format, infos)
 }
 
 func listAccount(ctx *cli.Context) error {
 	accountsStorage := NewAccountsStorage(ctx)
 
-	matches, err := filepath.Glob(filepath.Join(accountsStorage.GetRootPath(), "*", "*", "*.json"))
+	accounts, err := accountsStorage.ReadAllAccounts()
 	if err != nil {
 		return err
 	}
 
-	if len(matches) == 0 {
+	if len(accounts) == 0 {
 		fmt.Println("No accounts found.")
 		return nil
 	}
 
 	fmt.Println("Found the following accounts:")
-	for _, filename := range matches {
-		data, err := os.ReadFile(filename)
+	for _, location := range slices.Sorted(maps.Keys(accounts)) {
+		account := accounts[location]
+
+		uri, err := url.Parse(account.Registration.URI)
 		if err != nil {
 			return err
 		}
 
-		var account Account
-		err = json.Unmarshal(data, &account)
+		fmt.Println("  Email:", account.Email)
+		fmt.Println("  Server:", uri.Host)
+		fmt.Println("  Path:", location)
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// accountInfo the description of an account (list --accounts --format).
+type accountInfo struct {
+	Email  string `json:"email"`
+	Server string `json:"server"`
+	URI    string `json:"uri"`
+	Path   string `json:"path"`
+}
+
+// listAccountInfos displays the accounts in the json or csv format.
+func listAccountInfos(ctx *cli.Context, format string) error {
+	accounts, err := NewAccountsStorage(ctx).ReadAllAccounts()
+	if err != nil {
+		return err
+	}
+
+	infos := []*accountInfo{}
+
+	for _, location := range slices.Sorted(maps.Keys(accounts)) {
+		account := accounts[location]
+
+		uri, err := url.Parse(account.Registration.URI)
 		if err != nil {
 			return err
 		}
 
-		uri, err := url.Parse(account.Registration.URI)
+		infos = append(infos, &accountInfo{
+			Email:  account.Email,
+			Server: uri.Host,
+			URI:    account.Registration.URI,
+			Path:   location,
+		})
+	}
+
+	if format == formatJSON {
+		return writeJSON(infos)
+	}
+
+	records := [][]string{{"email", "server", "uri", "path"}}
+	for _, info := range infos {
+		records = append(records, []string{info.Email, info.Server, info.URI, info.Path})
+	}
+
+	return writeCSV(records)
+}
+
+func listOrders(ctx *cli.Context) error {
+	// The accounts of the server are located next to the (unknown) user.
+	userIDs, err := NewAccountsStorage(ctx).ListAccounts()
+	if err != nil {
+		return err
+	}
+
+	if len(userIDs) == 0 {
+		fmt.Println("No accounts found.")
+		return nil
+	}
+
+	for _, userID := range userIDs {
+		accountsStorage := newAccountsStorage(ctx, userID)
+
+		privateKey, err := accountsStorage.ReadPrivateKey()
 		if err != nil {
-			return err
+			return fmt.Errorf("could not load the private key of the account %s: %w", accountsStorage.GetUserID(), err)
 		}
 
-		fmt.Println("  Email:", account.Email)
-		fmt.Println("  Server:", uri.Host)
-		fmt.Println("  Path:", filepath.Dir(filename))
-		fmt.Println()
+		account := accountsStorage.LoadAccount(privateKey)
+
+		client := newClient(ctx, account, getKeyType(ctx))
+
+		orders, err := client.Registration.ListOrders(true)
+		if err != nil {
+			return fmt.Errorf("could not list the orders of the account %s: %w", account.Email, err)
+		}
+
+		orders = slices.DeleteFunc(orders, func(order acme.ExtendedOrder) bool {
+			return order.Status != acme.StatusPending && order.Status != acme.StatusInvalid
+		})
+
+		if len(orders) == 0 {
+			fmt.Printf("No pending or invalid orders found for the account %s.\n", account.Email)
+			fmt.Println()
+
+			continue
+		}
+
+		fmt.Printf("Found the following pending or invalid orders for the account %s:\n", account.Email)
+
+		for _, order := range orders {
+			var domains []string
+			for _, identifier := range order.Identifiers {
+				domains = append(domains, identifier.Value)
+			}
+
+			fmt.Println("  Order:", order.Location)
+			fmt.Println("    Status:", order.Status)
+			fmt.Println("    Domains:", strings.Join(domains, ", "))
+
+			if order.Expires != "" {
+				fmt.Println("    Expires:", order.Expires)
+			}
+
+			if order.Error != nil {
+				fmt.Println("    Error:", order.Error.Type, "::", order.Error.Detail)
+
+				for _, sub := range order.Error.SubProblems {
+					fmt.Printf("      %s: %s :: %s\n", sub.Identifier.Value, sub.Type, sub.Detail)
+				}
+			}
+
+			fmt.Println()
+		}
 	}
 
 	return nil
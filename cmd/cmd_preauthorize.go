@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+func createPreAuthorize() *cli.Command {
+	return &cli.Command{
+		Name: "preauthorize",
+		Usage: "Pre-authorize domains, so that certificates can be obtained later without solving challenges." +
+			" A wildcard domain cannot be pre-authorized (RFC 8555 section 7.4.1).",
+		Before: func(ctx *cli.Context) error {
+			if len(ctx.StringSlice(flgDomains)) == 0 {
+				log.Fatal("Please specify --domains/-d")
+			}
+			return nil
+		},
+		Action: preAuthorize,
+	}
+}
+
+func preAuthorize(ctx *cli.Context) error {
+	account, keyType, unlock := setupAccount(ctx, NewAccountsStorage(ctx))
+
+	defer unlock()
+
+	if account.Registration == nil {
+		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+	}
+
+	client, err := setupClient(ctx, account, keyType, certificate.EventIssued)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	authorizations, err := client.Certificate.PreAuthorize(ctx.StringSlice(flgDomains))
+	if err != nil {
+		log.Fatalf("Could not pre-authorize the domains:\n\t%v", err)
+	}
+
+	for _, authz := range authorizations {
+		log.Printf("[%s] The authorization is %s until %s: %s",
+			authz.Identifier.Value, authz.Status, authz.Expires.Format(time.RFC3339), authz.Location)
+	}
+
+	return nil
+}
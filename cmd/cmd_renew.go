@@ -4,11 +4,13 @@ import (
 	"crypto"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"math/rand"
 	"os"
 	"slices"
 	"time"
 
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
@@ -27,6 +29,7 @@ const (
 	flgReuseKey               = "reuse-key"
 	flgRenewHook              = "renew-hook"
 	flgRenewHookTimeout       = "renew-hook-timeout"
+	flgSkipHook               = "skip-hook"
 	flgNoRandomSleep          = "no-random-sleep"
 	flgForceCertDomains       = "force-cert-domains"
 )
@@ -37,6 +40,11 @@ func createRenew() *cli.Command {
 		Usage:  "Renew a certificate",
 		Action: renew,
 		Before: func(ctx *cli.Context) error {
+			// the certificates are described by the configuration file
+			if ctx.IsSet(flgConfig) {
+				return nil
+			}
+
 			// we require either domains or csr, but not both
 			hasDomains := len(ctx.StringSlice(flgDomains)) > 0
 			hasCsr := ctx.String(flgCSR) != ""
@@ -49,6 +57,7 @@ func createRenew() *cli.Command {
 			if ctx.Bool(flgForceCertDomains) && hasCsr {
 				log.Fatalf("--%s only works with --%s/-d, --%s/-c doesn't support this option.", flgForceCertDomains, flgDomains, flgCSR)
 			}
+			checkMaxIdentifiers(ctx, hasDomains)
 			return nil
 		},
 		Flags: []cli.Flag{
@@ -111,6 +120,18 @@ func createRenew() *cli.Command {
 				Name:  flgRenewHook,
 				Usage: "Define a hook. The hook is executed only when the certificates are effectively renewed.",
 			},
+			&cli.StringFlag{
+				Name:  flgPreHook,
+				Usage: "Define a hook. The hook is executed before renewing a certificate, the renewal is aborted if the hook fails.",
+			},
+			&cli.StringFlag{
+				Name:  flgFailureHook,
+				Usage: "Define a hook. The hook is executed when a certificate cannot be renewed.",
+			},
+			&cli.StringFlag{
+				Name:  flgSkipHook,
+				Usage: "Define a hook. The hook is executed when a certificate doesn't need to be renewed, or when the renewal is deferred by the CA.",
+			},
 			&cli.DurationFlag{
 				Name:  flgRenewHookTimeout,
 				Usage: "Define the timeout for the hook execution.",
@@ -125,55 +146,102 @@ func createRenew() *cli.Command {
 				Name:  flgForceCertDomains,
 				Usage: "Check and ensure that the cert's domain list matches those passed in the domains argument.",
 			},
+			&cli.IntFlag{
+				Name: flgMaxIdentifiers,
+				Usage: "Split the domains into several certificates of at most this number of identifiers (only with --domains/-d)." +
+					" By default, a split certificate keeps the value used to obtain it.",
+			},
+			&cli.BoolFlag{
+				Name:  flgAllowPartial,
+				Usage: "Drop the domains with failed validations from the certificate instead of failing the whole certificate (only with --domains/-d).",
+			},
 		},
 	}
 }
 
 func renew(ctx *cli.Context) error {
-	account, keyType := setupAccount(ctx, NewAccountsStorage(ctx))
+	if ctx.IsSet(flgConfig) {
+		return forEachCertificate(ctx, renew)
+	}
+
+	accountsStorage, err := openAccountsStorageFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	account, keyType, unlock, err := openAccount(ctx, accountsStorage)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
 
 	if account.Registration == nil {
-		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+		return fmt.Errorf("account %s is not registered: use 'run' to register a new account", account.Email)
 	}
 
-	certsStorage := NewCertificatesStorage(ctx)
+	certsStorage, err := openCertificatesStorage(ctx)
+	if err != nil {
+		return err
+	}
 
 	bundle := !ctx.Bool(flgNoBundle)
 
-	meta := map[string]string{hookEnvAccountEmail: account.Email}
+	hooks := newHookRunner(ctx, account.Email, ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout))
 
 	// CSR
 	if ctx.IsSet(flgCSR) {
-		return renewForCSR(ctx, account, keyType, certsStorage, bundle, meta)
+		return renewForCSR(ctx, account, keyType, certsStorage, bundle, hooks)
 	}
 
 	// Domains
-	return renewForDomains(ctx, account, keyType, certsStorage, bundle, meta)
+	return renewForDomains(ctx, account, keyType, certsStorage, bundle, hooks)
 }
 
-func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle bool, meta map[string]string) error {
+func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle bool, hooks *hookRunner) error {
 	domains := ctx.StringSlice(flgDomains)
 	domain := domains[0]
 
+	// The other lego processes wait for the renewal of the certificate (or of the group).
+	defer certsStorage.Lock(domain)()
+
+	group, err := certsStorage.ReadGroup(domain)
+	if err != nil {
+		return fmt.Errorf("error while loading the group for domain %s: %w", domain, err)
+	}
+
+	if group == nil && ctx.Int(flgMaxIdentifiers) > 0 {
+		// The certificate is not split yet.
+		group = &CertificateGroup{Domain: domain, Parts: []string{domain}}
+	}
+
+	if group != nil {
+		return renewGroup(ctx, account, keyType, certsStorage, bundle, group, hooks)
+	}
+
 	// load the cert resource from files.
 	// We store the certificate, private key and metadata in different files
 	// as web servers would not be able to work with a combined file.
-	certificates, err := certsStorage.ReadCertificate(domain, certExt)
+	cert, err := readLeafCertificate(certsStorage, domain)
 	if err != nil {
-		log.Fatalf("Error while loading the certificate for domain %s\n\t%v", domain, err)
+		return err
 	}
 
-	cert := certificates[0]
+	hooks.expiring(domain, cert)
 
 	var ariRenewalTime *time.Time
+	var ariWindow *acme.Window
 	var replacesCertID string
 
 	var client *lego.Client
 
 	if !ctx.Bool(flgARIDisable) {
-		client = setupClient(ctx, account, keyType)
+		client, err = setupClient(ctx, account, keyType, certificate.EventRenewed)
+		if err != nil {
+			return err
+		}
 
-		ariRenewalTime = getARIRenewalTime(ctx, cert, domain, client)
+		ariRenewalTime, ariWindow = getARIRenewalTime(ctx, cert, domain, client)
 		if ariRenewalTime != nil {
 			now := time.Now().UTC()
 
@@ -186,7 +254,7 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 
 		replacesCertID, err = certificate.MakeARICertID(cert)
 		if err != nil {
-			log.Fatalf("Error while construction the ARI CertID for domain %s\n\t%v", domain, err)
+			return fmt.Errorf("error while construction the ARI CertID for domain %s: %w", domain, err)
 		}
 	}
 
@@ -196,11 +264,14 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 
 	if ariRenewalTime == nil && !needRenewal(cert, domain, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic)) &&
 		(!forceDomains || slices.Equal(certDomains, domains)) {
-		return nil
+		return hooks.skip(domain, cert, ariWindow, nil)
 	}
 
 	if client == nil {
-		client = setupClient(ctx, account, keyType)
+		client, err = setupClient(ctx, account, keyType, certificate.EventRenewed)
+		if err != nil {
+			return err
+		}
 	}
 
 	// This is just meant to be informal for the user.
@@ -209,9 +280,9 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 
 	var privateKey crypto.PrivateKey
 	if ctx.Bool(flgReuseKey) {
-		keyBytes, errR := certsStorage.ReadFile(domain, keyExt)
+		keyBytes, errR := certsStorage.ReadPrivateKey(domain)
 		if errR != nil {
-			log.Fatalf("Error while loading the private key for domain %s\n\t%v", domain, errR)
+			return fmt.Errorf("error while loading the private key for domain %s: %w", domain, errR)
 		}
 
 		privateKey, errR = certcrypto.ParsePEMPrivateKey(keyBytes)
@@ -220,17 +291,7 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 		}
 	}
 
-	// https://github.com/go-acme/lego/issues/1656
-	// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L435-L440
-	if !isatty.IsTerminal(os.Stdout.Fd()) && !ctx.Bool(flgNoRandomSleep) {
-		// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L472
-		const jitter = 8 * time.Minute
-		rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
-		sleepTime := time.Duration(rnd.Int63n(int64(jitter)))
-
-		log.Infof("renewal: random delay of %s", sleepTime)
-		time.Sleep(sleepTime)
-	}
+	randomSleep(ctx)
 
 	renewalDomains := slices.Clone(domains)
 	if !forceDomains {
@@ -247,56 +308,254 @@ func renewForDomains(ctx *cli.Context, account *Account, keyType certcrypto.KeyT
 		PreferredChain:                 ctx.String(flgPreferredChain),
 		Profile:                        ctx.String(flgProfile),
 		AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations),
+		AllowPartial:                   ctx.Bool(flgAllowPartial),
 	}
 
 	if replacesCertID != "" {
 		request.ReplacesCertID = replacesCertID
 	}
 
+	err = hooks.pre(domain, renewalDomains, cert, ariWindow)
+	if err != nil {
+		return fmt.Errorf("[%s] pre hook: %w", domain, err)
+	}
+
 	certRes, err := client.Certificate.Obtain(request)
 	if err != nil {
-		log.Fatal(err)
+		if deferRenewal(ctx, domain, err) {
+			return hooks.skip(domain, cert, ariWindow, err)
+		}
+
+		hooks.failure(domain, renewalDomains, cert, err)
+
+		return err
 	}
 
+	// The certificate keeps its name, even if the first domain has been dropped (--allow-partial).
 	certRes.Domain = domain
 
+	logDroppedDomains(domain, certRes)
+
 	certsStorage.SaveResource(certRes)
 
-	addPathToMetadata(meta, domain, certRes, certsStorage)
+	err = deploy(ctx, certsStorage, certRes)
+	if err != nil {
+		return err
+	}
+
+	return hooks.post(certRes, certsStorage)
+}
+
+// renewGroup renews a certificate split into several certificates as a single certificate:
+// all the parts are renewed if one of them needs to be renewed.
+func renewGroup(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle bool, group *CertificateGroup, hooks *hookRunner) error {
+	if ctx.IsSet(flgFilename) {
+		return fmt.Errorf("[%s] --%s cannot be used with a split certificate", group.Domain, flgFilename)
+	}
+
+	domains := ctx.StringSlice(flgDomains)
+	domain := domains[0]
+
+	var client *lego.Client
+	var err error
+
+	if !ctx.Bool(flgARIDisable) {
+		client, err = setupClient(ctx, account, keyType, certificate.EventRenewed)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		ariRenewalTime *time.Time
+		ariWindow      *acme.Window
+		replaces       []certificate.SplitPart
+		certDomains    []string
+		renewal        bool
+		notAfter       time.Time
+		current        *x509.Certificate // The part which expires first.
+	)
+
+	for _, part := range group.Parts {
+		// load the cert resource from files.
+		cert, err := readLeafCertificate(certsStorage, part)
+		if err != nil {
+			return err
+		}
+
+		hooks.expiring(part, cert)
+
+		certDomains = merge(certDomains, certcrypto.ExtractDomains(cert))
+
+		if notAfter.IsZero() || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+			current = cert
+		}
+
+		if client != nil {
+			partRenewalTime, partWindow := getARIRenewalTime(ctx, cert, part, client)
+			if partRenewalTime != nil && (ariRenewalTime == nil || partRenewalTime.Before(*ariRenewalTime)) {
+				ariRenewalTime = partRenewalTime
+			}
+
+			if partWindow != nil && (ariWindow == nil || partWindow.Start.Before(ariWindow.Start)) {
+				ariWindow = partWindow
+			}
+
+			certID, err := certificate.MakeARICertID(cert)
+			if err != nil {
+				return fmt.Errorf("error while construction the ARI CertID for domain %s: %w", part, err)
+			}
+
+			replaces = append(replaces, certificate.SplitPart{Name: part, Domains: certcrypto.ExtractDomains(cert), CertID: certID})
+		}
+
+		if needRenewal(cert, part, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic)) {
+			renewal = true
+		}
+	}
+
+	if ariRenewalTime != nil {
+		now := time.Now().UTC()
+
+		// Figure out if we need to sleep before renewing.
+		if ariRenewalTime.After(now) {
+			log.Infof("[%s] Sleeping %s until renewal time %s", domain, ariRenewalTime.Sub(now), ariRenewalTime)
+			time.Sleep(ariRenewalTime.Sub(now))
+		}
+	}
+
+	forceDomains := ctx.Bool(flgForceCertDomains)
+
+	if ariRenewalTime == nil && !renewal && (!forceDomains || equalDomains(certDomains, domains)) {
+		return hooks.skip(domain, current, ariWindow, nil)
+	}
+
+	if client == nil {
+		client, err = setupClient(ctx, account, keyType, certificate.EventRenewed)
+		if err != nil {
+			return err
+		}
+	}
+
+	// This is just meant to be informal for the user.
+	timeLeft := notAfter.Sub(time.Now().UTC())
+	log.Infof("[%s] acme: Trying renewal of %d certificates with %d hours remaining", domain, len(group.Parts), int(timeLeft.Hours()))
+
+	var privateKey crypto.PrivateKey
+	if ctx.Bool(flgReuseKey) {
+		keyBytes, errR := certsStorage.ReadPrivateKey(domain)
+		if errR != nil {
+			return fmt.Errorf("error while loading the private key for domain %s: %w", domain, errR)
+		}
+
+		privateKey, err = certcrypto.ParsePEMPrivateKey(keyBytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	randomSleep(ctx)
+
+	renewalDomains := slices.Clone(domains)
+	if !forceDomains {
+		// The main domain stays the first domain: it is the name of the certificate.
+		renewalDomains = merge([]string{domain}, merge(certDomains, domains))
+	}
 
-	return launchHook(ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout), meta)
+	request := certificate.ObtainRequest{
+		Domains:                        renewalDomains,
+		PrivateKey:                     privateKey,
+		MustStaple:                     ctx.Bool(flgMustStaple),
+		NotBefore:                      getTime(ctx, flgNotBefore),
+		NotAfter:                       getTime(ctx, flgNotAfter),
+		Bundle:                         bundle,
+		PreferredChain:                 ctx.String(flgPreferredChain),
+		Profile:                        ctx.String(flgProfile),
+		AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations),
+		AllowPartial:                   ctx.Bool(flgAllowPartial),
+	}
+
+	maxIdentifiers := group.MaxIdentifiers
+	if ctx.Int(flgMaxIdentifiers) > 0 {
+		maxIdentifiers = ctx.Int(flgMaxIdentifiers)
+	}
+
+	err = hooks.pre(domain, renewalDomains, current, ariWindow)
+	if err != nil {
+		return fmt.Errorf("[%s] pre hook: %w", domain, err)
+	}
+
+	resources, err := client.Certificate.ObtainSplit(request, &certificate.SplitOptions{
+		MaxIdentifiers: maxIdentifiers,
+		Replaces:       replaces,
+	})
+	if err != nil {
+		// The parts already obtained are kept.
+		if len(resources) > 0 {
+			certsStorage.SavePartialGroup(domain, maxIdentifiers, resources)
+		}
+
+		if deferRenewal(ctx, domain, err) {
+			return hooks.skip(domain, current, ariWindow, err)
+		}
+
+		hooks.failure(domain, renewalDomains, current, err)
+
+		return err
+	}
+
+	for _, resource := range resources {
+		logDroppedDomains(resource.Domain, resource)
+	}
+
+	certsStorage.SaveGroup(domain, maxIdentifiers, resources)
+
+	err = deployGroup(ctx, certsStorage, resources)
+	if err != nil {
+		return err
+	}
+
+	return hooks.postGroup(resources, certsStorage)
 }
 
-func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle bool, meta map[string]string) error {
+func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, certsStorage *CertificatesStorage, bundle bool, hooks *hookRunner) error {
 	csr, err := readCSRFile(ctx.String(flgCSR))
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
 	domain, err := certcrypto.GetCSRMainDomain(csr)
 	if err != nil {
-		log.Fatalf("Error: %v", err)
+		return err
 	}
 
+	// The other lego processes wait for the renewal of the certificate.
+	defer certsStorage.Lock(domain)()
+
 	// load the cert resource from files.
 	// We store the certificate, private key and metadata in different files
 	// as web servers would not be able to work with a combined file.
-	certificates, err := certsStorage.ReadCertificate(domain, certExt)
+	cert, err := readLeafCertificate(certsStorage, domain)
 	if err != nil {
-		log.Fatalf("Error while loading the certificate for domain %s\n\t%v", domain, err)
+		return err
 	}
 
-	cert := certificates[0]
+	hooks.expiring(domain, cert)
 
 	var ariRenewalTime *time.Time
+	var ariWindow *acme.Window
 	var replacesCertID string
 
 	var client *lego.Client
 
 	if !ctx.Bool(flgARIDisable) {
-		client = setupClient(ctx, account, keyType)
+		client, err = setupClient(ctx, account, keyType, certificate.EventRenewed)
+		if err != nil {
+			return err
+		}
 
-		ariRenewalTime = getARIRenewalTime(ctx, cert, domain, client)
+		ariRenewalTime, ariWindow = getARIRenewalTime(ctx, cert, domain, client)
 		if ariRenewalTime != nil {
 			now := time.Now().UTC()
 
@@ -309,16 +568,19 @@ func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType,
 
 		replacesCertID, err = certificate.MakeARICertID(cert)
 		if err != nil {
-			log.Fatalf("Error while construction the ARI CertID for domain %s\n\t%v", domain, err)
+			return fmt.Errorf("error while construction the ARI CertID for domain %s: %w", domain, err)
 		}
 	}
 
 	if ariRenewalTime == nil && !needRenewal(cert, domain, ctx.Int(flgRenewDays), ctx.Bool(flgRenewDynamic)) {
-		return nil
+		return hooks.skip(domain, cert, ariWindow, nil)
 	}
 
 	if client == nil {
-		client = setupClient(ctx, account, keyType)
+		client, err = setupClient(ctx, account, keyType, certificate.EventRenewed)
+		if err != nil {
+			return err
+		}
 	}
 
 	// This is just meant to be informal for the user.
@@ -339,16 +601,91 @@ func renewForCSR(ctx *cli.Context, account *Account, keyType certcrypto.KeyType,
 		request.ReplacesCertID = replacesCertID
 	}
 
+	csrDomains := certcrypto.ExtractDomainsCSR(csr)
+
+	err = hooks.pre(domain, csrDomains, cert, ariWindow)
+	if err != nil {
+		return fmt.Errorf("[%s] pre hook: %w", domain, err)
+	}
+
 	certRes, err := client.Certificate.ObtainForCSR(request)
 	if err != nil {
-		log.Fatal(err)
+		if deferRenewal(ctx, domain, err) {
+			return hooks.skip(domain, cert, ariWindow, err)
+		}
+
+		hooks.failure(domain, csrDomains, cert, err)
+
+		return err
 	}
 
 	certsStorage.SaveResource(certRes)
 
-	addPathToMetadata(meta, domain, certRes, certsStorage)
+	err = deploy(ctx, certsStorage, certRes)
+	if err != nil {
+		return err
+	}
+
+	return hooks.post(certRes, certsStorage)
+}
+
+// readLeafCertificate reads the certificate, and checks that the bundle starts with the leaf certificate.
+func readLeafCertificate(certsStorage *CertificatesStorage, domain string) (*x509.Certificate, error) {
+	certificates, err := certsStorage.ReadCertificate(domain, certExt)
+	if err != nil {
+		return nil, fmt.Errorf("error while loading the certificate for domain %s: %w", domain, err)
+	}
+
+	if certificates[0].IsCA {
+		return nil, fmt.Errorf("[%s] certificate bundle starts with a CA certificate", domain)
+	}
+
+	return certificates[0], nil
+}
+
+// randomSleep adds a random delay before the renewal when lego is not run in a terminal.
+func randomSleep(ctx *cli.Context) {
+	// https://github.com/go-acme/lego/issues/1656
+	// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L435-L440
+	if isatty.IsTerminal(os.Stdout.Fd()) || ctx.Bool(flgNoRandomSleep) {
+		return
+	}
+
+	// https://github.com/certbot/certbot/blob/284023a1b7672be2bd4018dd7623b3b92197d4b0/certbot/certbot/_internal/renewal.py#L472
+	const jitter = 8 * time.Minute
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	sleepTime := time.Duration(rnd.Int63n(int64(jitter)))
+
+	log.Infof("renewal: random delay of %s", sleepTime)
+	time.Sleep(sleepTime)
+}
+
+// equalDomains checks if the two lists contain the same domains, in any order.
+func equalDomains(a, b []string) bool {
+	a = slices.Clone(a)
+	slices.Sort(a)
+
+	b = slices.Clone(b)
+	slices.Sort(b)
 
-	return launchHook(ctx.String(flgRenewHook), ctx.Duration(flgRenewHookTimeout), meta)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// deferRenewal checks if the renewal has been rejected by a rate limit (or because the server is unavailable),
+// and if the server asks to wait (Retry-After) longer than --rate-limit.max-wait:
+// in this case the renewal is deferred to the next run instead of failing.
+// A shorter wait is handled by the retry policy of the client (--rate-limit.max-wait, --rate-limit.max-retries):
+// the renewal is done now, and fails if the retries have not been enough.
+func deferRenewal(ctx *cli.Context, domain string, err error) bool {
+	retryAfter, ok := api.GetRetryAfter(err)
+	if !ok || retryAfter <= ctx.Duration(flgRateLimitMaxWait) {
+		return false
+	}
+
+	log.Warnf("[%s] The renewal has been deferred: the server asks to retry after %s (%s): %v",
+		domain, retryAfter, time.Now().Add(retryAfter).UTC().Format(time.RFC3339), err)
+
+	return true
 }
 
 func needRenewal(x509Cert *x509.Certificate, domain string, days int, dynamic bool) bool {
@@ -376,20 +713,12 @@ func needRenewal(x509Cert *x509.Certificate, domain string, days int, dynamic bo
 }
 
 func needRenewalDynamic(x509Cert *x509.Certificate, now time.Time) bool {
-	lifetime := x509Cert.NotAfter.Sub(x509Cert.NotBefore)
-
-	var divisor int64 = 3
-	if lifetime.Round(24*time.Hour).Hours()/24.0 <= 10 {
-		divisor = 2
-	}
-
-	dueDate := x509Cert.NotAfter.Add(-1 * time.Duration(lifetime.Nanoseconds()/divisor))
-
-	return dueDate.Before(now)
+	return certificate.RenewalDueDate(x509Cert).Before(now)
 }
 
 // getARIRenewalTime checks if the certificate needs to be renewed using the renewalInfo endpoint.
-func getARIRenewalTime(ctx *cli.Context, cert *x509.Certificate, domain string, client *lego.Client) *time.Time {
+// It also returns the renewal window suggested by the server.
+func getARIRenewalTime(ctx *cli.Context, cert *x509.Certificate, domain string, client *lego.Client) (*time.Time, *acme.Window) {
 	if cert.IsCA {
 		log.Fatalf("[%s] Certificate bundle starts with a CA certificate", domain)
 	}
@@ -399,17 +728,19 @@ func getARIRenewalTime(ctx *cli.Context, cert *x509.Certificate, domain string,
 		if errors.Is(err, api.ErrNoARI) {
 			// The server does not advertise a renewal info endpoint.
 			log.Warnf("[%s] acme: %v", domain, err)
-			return nil
+			return nil, nil
 		}
 		log.Warnf("[%s] acme: calling renewal info endpoint: %v", domain, err)
-		return nil
+		return nil, nil
 	}
 
+	window := &renewalInfo.SuggestedWindow
+
 	now := time.Now().UTC()
 	renewalTime := renewalInfo.ShouldRenewAt(now, ctx.Duration(flgARIWaitToRenewDuration))
 	if renewalTime == nil {
 		log.Infof("[%s] acme: renewalInfo endpoint indicates that renewal is not needed", domain)
-		return nil
+		return nil, window
 	}
 	log.Infof("[%s] acme: renewalInfo endpoint indicates that renewal is needed", domain)
 
@@ -417,7 +748,7 @@ func getARIRenewalTime(ctx *cli.Context, cert *x509.Certificate, domain string,
 		log.Infof("[%s] acme: renewalInfo endpoint provided an explanation: %s", domain, renewalInfo.ExplanationURL)
 	}
 
-	return renewalTime
+	return renewalTime, window
 }
 
 func merge(prevDomains, nextDomains []string) []string {
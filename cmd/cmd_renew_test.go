@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"crypto/x509"
+	"flag"
 	"testing"
 	"time"
 
+	"github.com/go-acme/lego/v4/acme"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
 )
 
 func Test_merge(t *testing.T) {
@@ -167,3 +171,57 @@ func Test_needRenewalDynamic(t *testing.T) {
 		})
 	}
 }
+
+func Test_equalDomains(t *testing.T) {
+	assert.True(t, equalDomains([]string{"a.com", "b.com"}, []string{"b.com", "a.com"}))
+	assert.True(t, equalDomains([]string{"a.com", "b.com", "a.com"}, []string{"b.com", "a.com"}))
+	assert.False(t, equalDomains([]string{"a.com", "b.com"}, []string{"a.com", "c.com"}))
+	assert.False(t, equalDomains([]string{"a.com"}, []string{"a.com", "b.com"}))
+}
+
+func Test_deferRenewal(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		maxWait  string
+		err      error
+		expected bool
+	}{
+		{
+			desc: "no Retry-After",
+			err:  &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:rateLimited"},
+		},
+		{
+			desc:     "no retry policy",
+			err:      &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:rateLimited", RetryAfter: time.Minute},
+			expected: true,
+		},
+		{
+			desc:    "within the maximum wait",
+			maxWait: "5m",
+			err:     &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:rateLimited", RetryAfter: time.Minute},
+		},
+		{
+			desc:     "beyond the maximum wait",
+			maxWait:  "5m",
+			err:      &acme.ProblemDetails{Type: "urn:ietf:params:acme:error:rateLimited", RetryAfter: time.Hour},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			set := flag.NewFlagSet("renew", flag.ContinueOnError)
+			set.Duration(flgRateLimitMaxWait, 0, "")
+
+			if test.maxWait != "" {
+				require.NoError(t, set.Set(flgRateLimitMaxWait, test.maxWait))
+			}
+
+			ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+			assert.Equal(t, test.expected, deferRenewal(ctx, "example.com", test.err))
+		})
+	}
+}
@@ -1,15 +1,22 @@
 package cmd
 
 import (
+	"crypto"
+	"os"
+	"slices"
+
 	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/urfave/cli/v2"
 )
 
 // Flag names.
 const (
-	flgKeep   = "keep"
-	flgReason = "reason"
+	flgKeep      = "keep"
+	flgReason    = "reason"
+	flgRevokeKey = "key"
 )
 
 func createRevoke() *cli.Command {
@@ -33,23 +40,48 @@ func createRevoke() *cli.Command {
 					" 9 (privilegeWithdrawn), or 10 (aACompromise).",
 				Value: acme.CRLReasonUnspecified,
 			},
+			&cli.StringFlag{
+				Name: flgRevokeKey,
+				Usage: "Path to the private key of the certificate (PEM)." +
+					" The revocation request is signed with this key instead of the account key, so no account is required.",
+			},
 		},
 	}
 }
 
 func revoke(ctx *cli.Context) error {
-	account, keyType := setupAccount(ctx, NewAccountsStorage(ctx))
+	var revokeCert func(cert []byte, reason *uint) error
 
-	if account.Registration == nil {
-		log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
-	}
+	if ctx.IsSet(flgRevokeKey) {
+		privateKey := readCertificateKey(ctx.String(flgRevokeKey))
+
+		client := newRevokeClient(ctx, privateKey)
+
+		revokeCert = func(cert []byte, reason *uint) error {
+			return client.Certificate.RevokeWithKey(cert, privateKey, reason)
+		}
+	} else {
+		account, keyType, unlock := setupAccount(ctx, NewAccountsStorage(ctx))
+
+		defer unlock()
+
+		if account.Registration == nil {
+			log.Fatalf("Account %s is not registered. Use 'run' to register a new account.\n", account.Email)
+		}
 
-	client := newClient(ctx, account, keyType)
+		client := newClient(ctx, account, keyType)
+
+		revokeCert = client.Certificate.RevokeWithReason
+	}
 
 	certsStorage := NewCertificatesStorage(ctx)
-	certsStorage.CreateRootFolder()
 
-	for _, domain := range ctx.StringSlice(flgDomains) {
+	// The locks are taken in the same order by all the lego processes.
+	for _, domain := range slices.Compact(slices.Sorted(slices.Values(ctx.StringSlice(flgDomains)))) {
+		defer certsStorage.Lock(domain)()
+	}
+
+	for _, domain := range getRevokedCertificates(certsStorage, ctx.StringSlice(flgDomains)) {
 		log.Printf("Trying to revoke certificate for domain %s", domain)
 
 		certBytes, err := certsStorage.ReadFile(domain, certExt)
@@ -59,7 +91,7 @@ func revoke(ctx *cli.Context) error {
 
 		reason := ctx.Uint(flgReason)
 
-		err = client.Certificate.RevokeWithReason(certBytes, &reason)
+		err = revokeCert(certBytes, &reason)
 		if err != nil {
 			log.Fatalf("Error while revoking the certificate for domain %s\n\t%v", domain, err)
 		}
@@ -70,8 +102,6 @@ func revoke(ctx *cli.Context) error {
 			return nil
 		}
 
-		certsStorage.CreateArchiveFolder()
-
 		err = certsStorage.MoveToArchive(domain)
 		if err != nil {
 			return err
@@ -82,3 +112,48 @@ func revoke(ctx *cli.Context) error {
 
 	return nil
 }
+
+// getRevokedCertificates returns the names of the certificates to revoke:
+// all the parts of a split certificate are revoked.
+func getRevokedCertificates(certsStorage *CertificatesStorage, domains []string) []string {
+	var names []string
+
+	for _, domain := range domains {
+		group, err := certsStorage.ReadGroup(domain)
+		if err != nil {
+			log.Fatalf("Error while loading the group for domain %s\n\t%v", domain, err)
+		}
+
+		if group == nil {
+			names = append(names, domain)
+			continue
+		}
+
+		// The main part is the last one to be archived with the group.
+		for _, part := range slices.Backward(group.Parts) {
+			names = append(names, part)
+		}
+	}
+
+	return names
+}
+
+// newRevokeClient creates a client without account:
+// the requests are signed with the private key of the certificate and contain the public key (jwk).
+func newRevokeClient(ctx *cli.Context, privateKey crypto.PrivateKey) *lego.Client {
+	return newClient(ctx, &Account{key: privateKey}, getKeyType(ctx))
+}
+
+func readCertificateKey(filename string) crypto.PrivateKey {
+	keyBytes, err := os.ReadFile(filename)
+	if err != nil {
+		log.Fatalf("Could not read the private key %s: %v", filename, err)
+	}
+
+	privateKey, err := certcrypto.ParsePEMPrivateKey(keyBytes)
+	if err != nil {
+		log.Fatalf("Could not parse the private key %s: %v", filename, err)
+	}
+
+	return privateKey
+}
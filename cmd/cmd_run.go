@@ -2,11 +2,16 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/log"
@@ -26,6 +31,12 @@ const (
 	flgAlwaysDeactivateAuthorizations = "always-deactivate-authorizations"
 	flgRunHook                        = "run-hook"
 	flgRunHookTimeout                 = "run-hook-timeout"
+	flgPreHook                        = "pre-hook"
+	flgFailureHook                    = "failure-hook"
+	flgDomainsFile                    = "domains-file"
+	flgWorkers                        = "workers"
+	flgMaxIdentifiers                 = "max-identifiers"
+	flgAllowPartial                   = "allow-partial"
 )
 
 func createRun() *cli.Command {
@@ -33,15 +44,25 @@ func createRun() *cli.Command {
 		Name:  "run",
 		Usage: "Register an account, then create and install a certificate",
 		Before: func(ctx *cli.Context) error {
-			// we require either domains or csr, but not both
+			// the certificates are described by the configuration file
+			if ctx.IsSet(flgConfig) {
+				return nil
+			}
+
+			// we require either domains, csr, or domains-file, but only one of them
 			hasDomains := len(ctx.StringSlice(flgDomains)) > 0
 			hasCsr := ctx.String(flgCSR) != ""
+			hasDomainsFile := ctx.String(flgDomainsFile) != ""
 			if hasDomains && hasCsr {
 				log.Fatal("Please specify either --domains/-d or --csr/-c, but not both")
 			}
-			if !hasDomains && !hasCsr {
-				log.Fatal("Please specify --domains/-d (or --csr/-c if you already have a CSR)")
+			if hasDomainsFile && (hasDomains || hasCsr) {
+				log.Fatal("Please specify either --domains-file or --domains/-d or --csr/-c, but only one of them")
+			}
+			if !hasDomains && !hasCsr && !hasDomainsFile {
+				log.Fatal("Please specify --domains/-d (or --csr/-c if you already have a CSR, or --domains-file to obtain several certificates)")
 			}
+			checkMaxIdentifiers(ctx, hasDomains)
 			return nil
 		},
 		Action: run,
@@ -86,11 +107,38 @@ func createRun() *cli.Command {
 				Name:  flgRunHook,
 				Usage: "Define a hook. The hook is executed when the certificates are effectively created.",
 			},
+			&cli.StringFlag{
+				Name:  flgPreHook,
+				Usage: "Define a hook. The hook is executed before obtaining a certificate, the certificate is not obtained if the hook fails.",
+			},
+			&cli.StringFlag{
+				Name:  flgFailureHook,
+				Usage: "Define a hook. The hook is executed when a certificate cannot be obtained.",
+			},
 			&cli.DurationFlag{
 				Name:  flgRunHookTimeout,
 				Usage: "Define the timeout for the hook execution.",
 				Value: 2 * time.Minute,
 			},
+			&cli.StringFlag{
+				Name: flgDomainsFile,
+				Usage: "Path to a file listing the certificates to obtain, one certificate per line." +
+					" The domains of a certificate are separated by commas or spaces. The empty lines and the lines starting with '#' are ignored.",
+			},
+			&cli.IntFlag{
+				Name:  flgWorkers,
+				Usage: "Maximum number of certificates obtained concurrently (only with --domains-file).",
+				Value: 4,
+			},
+			&cli.IntFlag{
+				Name: flgMaxIdentifiers,
+				Usage: "Split the domains into several certificates of at most this number of identifiers (only with --domains/-d)." +
+					" The certificates are managed as one certificate named after the first domain. By default, the domains are not split.",
+			},
+			&cli.BoolFlag{
+				Name:  flgAllowPartial,
+				Usage: "Drop the domains with failed validations from the certificate instead of failing the whole certificate (only with --domains/-d).",
+			},
 		},
 	}
 }
@@ -107,45 +155,118 @@ backups of this folder is ideal.
 `
 
 func run(ctx *cli.Context) error {
-	accountsStorage := NewAccountsStorage(ctx)
+	if ctx.IsSet(flgConfig) {
+		return forEachCertificate(ctx, run)
+	}
+
+	accountsStorage, err := openAccountsStorageFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	account, keyType, unlock, err := openAccount(ctx, accountsStorage)
+	if err != nil {
+		return err
+	}
 
-	account, keyType := setupAccount(ctx, accountsStorage)
+	// The shared lock is released during the registration.
+	defer func() { unlock() }()
 
-	client := setupClient(ctx, account, keyType)
+	client, err := setupClient(ctx, account, keyType, certificate.EventIssued)
+	if err != nil {
+		return err
+	}
 
 	if account.Registration == nil {
-		reg, err := register(ctx, client)
+		// The registration requires the exclusive lock of the account.
+		unlock()
+		unlock = func() {}
+
+		err = registerAccount(ctx, client, accountsStorage, account)
 		if err != nil {
-			log.Fatalf("Could not complete registration\n\t%v", err)
+			return err
 		}
 
-		account.Registration = reg
-		if err = accountsStorage.Save(account); err != nil {
-			log.Fatal(err)
+		relock, err := accountsStorage.rLock()
+		if err != nil {
+			return err
 		}
 
+		unlock = relock
+
 		fmt.Printf(rootPathWarningMessage, accountsStorage.GetRootPath())
 	}
 
-	certsStorage := NewCertificatesStorage(ctx)
-	certsStorage.CreateRootFolder()
+	certsStorage, err := openCertificatesStorage(ctx)
+	if err != nil {
+		return err
+	}
+
+	hooks := newHookRunner(ctx, account.Email, ctx.String(flgRunHook), ctx.Duration(flgRunHookTimeout))
+
+	if ctx.IsSet(flgDomainsFile) {
+		return runBatch(ctx, client, certsStorage, hooks)
+	}
+
+	if ctx.Int(flgMaxIdentifiers) > 0 {
+		return runSplit(ctx, client, certsStorage, hooks)
+	}
+
+	// The other lego processes wait for the certificate.
+	domains := ctx.StringSlice(flgDomains)
+	if len(domains) > 0 {
+		defer certsStorage.Lock(domains[0])()
+	}
+
+	requestDomains := getRequestDomains(ctx)
+
+	var name string
+	if len(requestDomains) > 0 {
+		name = requestDomains[0]
+	}
+
+	err = hooks.pre(name, requestDomains, nil, nil)
+	if err != nil {
+		return fmt.Errorf("[%s] pre hook: %w", name, err)
+	}
 
 	cert, err := obtainCertificate(ctx, client)
 	if err != nil {
+		hooks.failure(name, requestDomains, nil, err)
+
 		// Make sure to return a non-zero exit code if ObtainSANCertificate returned at least one error.
-		// Due to us not returning partial certificate we can just exit here instead of at the end.
-		log.Fatalf("Could not obtain certificates:\n\t%v", err)
+		return fmt.Errorf("could not obtain certificates: %w", err)
+	}
+
+	if len(domains) == 0 {
+		// With a CSR, the name of the certificate is known after the issuance.
+		defer certsStorage.Lock(cert.Domain)()
 	}
 
 	certsStorage.SaveResource(cert)
 
-	meta := map[string]string{
-		hookEnvAccountEmail: account.Email,
+	err = deploy(ctx, certsStorage, cert)
+	if err != nil {
+		return err
 	}
 
-	addPathToMetadata(meta, cert.Domain, cert, certsStorage)
+	return hooks.post(cert, certsStorage)
+}
 
-	return launchHook(ctx.String(flgRunHook), ctx.Duration(flgRunHookTimeout), meta)
+// getRequestDomains returns the domains of the certificate request: the domains, or the domains of the CSR.
+func getRequestDomains(ctx *cli.Context) []string {
+	domains := ctx.StringSlice(flgDomains)
+	if len(domains) > 0 {
+		return domains
+	}
+
+	// The errors are reported when obtaining the certificate.
+	csr, err := readCSRFile(ctx.String(flgCSR))
+	if err != nil {
+		return nil
+	}
+
+	return certcrypto.ExtractDomainsCSR(csr)
 }
 
 func handleTOS(ctx *cli.Context, client *lego.Client) bool {
@@ -176,10 +297,40 @@ func handleTOS(ctx *cli.Context, client *lego.Client) bool {
 	}
 }
 
+// registerAccount registers the account and saves it, unless another lego process has already registered it.
+func registerAccount(ctx *cli.Context, client *lego.Client, accountsStorage *AccountsStorage, account *Account) error {
+	unlock, err := accountsStorage.lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	if accountsStorage.ExistsAccountFilePath() {
+		existing, err := accountsStorage.readAccount(account.key)
+		if err != nil {
+			return err
+		}
+
+		account.Registration = existing.Registration
+
+		return nil
+	}
+
+	reg, err := register(ctx, client)
+	if err != nil {
+		return fmt.Errorf("could not complete registration: %w", err)
+	}
+
+	account.Registration = reg
+
+	return accountsStorage.Save(account)
+}
+
 func register(ctx *cli.Context, client *lego.Client) (*registration.Resource, error) {
 	accepted := handleTOS(ctx, client)
 	if !accepted {
-		log.Fatal("You did not accept the TOS. Unable to proceed.")
+		return nil, errors.New("you did not accept the TOS: unable to proceed")
 	}
 
 	if ctx.Bool(flgEAB) {
@@ -187,7 +338,7 @@ func register(ctx *cli.Context, client *lego.Client) (*registration.Resource, er
 		hmacEncoded := ctx.String(flgHMAC)
 
 		if kid == "" || hmacEncoded == "" {
-			log.Fatalf("Requires arguments --%s and --%s.", flgKID, flgHMAC)
+			return nil, fmt.Errorf("requires arguments --%s and --%s", flgKID, flgHMAC)
 		}
 
 		return client.Registration.RegisterWithExternalAccountBinding(registration.RegisterEABOptions{
@@ -206,26 +357,22 @@ func obtainCertificate(ctx *cli.Context, client *lego.Client) (*certificate.Reso
 	domains := ctx.StringSlice(flgDomains)
 	if len(domains) > 0 {
 		// obtain a certificate, generating a new private key
-		request := certificate.ObtainRequest{
-			Domains:                        domains,
-			MustStaple:                     ctx.Bool(flgMustStaple),
-			NotBefore:                      getTime(ctx, flgNotBefore),
-			NotAfter:                       getTime(ctx, flgNotAfter),
-			Bundle:                         bundle,
-			PreferredChain:                 ctx.String(flgPreferredChain),
-			Profile:                        ctx.String(flgProfile),
-			AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations),
+		request, err := newObtainRequest(ctx, domains)
+		if err != nil {
+			return nil, err
 		}
 
-		if ctx.IsSet(flgPrivateKey) {
-			var err error
-			request.PrivateKey, err = loadPrivateKey(ctx.String(flgPrivateKey))
-			if err != nil {
-				return nil, fmt.Errorf("load private key: %w", err)
-			}
+		certRes, err := client.Certificate.Obtain(request)
+		if err != nil {
+			return nil, err
 		}
 
-		return client.Certificate.Obtain(request)
+		// The certificate keeps its name, even if the first domain has been dropped (--allow-partial).
+		certRes.Domain = domains[0]
+
+		logDroppedDomains(certRes.Domain, certRes)
+
+		return certRes, nil
 	}
 
 	// read the CSR
@@ -255,3 +402,224 @@ func obtainCertificate(ctx *cli.Context, client *lego.Client) (*certificate.Reso
 
 	return client.Certificate.ObtainForCSR(request)
 }
+
+func newObtainRequest(ctx *cli.Context, domains []string) (certificate.ObtainRequest, error) {
+	request := certificate.ObtainRequest{
+		Domains:                        domains,
+		MustStaple:                     ctx.Bool(flgMustStaple),
+		NotBefore:                      getTime(ctx, flgNotBefore),
+		NotAfter:                       getTime(ctx, flgNotAfter),
+		Bundle:                         !ctx.Bool(flgNoBundle),
+		PreferredChain:                 ctx.String(flgPreferredChain),
+		Profile:                        ctx.String(flgProfile),
+		AlwaysDeactivateAuthorizations: ctx.Bool(flgAlwaysDeactivateAuthorizations),
+		AllowPartial:                   ctx.Bool(flgAllowPartial),
+	}
+
+	if ctx.IsSet(flgPrivateKey) {
+		var err error
+		request.PrivateKey, err = loadPrivateKey(ctx.String(flgPrivateKey))
+		if err != nil {
+			return request, fmt.Errorf("load private key: %w", err)
+		}
+	}
+
+	return request, nil
+}
+
+// runBatch obtains the certificates listed in the domains file.
+func runBatch(ctx *cli.Context, client *lego.Client, certsStorage *CertificatesStorage, hooks *hookRunner) error {
+	domainsList, err := readDomainsFile(ctx.String(flgDomainsFile))
+	if err != nil {
+		return fmt.Errorf("could not read the domains file: %w", err)
+	}
+
+	var requests []certificate.ObtainRequest
+
+	for _, domains := range domainsList {
+		request, err := newObtainRequest(ctx, domains)
+		if err != nil {
+			return fmt.Errorf("could not obtain certificates: %w", err)
+		}
+
+		requests = append(requests, request)
+	}
+
+	// The locks are taken in the same order by all the lego processes.
+	var names []string
+	for _, request := range requests {
+		names = append(names, request.Domains[0])
+	}
+
+	for _, name := range slices.Compact(slices.Sorted(slices.Values(names))) {
+		defer certsStorage.Lock(name)()
+	}
+
+	for _, request := range requests {
+		err = hooks.pre(request.Domains[0], request.Domains, nil, nil)
+		if err != nil {
+			return fmt.Errorf("[%s] pre hook: %w", request.Domains[0], err)
+		}
+	}
+
+	results := client.Certificate.ObtainBatch(requests, &certificate.BatchOptions{Workers: getBatchWorkers(ctx)})
+
+	var failures int
+
+	for _, result := range results {
+		if result.Err != nil {
+			log.Warnf("[%s] Could not obtain the certificate: %v", result.Request.Domains[0], result.Err)
+
+			hooks.failure(result.Request.Domains[0], result.Request.Domains, nil, result.Err)
+
+			failures++
+
+			continue
+		}
+
+		// The certificate keeps its name, even if the first domain has been dropped (--allow-partial).
+		result.Resource.Domain = result.Request.Domains[0]
+
+		logDroppedDomains(result.Resource.Domain, result.Resource)
+
+		certsStorage.SaveResource(result.Resource)
+
+		err = deploy(ctx, certsStorage, result.Resource)
+		if err != nil {
+			return err
+		}
+
+		err = hooks.post(result.Resource, certsStorage)
+		if err != nil {
+			return err
+		}
+	}
+
+	if failures > 0 {
+		// Make sure to return a non-zero exit code if at least one certificate has not been obtained.
+		return fmt.Errorf("could not obtain %d of the %d certificates", failures, len(results))
+	}
+
+	return nil
+}
+
+// runSplit obtains a certificate split into several certificates.
+func runSplit(ctx *cli.Context, client *lego.Client, certsStorage *CertificatesStorage, hooks *hookRunner) error {
+	request, err := newObtainRequest(ctx, ctx.StringSlice(flgDomains))
+	if err != nil {
+		return fmt.Errorf("could not obtain certificates: %w", err)
+	}
+
+	maxIdentifiers := ctx.Int(flgMaxIdentifiers)
+
+	// The other lego processes wait for the certificates of the group.
+	defer certsStorage.Lock(request.Domains[0])()
+
+	err = hooks.pre(request.Domains[0], request.Domains, nil, nil)
+	if err != nil {
+		return fmt.Errorf("[%s] pre hook: %w", request.Domains[0], err)
+	}
+
+	resources, err := client.Certificate.ObtainSplit(request, &certificate.SplitOptions{MaxIdentifiers: maxIdentifiers})
+	if err != nil {
+		hooks.failure(request.Domains[0], request.Domains, nil, err)
+
+		// The parts already obtained are kept.
+		if len(resources) > 0 {
+			certsStorage.SavePartialGroup(request.Domains[0], maxIdentifiers, resources)
+		}
+
+		return fmt.Errorf("could not obtain certificates: %w", err)
+	}
+
+	for _, resource := range resources {
+		logDroppedDomains(resource.Domain, resource)
+	}
+
+	certsStorage.SaveGroup(request.Domains[0], maxIdentifiers, resources)
+
+	err = deployGroup(ctx, certsStorage, resources)
+	if err != nil {
+		return err
+	}
+
+	return hooks.postGroup(resources, certsStorage)
+}
+
+// logDroppedDomains logs the domains dropped from a partial certificate (--allow-partial).
+func logDroppedDomains(name string, certRes *certificate.Resource) {
+	if len(certRes.DroppedDomains) == 0 {
+		return
+	}
+
+	for _, domain := range slices.Sorted(maps.Keys(certRes.DroppedDomains)) {
+		log.Warnf("[%s] The domain %s has been dropped from the certificate: %v", name, domain, certRes.DroppedDomains[domain])
+	}
+}
+
+// checkMaxIdentifiers checks that the split of the domains is possible.
+func checkMaxIdentifiers(ctx *cli.Context, hasDomains bool) {
+	if ctx.Int(flgMaxIdentifiers) <= 0 {
+		return
+	}
+
+	if !hasDomains {
+		log.Fatalf("--%s only works with --%s/-d", flgMaxIdentifiers, flgDomains)
+	}
+
+	if ctx.IsSet(flgFilename) {
+		log.Fatalf("--%s cannot be used with --%s: the parts of the certificate need different filenames", flgMaxIdentifiers, flgFilename)
+	}
+}
+
+// getBatchWorkers returns the number of certificates obtained concurrently.
+func getBatchWorkers(ctx *cli.Context) int {
+	workers := ctx.Int(flgWorkers)
+
+	standaloneHTTP := ctx.Bool(flgHTTP) && !ctx.IsSet(flgHTTPWebroot) && !ctx.IsSet(flgHTTPMemcachedHost) && !ctx.IsSet(flgHTTPS3Bucket)
+
+	if workers > 1 && (standaloneHTTP || ctx.Bool(flgTLS)) {
+		log.Infof("The built-in HTTP and TLS servers cannot solve concurrent challenges: the certificates are obtained one by one.")
+		return 1
+	}
+
+	return workers
+}
+
+// readDomainsFile reads a file listing the domains of several certificates:
+// one certificate per line, the domains are separated by commas or spaces.
+// The empty lines and the lines starting with '#' are ignored.
+func readDomainsFile(filename string) ([][]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	var domainsList [][]string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		domains := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+
+		domainsList = append(domainsList, domains)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(domainsList) == 0 {
+		return nil, fmt.Errorf("no certificates in %s", filename)
+	}
+
+	return domainsList, nil
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readDomainsFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "domains.txt")
+
+	content := `# certificates
+example.com, www.example.com
+
+example.org
+  *.example.net example.net,foo.example.net
+`
+
+	err := os.WriteFile(filename, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	domainsList, err := readDomainsFile(filename)
+	require.NoError(t, err)
+
+	expected := [][]string{
+		{"example.com", "www.example.com"},
+		{"example.org"},
+		{"*.example.net", "example.net", "foo.example.net"},
+	}
+
+	assert.Equal(t, expected, domainsList)
+}
+
+func Test_readDomainsFile_empty(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "domains.txt")
+
+	err := os.WriteFile(filename, []byte("# nothing\n\n"), 0o600)
+	require.NoError(t, err)
+
+	_, err = readDomainsFile(filename)
+	require.Error(t, err)
+}
@@ -0,0 +1,502 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Config the configuration file (TOML) describing several certificates.
+type Config struct {
+	// Path the directory used to store the data.
+	Path string `toml:"path"`
+
+	// Storage the storage of the data, instead of the directory (see Storage).
+	Storage string `toml:"storage"`
+
+	// Notify the webhooks receiving the events of the certificates.
+	Notify ConfigNotify `toml:"notify"`
+
+	Accounts map[string]ConfigAccount `toml:"accounts"`
+
+	// Defaults the default settings of the certificates.
+	Defaults ConfigCertificate `toml:"defaults"`
+
+	Certificates []ConfigCertificate `toml:"certificates"`
+}
+
+// ConfigNotify the webhooks receiving the events of the certificates.
+type ConfigNotify struct {
+	URLs          []string `toml:"urls"`
+	Secret        string   `toml:"secret"`
+	ExpiryWarning string   `toml:"expiry-warning"`
+}
+
+// ConfigAccount an account and its CA server.
+type ConfigAccount struct {
+	Email     string `toml:"email"`
+	Server    string `toml:"server"`
+	KeyType   string `toml:"key-type"`
+	AcceptTOS bool   `toml:"accept-tos"`
+
+	// External Account Binding.
+	EABKID  string `toml:"eab-kid"`
+	EABHMAC string `toml:"eab-hmac"`
+}
+
+// ConfigCertificate the settings of a certificate.
+type ConfigCertificate struct {
+	// Account the name of the account (default: the only account).
+	Account string   `toml:"account"`
+	Domains []string `toml:"domains"`
+
+	KeyType        string `toml:"key-type"`
+	Profile        string `toml:"profile"`
+	PreferredChain string `toml:"preferred-chain"`
+	MustStaple     *bool  `toml:"must-staple"`
+	NoBundle       *bool  `toml:"no-bundle"`
+	ReuseKey       *bool  `toml:"reuse-key"`
+
+	// Challenge replaces the default challenge settings.
+	Challenge *ConfigChallenge `toml:"challenge"`
+
+	// Env the environment variables, i.e. the DNS provider credentials.
+	// The variables are merged with the default variables.
+	Env map[string]string `toml:"env"`
+
+	Hooks  ConfigHooks  `toml:"hooks"`
+	Output ConfigOutput `toml:"output"`
+
+	// Deploy the deployers of the certificate: the type of the deployer and its options (see newDeployer).
+	// The deployers replace the default deployers.
+	Deploy []map[string]string `toml:"deploy"`
+}
+
+// ConfigChallenge the challenge settings.
+type ConfigChallenge struct {
+	HTTP          bool     `toml:"http"`
+	HTTPPort      string   `toml:"http-port"`
+	HTTPWebroot   string   `toml:"http-webroot"`
+	HTTPMemcached []string `toml:"http-memcached-host"`
+	HTTPS3Bucket  string   `toml:"http-s3-bucket"`
+
+	TLS     bool   `toml:"tls"`
+	TLSPort string `toml:"tls-port"`
+
+	DNS                      string   `toml:"dns"`
+	DNSResolvers             []string `toml:"dns-resolvers"`
+	DNSDisableCP             bool     `toml:"dns-disable-cp"`
+	DNSPropagationWait       string   `toml:"dns-propagation-wait"`
+	DNSPropagationRNS        bool     `toml:"dns-propagation-rns"`
+	DNSPropagationDisableANS bool     `toml:"dns-propagation-disable-ans"`
+	DNSAccount               bool     `toml:"dns-account"`
+	DNSPersist               bool     `toml:"dns-persist"`
+}
+
+// ConfigHooks the hooks of a certificate.
+type ConfigHooks struct {
+	Run     string `toml:"run"`
+	Renew   string `toml:"renew"`
+	Pre     string `toml:"pre"`
+	Failure string `toml:"failure"`
+	Skip    string `toml:"skip"`
+	Timeout string `toml:"timeout"`
+}
+
+// ConfigOutput the output formats of a certificate.
+type ConfigOutput struct {
+	PEM         *bool  `toml:"pem"`
+	PFX         *bool  `toml:"pfx"`
+	PFXPassword string `toml:"pfx-password"`
+	PFXFormat   string `toml:"pfx-format"`
+	// CertbotLayout also stores the certificate with the Certbot layout (live/archive directories).
+	CertbotLayout *bool `toml:"certbot-layout"`
+}
+
+// readConfig reads and validates a configuration file.
+func readConfig(filename string) (*Config, error) {
+	var cfg Config
+
+	md, err := toml.DecodeFile(filename, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("unknown keys: %v", undecoded)
+	}
+
+	if len(cfg.Accounts) == 0 {
+		return nil, errors.New("no accounts")
+	}
+
+	if len(cfg.Certificates) == 0 {
+		return nil, errors.New("no certificates")
+	}
+
+	err = checkNotifyURLs(cfg.Notify.URLs)
+	if err != nil {
+		return nil, fmt.Errorf("notify: %w", err)
+	}
+
+	names := map[string]struct{}{}
+
+	for i, cert := range cfg.Certificates {
+		if len(cert.Domains) == 0 {
+			return nil, fmt.Errorf("certificates[%d]: no domains", i)
+		}
+
+		if _, ok := names[cert.Domains[0]]; ok {
+			return nil, fmt.Errorf("certificates[%d]: duplicated certificate %s", i, cert.Domains[0])
+		}
+
+		names[cert.Domains[0]] = struct{}{}
+
+		_, err = cfg.account(cert)
+		if err != nil {
+			return nil, fmt.Errorf("certificates[%d] (%s): %w", i, cert.Domains[0], err)
+		}
+
+		specs, err := deploySpecs(cfg.deploy(cert))
+		if err == nil {
+			_, err = newDeployers(specs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("certificates[%d] (%s): deploy: %w", i, cert.Domains[0], err)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// account returns the account of a certificate.
+func (c *Config) account(cert ConfigCertificate) (ConfigAccount, error) {
+	name := cmp.Or(cert.Account, c.Defaults.Account)
+
+	if name == "" {
+		if len(c.Accounts) > 1 {
+			return ConfigAccount{}, errors.New("the account must be defined when there are several accounts")
+		}
+
+		for _, account := range c.Accounts {
+			return account, nil
+		}
+	}
+
+	account, ok := c.Accounts[name]
+	if !ok {
+		return ConfigAccount{}, fmt.Errorf("unknown account %q", name)
+	}
+
+	return account, nil
+}
+
+// flagValues returns the values of the flags which describe a certificate, indexed by flag name.
+func (c *Config) flagValues(cert ConfigCertificate) map[string][]string {
+	// The errors have been checked by readConfig.
+	account, _ := c.account(cert)
+
+	values := map[string][]string{
+		flgDomains: cert.Domains,
+	}
+
+	setString(values, flgPath, c.Path)
+	setString(values, flgStorage, c.Storage)
+
+	setStrings(values, flgNotifyURL, c.Notify.URLs)
+	setString(values, flgNotifySecret, c.Notify.Secret)
+	setString(values, flgNotifyExpiryWarning, c.Notify.ExpiryWarning)
+
+	setString(values, flgEmail, account.Email)
+	setString(values, flgServer, account.Server)
+	setBool(values, flgAcceptTOS, account.AcceptTOS)
+
+	if account.EABKID != "" {
+		setBool(values, flgEAB, true)
+		setString(values, flgKID, account.EABKID)
+		setString(values, flgHMAC, account.EABHMAC)
+	}
+
+	defaults := c.Defaults
+
+	setString(values, flgKeyType, cmp.Or(cert.KeyType, defaults.KeyType, account.KeyType))
+	setString(values, flgProfile, cmp.Or(cert.Profile, defaults.Profile))
+	setString(values, flgPreferredChain, cmp.Or(cert.PreferredChain, defaults.PreferredChain))
+	setBoolPtr(values, flgMustStaple, cert.MustStaple, defaults.MustStaple)
+	setBoolPtr(values, flgNoBundle, cert.NoBundle, defaults.NoBundle)
+	setBoolPtr(values, flgReuseKey, cert.ReuseKey, defaults.ReuseKey)
+
+	challenge := cert.Challenge
+	if challenge == nil {
+		challenge = defaults.Challenge
+	}
+
+	if challenge != nil {
+		setBool(values, flgHTTP, challenge.HTTP)
+		setString(values, flgHTTPPort, challenge.HTTPPort)
+		setString(values, flgHTTPWebroot, challenge.HTTPWebroot)
+		setStrings(values, flgHTTPMemcachedHost, challenge.HTTPMemcached)
+		setString(values, flgHTTPS3Bucket, challenge.HTTPS3Bucket)
+		setBool(values, flgTLS, challenge.TLS)
+		setString(values, flgTLSPort, challenge.TLSPort)
+		setString(values, flgDNS, challenge.DNS)
+		setStrings(values, flgDNSResolvers, challenge.DNSResolvers)
+		setBool(values, flgDNSDisableCP, challenge.DNSDisableCP)
+		setString(values, flgDNSPropagationWait, challenge.DNSPropagationWait)
+		setBool(values, flgDNSPropagationRNS, challenge.DNSPropagationRNS)
+		setBool(values, flgDNSPropagationDisableANS, challenge.DNSPropagationDisableANS)
+		setBool(values, flgDNSAccount, challenge.DNSAccount)
+		setBool(values, flgDNSPersist, challenge.DNSPersist)
+	}
+
+	hookTimeout := cmp.Or(cert.Hooks.Timeout, defaults.Hooks.Timeout)
+
+	setString(values, flgRunHook, cmp.Or(cert.Hooks.Run, defaults.Hooks.Run))
+	setString(values, flgRunHookTimeout, hookTimeout)
+	setString(values, flgRenewHook, cmp.Or(cert.Hooks.Renew, defaults.Hooks.Renew))
+	setString(values, flgRenewHookTimeout, hookTimeout)
+	setString(values, flgPreHook, cmp.Or(cert.Hooks.Pre, defaults.Hooks.Pre))
+	setString(values, flgFailureHook, cmp.Or(cert.Hooks.Failure, defaults.Hooks.Failure))
+	setString(values, flgSkipHook, cmp.Or(cert.Hooks.Skip, defaults.Hooks.Skip))
+
+	setBoolPtr(values, flgPEM, cert.Output.PEM, defaults.Output.PEM)
+	setBoolPtr(values, flgPFX, cert.Output.PFX, defaults.Output.PFX)
+	setString(values, flgPFXPass, cmp.Or(cert.Output.PFXPassword, defaults.Output.PFXPassword))
+	setString(values, flgPFXFormat, cmp.Or(cert.Output.PFXFormat, defaults.Output.PFXFormat))
+	setBoolPtr(values, flgCertbotLayout, cert.Output.CertbotLayout, defaults.Output.CertbotLayout)
+
+	specs, _ := deploySpecs(c.deploy(cert))
+	setStrings(values, flgDeploy, specs)
+
+	return values
+}
+
+// deploy returns the deployers of a certificate.
+func (c *Config) deploy(cert ConfigCertificate) []map[string]string {
+	if len(cert.Deploy) > 0 {
+		return cert.Deploy
+	}
+
+	return c.Defaults.Deploy
+}
+
+// deploySpecs converts the deployers of the configuration file to their descriptions (see newDeployer).
+func deploySpecs(deployers []map[string]string) ([]string, error) {
+	var specs []string
+
+	for _, deployer := range deployers {
+		fields := []string{deployer["type"]}
+
+		for _, key := range slices.Sorted(maps.Keys(deployer)) {
+			if key == "type" {
+				continue
+			}
+
+			// The values of --deploy are separated by commas.
+			if strings.Contains(deployer[key], ",") {
+				return nil, fmt.Errorf("%s: the value of %s cannot contain commas", deployer["type"], key)
+			}
+
+			fields = append(fields, key+"="+quoteDeployValue(deployer[key]))
+		}
+
+		specs = append(specs, strings.Join(fields, " "))
+	}
+
+	return specs, nil
+}
+
+// env returns the environment variables of a certificate.
+func (c *Config) env(cert ConfigCertificate) map[string]string {
+	env := maps.Clone(c.Defaults.Env)
+	if env == nil {
+		env = make(map[string]string)
+	}
+
+	maps.Copy(env, cert.Env)
+
+	return env
+}
+
+// forEachCertificate calls the action for each certificate of the configuration file.
+// The action is called with a context containing the flags which describe the certificate,
+// and with the environment variables of the certificate.
+//
+// The errors returned by the action (setup of the account and of the storage, challenges, order, deployment)
+// don't prevent the processing of the other certificates.
+// A failure to read or write the files of the storage (e.g. a certificate which cannot be saved) stops lego.
+func forEachCertificate(ctx *cli.Context, action cli.ActionFunc) error {
+	cfg, err := readConfig(ctx.String(flgConfig))
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", ctx.String(flgConfig), err)
+	}
+
+	var errs []error
+
+	for _, cert := range cfg.Certificates {
+		err := runForCertificate(ctx, cfg, cert, action)
+		if err != nil {
+			log.Warnf("[%s] %v", cert.Domains[0], err)
+
+			errs = append(errs, fmt.Errorf("[%s] %w", cert.Domains[0], err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runForCertificate calls the action with the context and the environment of a certificate of the configuration file.
+func runForCertificate(ctx *cli.Context, cfg *Config, cert ConfigCertificate, action cli.ActionFunc) error {
+	certCtx, err := newConfigContext(ctx, cfg.flagValues(cert))
+	if err != nil {
+		return err
+	}
+
+	return withEnv(cfg.env(cert), func() error {
+		err := Before(certCtx)
+		if err != nil {
+			return err
+		}
+
+		return action(certCtx)
+	})
+}
+
+// newConfigContext creates a context with the global and the command flags:
+// the flags set by the user are kept, the values override them,
+// and the other flags have their default values.
+func newConfigContext(ctx *cli.Context, values map[string][]string) (*cli.Context, error) {
+	set := flag.NewFlagSet(ctx.Command.Name, flag.ContinueOnError)
+
+	flags := slices.Concat(ctx.App.Flags, ctx.Command.Flags)
+
+	for _, f := range flags {
+		// The help flag is defined by the application and by the command.
+		if slices.ContainsFunc(f.Names(), func(name string) bool { return set.Lookup(name) != nil }) {
+			continue
+		}
+
+		err := f.Apply(set)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The values replace the values set by the user: the slices are not appended.
+	merged := userFlagValues(ctx, flags)
+	maps.Copy(merged, values)
+
+	for _, name := range slices.Sorted(maps.Keys(merged)) {
+		// The flags unknown by the command are ignored.
+		if set.Lookup(name) == nil {
+			continue
+		}
+
+		for _, value := range merged[name] {
+			err := set.Set(name, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %w", name, err)
+			}
+		}
+	}
+
+	certCtx := cli.NewContext(ctx.App, set, nil)
+	certCtx.Command = ctx.Command
+	certCtx.Context = ctx.Context
+
+	return certCtx, nil
+}
+
+// userFlagValues returns the values of the flags set by the user (command line or environment variables).
+// The configuration file itself is not kept.
+func userFlagValues(ctx *cli.Context, flags []cli.Flag) map[string][]string {
+	values := make(map[string][]string)
+
+	for _, f := range flags {
+		name := f.Names()[0]
+
+		if name == flgConfig || !ctx.IsSet(name) {
+			continue
+		}
+
+		switch fl := f.(type) {
+		case *cli.StringSliceFlag:
+			values[name] = slices.Clone(ctx.StringSlice(name))
+
+		case *cli.TimestampFlag:
+			if ts := ctx.Timestamp(name); ts != nil {
+				values[name] = []string{ts.Format(fl.Layout)}
+			}
+
+		default:
+			values[name] = []string{fmt.Sprint(ctx.Value(name))}
+		}
+	}
+
+	return values
+}
+
+// withEnv calls the function with the environment variables, and then restores the previous environment.
+func withEnv(env map[string]string, fn func() error) error {
+	previous := make(map[string]*string)
+
+	for key, value := range env {
+		if old, ok := os.LookupEnv(key); ok {
+			previous[key] = &old
+		} else {
+			previous[key] = nil
+		}
+
+		_ = os.Setenv(key, value)
+	}
+
+	defer func() {
+		for key, old := range previous {
+			if old == nil {
+				_ = os.Unsetenv(key)
+			} else {
+				_ = os.Setenv(key, *old)
+			}
+		}
+	}()
+
+	return fn()
+}
+
+func setString(values map[string][]string, name, value string) {
+	if value != "" {
+		values[name] = []string{value}
+	}
+}
+
+func setStrings(values map[string][]string, name string, value []string) {
+	if len(value) > 0 {
+		values[name] = value
+	}
+}
+
+func setBool(values map[string][]string, name string, value bool) {
+	if value {
+		values[name] = []string{strconv.FormatBool(value)}
+	}
+}
+
+// setBoolPtr sets the first defined value.
+func setBoolPtr(values map[string][]string, name string, candidates ...*bool) {
+	for _, value := range candidates {
+		if value != nil {
+			values[name] = []string{strconv.FormatBool(*value)}
+			return
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_readConfig(t *testing.T) {
+	cfg, err := readConfig(filepath.FromSlash("testdata/config.toml"))
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Certificates, 2)
+
+	expected := map[string][]string{
+		flgDomains:            {"example.com", "*.example.com"},
+		flgPath:               {"/var/lib/lego"},
+		flgNotifyURL:          {"https://hooks.example.com/lego"},
+		flgNotifySecret:       {"secret"},
+		flgEmail:              {"admin@example.com"},
+		flgServer:             {"https://acme-staging-v02.api.letsencrypt.org/directory"},
+		flgAcceptTOS:          {"true"},
+		flgKeyType:            {"ec384"},
+		flgProfile:            {"tlsserver"},
+		flgDNS:                {"cloudflare"},
+		flgDNSResolvers:       {"1.1.1.1:53", "8.8.8.8:53"},
+		flgDNSPropagationWait: {"30s"},
+		flgRunHook:            {"./hook.sh"},
+		flgRunHookTimeout:     {"1m"},
+		flgRenewHook:          {"./hook.sh"},
+		flgRenewHookTimeout:   {"1m"},
+		flgFailureHook:        {"./alert.sh"},
+		flgPEM:                {"true"},
+	}
+
+	assert.Equal(t, expected, cfg.flagValues(cfg.Certificates[0]))
+	assert.Equal(t, map[string]string{"CF_DNS_API_TOKEN": "token", "CF_ZONE_API_TOKEN": "zone"}, cfg.env(cfg.Certificates[0]))
+
+	expected = map[string][]string{
+		flgDomains:          {"shop.example.org"},
+		flgPath:             {"/var/lib/lego"},
+		flgNotifyURL:        {"https://hooks.example.com/lego"},
+		flgNotifySecret:     {"secret"},
+		flgEmail:            {"other@example.com"},
+		flgServer:           {"https://ca.example.org/directory"},
+		flgEAB:              {"true"},
+		flgKID:              {"kid"},
+		flgHMAC:             {"hmac"},
+		flgKeyType:          {"rsa2048"},
+		flgProfile:          {"tlsserver"},
+		flgMustStaple:       {"true"},
+		flgHTTP:             {"true"},
+		flgHTTPWebroot:      {"/var/www"},
+		flgRunHook:          {"./hook.sh"},
+		flgRunHookTimeout:   {"1m"},
+		flgRenewHook:        {"./hook.sh"},
+		flgRenewHookTimeout: {"1m"},
+		flgFailureHook:      {"./alert.sh"},
+		flgPEM:              {"false"},
+		flgPFX:              {"true"},
+		flgDeploy:           {"copy dest=/etc/ssl/{name}.key mode=0640 src=key", "signal pidfile=/run/nginx.pid"},
+	}
+
+	assert.Equal(t, expected, cfg.flagValues(cfg.Certificates[1]))
+	assert.Equal(t, map[string]string{"CF_DNS_API_TOKEN": "other", "CF_ZONE_API_TOKEN": "zone"}, cfg.env(cfg.Certificates[1]))
+}
+
+func Test_deploySpecs(t *testing.T) {
+	specs, err := deploySpecs([]map[string]string{
+		{"type": "copy", "src": "key", "dest": "/etc/my ssl/{name}.key"},
+		{"type": "keystore", "dest": "/opt/app/keystore.jks", "password": `my "secret"`},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`copy dest="/etc/my ssl/{name}.key" src=key`,
+		`keystore dest=/opt/app/keystore.jks password="my \"secret\""`,
+	}, specs)
+
+	deployers, err := newDeployers(specs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "copy key to /etc/my ssl/{name}.key", deployers[0].String())
+	assert.Equal(t, `my "secret"`, deployers[1].(*keyStoreDeployer).password.value)
+
+	_, err = deploySpecs([]map[string]string{{"type": "copy", "src": "key", "dest": "/tmp/a,b"}})
+	require.EqualError(t, err, "copy: the value of dest cannot contain commas")
+}
+
+func Test_readConfig_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "unknown key",
+			content:  "foo = 1\n[accounts.a]\n[[certificates]]\ndomains = [\"example.com\"]\n",
+			expected: "unknown keys: [foo]",
+		},
+		{
+			desc:     "no accounts",
+			content:  "[[certificates]]\ndomains = [\"example.com\"]\n",
+			expected: "no accounts",
+		},
+		{
+			desc:     "no certificates",
+			content:  "[accounts.a]\n",
+			expected: "no certificates",
+		},
+		{
+			desc:     "no domains",
+			content:  "[accounts.a]\n[[certificates]]\n",
+			expected: "certificates[0]: no domains",
+		},
+		{
+			desc:     "duplicated certificate",
+			content:  "[accounts.a]\n[[certificates]]\ndomains = [\"example.com\"]\n[[certificates]]\ndomains = [\"example.com\"]\n",
+			expected: "certificates[1]: duplicated certificate example.com",
+		},
+		{
+			desc:     "unknown account",
+			content:  "[accounts.a]\n[[certificates]]\naccount = \"b\"\ndomains = [\"example.com\"]\n",
+			expected: `certificates[0] (example.com): unknown account "b"`,
+		},
+		{
+			desc:     "ambiguous account",
+			content:  "[accounts.a]\n[accounts.b]\n[[certificates]]\ndomains = [\"example.com\"]\n",
+			expected: "certificates[0] (example.com): the account must be defined when there are several accounts",
+		},
+		{
+			desc:     "invalid deployer",
+			content:  "[accounts.a]\n[[certificates]]\ndomains = [\"example.com\"]\n[[certificates.deploy]]\ntype = \"copy\"\nsrc = \"foo\"\ndest = \"/tmp/foo\"\n",
+			expected: `certificates[0] (example.com): deploy: copy: unknown file "foo", supported: cert, issuer, key, pem, pfx, json`,
+		},
+		{
+			desc:     "invalid notify URL",
+			content:  "[notify]\nurls = [\"ftp://example.com\"]\n[accounts.a]\n[[certificates]]\ndomains = [\"example.com\"]\n",
+			expected: "notify: ftp://example.com: only the HTTP and HTTPS URLs are supported",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			filename := filepath.Join(t.TempDir(), "config.toml")
+
+			err := os.WriteFile(filename, []byte(test.content), 0o600)
+			require.NoError(t, err)
+
+			_, err = readConfig(filename)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func Test_forEachCertificate(t *testing.T) {
+	dir := t.TempDir()
+
+	content := fmt.Sprintf(`path = %q
+
+[accounts.main]
+email = "admin@example.com"
+
+[defaults.env]
+LEGO_TEST_CONFIG_TOKEN = "default"
+
+[[certificates]]
+domains = ["example.com", "www.example.com"]
+
+[[certificates]]
+domains = ["example.org"]
+must-staple = true
+
+[certificates.env]
+LEGO_TEST_CONFIG_TOKEN = "example.org"
+`, dir)
+
+	filename := filepath.Join(dir, "config.toml")
+
+	err := os.WriteFile(filename, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	var calls []string
+
+	action := func(ctx *cli.Context) error {
+		calls = append(calls, fmt.Sprintf("%v %s %s %t %s %s %s %t",
+			ctx.StringSlice(flgDomains), ctx.String(flgEmail), ctx.String(flgPath), ctx.Bool(flgMustStaple), os.Getenv("LEGO_TEST_CONFIG_TOKEN"),
+			ctx.String(flgKeyType), ctx.Timestamp(flgNotAfter).Format(time.RFC3339), ctx.IsSet(flgConfig)))
+
+		return nil
+	}
+
+	app := cli.NewApp()
+	app.Flags = CreateFlags("")
+	app.Commands = []*cli.Command{{
+		Name:   "test",
+		Flags:  createRun().Flags,
+		Action: func(ctx *cli.Context) error { return forEachCertificate(ctx, action) },
+	}}
+
+	// The flags set by the user are kept, except the flags defined by the configuration file.
+	err = app.Run([]string{
+		"lego", "--" + flgConfig, filename, "--" + flgKeyType, "rsa4096", "--" + flgDomains, "example.net",
+		"test", "--" + flgNotAfter, "2026-01-01T00:00:00Z",
+	})
+	require.NoError(t, err)
+
+	expected := []string{
+		fmt.Sprintf("[example.com www.example.com] admin@example.com %s false default rsa4096 2026-01-01T00:00:00Z false", dir),
+		fmt.Sprintf("[example.org] admin@example.com %s true example.org rsa4096 2026-01-01T00:00:00Z false", dir),
+	}
+
+	assert.Equal(t, expected, calls)
+
+	_, ok := os.LookupEnv("LEGO_TEST_CONFIG_TOKEN")
+	assert.False(t, ok)
+}
+
+func Test_forEachCertificate_errors(t *testing.T) {
+	dir := t.TempDir()
+
+	content := fmt.Sprintf(`path = %q
+
+[accounts.main]
+email = "admin@example.com"
+
+[[certificates]]
+domains = ["example.com"]
+
+[[certificates]]
+domains = ["example.org"]
+
+[[certificates]]
+domains = ["example.net"]
+`, dir)
+
+	filename := filepath.Join(dir, "config.toml")
+
+	err := os.WriteFile(filename, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	var calls []string
+
+	action := func(ctx *cli.Context) error {
+		domain := ctx.StringSlice(flgDomains)[0]
+
+		calls = append(calls, domain)
+
+		if domain != "example.org" {
+			return errors.New("failure")
+		}
+
+		return nil
+	}
+
+	app := cli.NewApp()
+	app.Flags = CreateFlags("")
+	app.Commands = []*cli.Command{{
+		Name:   "test",
+		Flags:  createRun().Flags,
+		Action: func(ctx *cli.Context) error { return forEachCertificate(ctx, action) },
+	}}
+
+	err = app.Run([]string{"lego", "--" + flgConfig, filename, "test"})
+	require.EqualError(t, err, "[example.com] failure\n[example.net] failure")
+
+	// The failure of a certificate doesn't prevent the processing of the other certificates.
+	assert.Equal(t, []string{"example.com", "example.org", "example.net"}, calls)
+}
+
+func Test_forEachCertificate_renewSetupErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	content := fmt.Sprintf(`path = %q
+
+[accounts.main]
+email = "admin@example.com"
+
+[[certificates]]
+domains = ["example.com"]
+key-type = "rsa1024"
+
+[[certificates]]
+domains = ["example.org"]
+`, dir)
+
+	filename := filepath.Join(dir, "config.toml")
+
+	err := os.WriteFile(filename, []byte(content), 0o600)
+	require.NoError(t, err)
+
+	app := cli.NewApp()
+	app.Flags = CreateFlags("")
+	app.Commands = []*cli.Command{createRenew()}
+
+	// The setup failures of a certificate are reported, the other certificates are processed.
+	err = app.Run([]string{"lego", "--" + flgConfig, filename, "renew"})
+	require.EqualError(t, err, "[example.com] unsupported key type: rsa1024\n"+
+		"[example.org] account admin@example.com is not registered: use 'run' to register a new account")
+}
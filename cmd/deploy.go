@@ -0,0 +1,578 @@
+package cmd
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Deployer types.
+const (
+	deployCopy     = "copy"
+	deployBundle   = "bundle"
+	deploySignal   = "signal"
+	deployKeyStore = "keystore"
+)
+
+// deployNamePlaceholder the placeholder of the certificate name in the destination paths.
+const deployNamePlaceholder = "{name}"
+
+// deployFiles the files of a certificate which can be deployed, indexed by name.
+var deployFiles = map[string]string{
+	"cert":   certExt,
+	"issuer": issuerExt,
+	"key":    keyExt,
+	"pem":    pemExt,
+	"pfx":    pfxExt,
+	"json":   resourceExt,
+}
+
+// Deployer deploys a certificate to a TLS consumer (web server, proxy, Java application, ...).
+type Deployer interface {
+	fmt.Stringer
+
+	Deploy(certsStorage *CertificatesStorage, certRes *certificate.Resource) error
+}
+
+// deploy runs the deployers of a certificate (--deploy), and reports the result of each deployer.
+// All the deployers are run, even if one of them fails.
+func deploy(ctx *cli.Context, certsStorage *CertificatesStorage, certRes *certificate.Resource) error {
+	deployers, err := newDeployers(ctx.StringSlice(flgDeploy))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, deployer := range deployers {
+		err = deployer.Deploy(certsStorage, certRes)
+		if err != nil {
+			log.Warnf("[%s] Deployment failed: %s: %v", certRes.Domain, deployer, err)
+
+			errs = append(errs, fmt.Errorf("%s: %w", deployer, err))
+
+			continue
+		}
+
+		log.Infof("[%s] Deployed: %s", certRes.Domain, deployer)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("[%s] %d of the %d deployments failed: %w", certRes.Domain, len(errs), len(deployers), errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// deployGroup runs the deployers of each part of a split certificate.
+func deployGroup(ctx *cli.Context, certsStorage *CertificatesStorage, resources []*certificate.Resource) error {
+	var errs []error
+
+	for _, resource := range resources {
+		err := deploy(ctx, certsStorage, resource)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func newDeployers(specs []string) ([]Deployer, error) {
+	var deployers []Deployer
+
+	for _, spec := range specs {
+		deployer, err := newDeployer(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		deployers = append(deployers, deployer)
+	}
+
+	return deployers, nil
+}
+
+// newDeployer creates a deployer from its description:
+// the type of the deployer followed by its options (key=value), separated by spaces.
+// A value containing spaces is quoted (single or double quotes).
+//
+//	copy src=key dest=/etc/nginx/ssl/{name}.key owner=root group=nginx mode=0640
+//	bundle parts=cert+key dest="/etc/haproxy/my certs/{name}.pem"
+//	signal pidfile=/run/nginx.pid signal=HUP
+//	keystore format=jks dest=/opt/app/keystore.jks password-file=/etc/app/keystore.pass alias=app
+func newDeployer(spec string) (Deployer, error) {
+	fields, err := splitQuoted(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 0 {
+		return nil, errors.New("empty deployer")
+	}
+
+	options := make(map[string]string)
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s: invalid option %q: the options are key=value", fields[0], field)
+		}
+
+		options[key] = value
+	}
+
+	var deployer Deployer
+
+	switch fields[0] {
+	case deployCopy:
+		deployer, err = newFileDeployer(deployCopy, options)
+	case deployBundle:
+		deployer, err = newFileDeployer(deployBundle, options)
+	case deploySignal:
+		deployer, err = newSignalDeployer(options)
+	case deployKeyStore:
+		deployer, err = newKeyStoreDeployer(options)
+	default:
+		return nil, fmt.Errorf("unknown deployer %q", fields[0])
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fields[0], err)
+	}
+
+	return deployer, nil
+}
+
+// quoteDeployValue quotes a value of a deployer option if needed (see splitQuoted).
+func quoteDeployValue(value string) string {
+	if value != "" && !strings.ContainsFunc(value, func(r rune) bool { return unicode.IsSpace(r) || r == '"' || r == '\'' }) {
+		return value
+	}
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// fileDeployer writes the concatenation of files of the certificate to a path:
+// a single file (copy), or several files (bundle, e.g. the certificate followed by the private key).
+type fileDeployer struct {
+	kind  string
+	parts []string
+	dest  string
+	perms filePermissions
+}
+
+func newFileDeployer(kind string, options map[string]string) (*fileDeployer, error) {
+	source := "parts"
+	if kind == deployCopy {
+		source = "src"
+	}
+
+	err := checkDeployOptions(options, source, "dest", "owner", "group", "mode")
+	if err != nil {
+		return nil, err
+	}
+
+	if options[source] == "" || options["dest"] == "" {
+		return nil, fmt.Errorf("the options %s and dest are required", source)
+	}
+
+	parts := []string{options[source]}
+	if kind == deployBundle {
+		parts = strings.Split(options[source], "+")
+	}
+
+	for _, part := range parts {
+		if _, ok := deployFiles[part]; !ok {
+			return nil, fmt.Errorf("unknown file %q, supported: cert, issuer, key, pem, pfx, json", part)
+		}
+	}
+
+	perms, err := newFilePermissions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileDeployer{kind: kind, parts: parts, dest: options["dest"], perms: perms}, nil
+}
+
+func (d *fileDeployer) Deploy(certsStorage *CertificatesStorage, certRes *certificate.Resource) error {
+	var data []byte
+
+	for _, part := range d.parts {
+		content, err := certsStorage.readSavedFile(certRes.Domain, deployFiles[part])
+		if err != nil {
+			return err
+		}
+
+		data = append(data, content...)
+	}
+
+	return d.perms.writeFile(deployPath(d.dest, certRes), data)
+}
+
+func (d *fileDeployer) String() string {
+	return fmt.Sprintf("%s %s to %s", d.kind, strings.Join(d.parts, "+"), d.dest)
+}
+
+// signalDeployer sends a signal to a process, identified by its PID file (e.g. to reload a web server).
+type signalDeployer struct {
+	pidFile string
+	name    string
+	signal  os.Signal
+}
+
+func newSignalDeployer(options map[string]string) (*signalDeployer, error) {
+	err := checkDeployOptions(options, "pidfile", "signal")
+	if err != nil {
+		return nil, err
+	}
+
+	if options["pidfile"] == "" {
+		return nil, errors.New("the option pidfile is required")
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(options["signal"]), "SIG")
+	if name == "" {
+		name = "HUP"
+	}
+
+	signal, err := parseSignal(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signalDeployer{pidFile: options["pidfile"], name: name, signal: signal}, nil
+}
+
+func (d *signalDeployer) Deploy(_ *CertificatesStorage, _ *certificate.Resource) error {
+	data, err := os.ReadFile(d.pidFile)
+	if err != nil {
+		return err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return fmt.Errorf("invalid PID file %s: %w", d.pidFile, err)
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+
+	return process.Signal(d.signal)
+}
+
+func (d *signalDeployer) String() string {
+	return fmt.Sprintf("signal %s to %s", d.name, d.pidFile)
+}
+
+// keyStoreDeployer writes the private key and the certificate chain to a Java KeyStore (JKS) or a PKCS#12 file.
+type keyStoreDeployer struct {
+	format    string
+	dest      string
+	password  keyStorePassword
+	alias     string
+	pfxFormat string
+	perms     filePermissions
+}
+
+func newKeyStoreDeployer(options map[string]string) (*keyStoreDeployer, error) {
+	err := checkDeployOptions(options, "format", "dest", "password", "password-env", "password-file", "alias", "pfx-format", "owner", "group", "mode")
+	if err != nil {
+		return nil, err
+	}
+
+	if options["dest"] == "" {
+		return nil, errors.New("the option dest is required")
+	}
+
+	password, err := newKeyStorePassword(options)
+	if err != nil {
+		return nil, err
+	}
+
+	format := options["format"]
+	if format == "" {
+		format = "jks"
+	}
+
+	if format != "jks" && format != "pkcs12" {
+		return nil, fmt.Errorf("unknown format %q, supported: jks, pkcs12", format)
+	}
+
+	pfxFormat := options["pfx-format"]
+	if pfxFormat == "" {
+		pfxFormat = "SHA256"
+	}
+
+	_, err = getPFXEncoder(pfxFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	perms, err := newFilePermissions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyStoreDeployer{
+		format:    format,
+		dest:      options["dest"],
+		password:  password,
+		alias:     options["alias"],
+		pfxFormat: pfxFormat,
+		perms:     perms,
+	}, nil
+}
+
+func (d *keyStoreDeployer) Deploy(_ *CertificatesStorage, certRes *certificate.Resource) error {
+	if certRes.PrivateKey == nil {
+		return errors.New("no private key: the certificate has been obtained with a CSR")
+	}
+
+	password, err := d.password.read()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+
+	switch d.format {
+	case "pkcs12":
+		data, err = encodePKCS12(certRes.Domain, certRes, d.pfxFormat, password)
+
+	default:
+		data, err = d.encodeJKS(certRes, password)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return d.perms.writeFile(deployPath(d.dest, certRes), data)
+}
+
+func (d *keyStoreDeployer) encodeJKS(certRes *certificate.Resource, password string) ([]byte, error) {
+	privateKey, err := certcrypto.ParsePEMPrivateKey(certRes.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := getFullChain(certRes)
+	if err != nil {
+		return nil, err
+	}
+
+	alias := d.alias
+	if alias == "" {
+		alias = strings.ToLower(sanitizedDomain(certRes.Domain))
+	}
+
+	return encodeJKS(privateKey, chain, alias, password, time.Now())
+}
+
+func (d *keyStoreDeployer) String() string {
+	return fmt.Sprintf("keystore %s to %s", d.format, d.dest)
+}
+
+// keyStorePassword the password of a keystore: the password itself (password),
+// or the name of the environment variable (password-env) or the path of the file (password-file) containing it.
+// The environment variable and the file are read at each deployment.
+type keyStorePassword struct {
+	value string
+	env   string
+	file  string
+}
+
+func newKeyStorePassword(options map[string]string) (keyStorePassword, error) {
+	password := keyStorePassword{value: options["password"], env: options["password-env"], file: options["password-file"]}
+
+	var count int
+
+	for _, source := range []string{password.value, password.env, password.file} {
+		if source != "" {
+			count++
+		}
+	}
+
+	if count != 1 {
+		return password, errors.New("one of the options password, password-env, or password-file is required")
+	}
+
+	return password, nil
+}
+
+// read returns the password.
+// The trailing line break of the file is removed.
+func (p keyStorePassword) read() (string, error) {
+	switch {
+	case p.env != "":
+		value, ok := os.LookupEnv(p.env)
+		if !ok || value == "" {
+			return "", fmt.Errorf("the environment variable %s of the password is not set", p.env)
+		}
+
+		return value, nil
+
+	case p.file != "":
+		data, err := os.ReadFile(p.file)
+		if err != nil {
+			return "", fmt.Errorf("password file: %w", err)
+		}
+
+		value := strings.TrimRight(string(data), "\r\n")
+		if value == "" {
+			return "", fmt.Errorf("the password file %s is empty", p.file)
+		}
+
+		return value, nil
+
+	default:
+		return p.value, nil
+	}
+}
+
+// filePermissions the mode and the owner of a deployed file.
+type filePermissions struct {
+	mode os.FileMode
+	uid  int
+	gid  int
+}
+
+func newFilePermissions(options map[string]string) (filePermissions, error) {
+	perms := filePermissions{mode: filePerm, uid: -1, gid: -1}
+
+	if options["mode"] != "" {
+		mode, err := strconv.ParseUint(options["mode"], 8, 32)
+		if err != nil {
+			return perms, fmt.Errorf("invalid mode %q: %w", options["mode"], err)
+		}
+
+		perms.mode = os.FileMode(mode)
+	}
+
+	var err error
+
+	perms.uid, err = lookupID(options["owner"], func(name string) (string, error) {
+		u, errL := user.Lookup(name)
+		if errL != nil {
+			return "", errL
+		}
+
+		return u.Uid, nil
+	})
+	if err != nil {
+		return perms, fmt.Errorf("owner: %w", err)
+	}
+
+	perms.gid, err = lookupID(options["group"], func(name string) (string, error) {
+		g, errL := user.LookupGroup(name)
+		if errL != nil {
+			return "", errL
+		}
+
+		return g.Gid, nil
+	})
+	if err != nil {
+		return perms, fmt.Errorf("group: %w", err)
+	}
+
+	return perms, nil
+}
+
+// writeFile writes a file atomically (temporary file renamed), with the mode and the owner.
+func (p filePermissions) writeFile(name string, data []byte) error {
+	temp, err := writeTempFile(name, data)
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(temp, p.mode)
+
+	if err == nil && (p.uid >= 0 || p.gid >= 0) {
+		err = os.Chown(temp, p.uid, p.gid)
+	}
+
+	if err == nil {
+		err = os.Rename(temp, name)
+	}
+
+	if err != nil {
+		_ = os.Remove(temp)
+		return err
+	}
+
+	return nil
+}
+
+// lookupID returns the numeric ID of a user or a group, -1 if the name is empty.
+func lookupID(name string, lookup func(name string) (string, error)) (int, error) {
+	if name == "" {
+		return -1, nil
+	}
+
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+
+	rawID, err := lookup(name)
+	if err != nil {
+		return -1, err
+	}
+
+	id, err := strconv.Atoi(rawID)
+	if err != nil {
+		return -1, fmt.Errorf("%s: unsupported ID %q", name, rawID)
+	}
+
+	return id, nil
+}
+
+func checkDeployOptions(options map[string]string, known ...string) error {
+	for key := range options {
+		if !slices.Contains(known, key) {
+			return fmt.Errorf("unknown option %q, supported: %s", key, strings.Join(known, ", "))
+		}
+	}
+
+	return nil
+}
+
+// deployPath replaces the placeholder of the certificate name in a path.
+func deployPath(dest string, certRes *certificate.Resource) string {
+	return strings.ReplaceAll(dest, deployNamePlaceholder, sanitizedDomain(certRes.Domain))
+}
+
+// getFullChain returns the certificate followed by the issuer certificates.
+func getFullChain(certRes *certificate.Resource) ([]*x509.Certificate, error) {
+	certificates, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	// The certificate is not a bundle (--no-bundle).
+	if len(certificates) == 1 && len(certRes.IssuerCertificate) > 0 {
+		issuers, err := certcrypto.ParsePEMBundle(certRes.IssuerCertificate)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, issuers...)
+	}
+
+	return certificates, nil
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func Test_newDeployer(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		spec     string
+		expected string
+	}{
+		{
+			desc:     "copy",
+			spec:     "copy src=key dest=/etc/ssl/{name}.key owner=0 group=0 mode=0640",
+			expected: "copy key to /etc/ssl/{name}.key",
+		},
+		{
+			desc:     "bundle",
+			spec:     "bundle parts=cert+issuer+key dest=/etc/haproxy/{name}.pem",
+			expected: "bundle cert+issuer+key to /etc/haproxy/{name}.pem",
+		},
+		{
+			desc:     "keystore",
+			spec:     "keystore dest=/opt/app/keystore.jks password=secret",
+			expected: "keystore jks to /opt/app/keystore.jks",
+		},
+		{
+			desc:     "quoted values",
+			spec:     `copy src=key dest="/etc/my ssl/{name}.key" mode='0640'`,
+			expected: "copy key to /etc/my ssl/{name}.key",
+		},
+		{
+			desc:     "keystore password file",
+			spec:     "keystore dest=/opt/app/keystore.jks password-file=/etc/app/keystore.pass",
+			expected: "keystore jks to /opt/app/keystore.jks",
+		},
+		{
+			desc:     "keystore pkcs12",
+			spec:     "keystore format=pkcs12 dest=/opt/app/keystore.p12 password=secret pfx-format=DES",
+			expected: "keystore pkcs12 to /opt/app/keystore.p12",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			deployer, err := newDeployer(test.spec)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, deployer.String())
+		})
+	}
+}
+
+func Test_newDeployer_errors(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		spec     string
+		expected string
+	}{
+		{
+			desc:     "empty",
+			spec:     " ",
+			expected: "empty deployer",
+		},
+		{
+			desc:     "unknown deployer",
+			spec:     "ftp dest=/tmp",
+			expected: `unknown deployer "ftp"`,
+		},
+		{
+			desc:     "invalid option",
+			spec:     "copy src",
+			expected: `copy: invalid option "src": the options are key=value`,
+		},
+		{
+			desc:     "unknown option",
+			spec:     "copy src=key dest=/tmp/key foo=bar",
+			expected: `copy: unknown option "foo", supported: src, dest, owner, group, mode`,
+		},
+		{
+			desc:     "missing destination",
+			spec:     "bundle parts=cert+key",
+			expected: "bundle: the options parts and dest are required",
+		},
+		{
+			desc:     "unknown file",
+			spec:     "bundle parts=cert+foo dest=/tmp/bundle.pem",
+			expected: `bundle: unknown file "foo", supported: cert, issuer, key, pem, pfx, json`,
+		},
+		{
+			desc:     "invalid mode",
+			spec:     "copy src=key dest=/tmp/key mode=0999",
+			expected: `copy: invalid mode "0999": strconv.ParseUint: parsing "0999": invalid syntax`,
+		},
+		{
+			desc:     "missing PID file",
+			spec:     "signal signal=HUP",
+			expected: "signal: the option pidfile is required",
+		},
+		{
+			desc:     "unknown keystore format",
+			spec:     "keystore format=pem dest=/tmp/keystore password=secret",
+			expected: `keystore: unknown format "pem", supported: jks, pkcs12`,
+		},
+		{
+			desc:     "missing keystore password",
+			spec:     "keystore dest=/tmp/keystore.jks",
+			expected: "keystore: one of the options password, password-env, or password-file is required",
+		},
+		{
+			desc:     "several keystore passwords",
+			spec:     "keystore dest=/tmp/keystore.jks password=secret password-env=KEYSTORE_PASSWORD",
+			expected: "keystore: one of the options password, password-env, or password-file is required",
+		},
+		{
+			desc:     "unterminated quote",
+			spec:     `copy src=key dest="/tmp/my key`,
+			expected: `unterminated quote (") in "copy src=key dest=\"/tmp/my key"`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := newDeployer(test.spec)
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func Test_quoteDeployValue(t *testing.T) {
+	for _, value := range []string{"/tmp/key", "/tmp/my key", `C:\my certs\"key"`, "it's", ""} {
+		fields, err := splitQuoted("copy dest=" + quoteDeployValue(value))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"copy", "dest=" + value}, fields)
+	}
+}
+
+func TestFileDeployer_Deploy(t *testing.T) {
+	storage := &CertificatesStorage{rootPath: t.TempDir()}
+
+	certPEM, keyPEM := generateTestCertificate(t, "*.example.com")
+	issuerPEM, _ := generateTestCertificate(t, "ca.example.com")
+
+	certRes := &certificate.Resource{
+		Domain:            "*.example.com",
+		Certificate:       certPEM,
+		IssuerCertificate: issuerPEM,
+		PrivateKey:        keyPEM,
+	}
+
+	storage.SaveResource(certRes)
+
+	dest := t.TempDir()
+
+	deployers, err := newDeployers([]string{
+		"copy src=key dest=" + filepath.Join(dest, "{name}.key") + " mode=0640",
+		"bundle parts=cert+issuer+key dest=" + filepath.Join(dest, "bundle", "{name}.pem"),
+	})
+	require.NoError(t, err)
+
+	for _, deployer := range deployers {
+		require.NoError(t, deployer.Deploy(storage, certRes))
+	}
+
+	assertFileContent(t, filepath.Join(dest, "_.example.com.key"), keyPEM)
+	assertFileContent(t, filepath.Join(dest, "bundle", "_.example.com.pem"), slices.Concat(certPEM, issuerPEM, keyPEM))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dest, "_.example.com.key"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+		info, err = os.Stat(filepath.Join(dest, "bundle", "_.example.com.pem"))
+		require.NoError(t, err)
+		assert.Equal(t, filePerm, info.Mode().Perm())
+	}
+}
+
+func TestFileDeployer_Deploy_filename(t *testing.T) {
+	storage := &CertificatesStorage{rootPath: t.TempDir(), filename: "site"}
+
+	certPEM, keyPEM := generateTestCertificate(t, "example.com")
+
+	certRes := &certificate.Resource{
+		Domain:      "example.com",
+		Certificate: certPEM,
+		PrivateKey:  keyPEM,
+	}
+
+	storage.SaveResource(certRes)
+
+	dest := filepath.Join(t.TempDir(), "{name}.key")
+
+	deployer, err := newDeployer("copy src=key dest=" + quoteDeployValue(dest))
+	require.NoError(t, err)
+
+	require.NoError(t, deployer.Deploy(storage, certRes))
+
+	assertFileContent(t, filepath.Join(filepath.Dir(dest), "example.com.key"), keyPEM)
+}
+
+func TestKeyStorePassword_read(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "keystore.pass")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("from file\n"), 0o600))
+
+	t.Setenv("LEGO_TEST_KEYSTORE_PASSWORD", "from env")
+
+	testCases := []struct {
+		desc     string
+		options  map[string]string
+		expected string
+	}{
+		{
+			desc:     "value",
+			options:  map[string]string{"password": "secret"},
+			expected: "secret",
+		},
+		{
+			desc:     "environment variable",
+			options:  map[string]string{"password-env": "LEGO_TEST_KEYSTORE_PASSWORD"},
+			expected: "from env",
+		},
+		{
+			desc:     "file",
+			options:  map[string]string{"password-file": passwordFile},
+			expected: "from file",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			password, err := newKeyStorePassword(test.options)
+			require.NoError(t, err)
+
+			value, err := password.read()
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestKeyStorePassword_read_errors(t *testing.T) {
+	password, err := newKeyStorePassword(map[string]string{"password-env": "LEGO_TEST_KEYSTORE_PASSWORD_UNSET"})
+	require.NoError(t, err)
+
+	_, err = password.read()
+	require.EqualError(t, err, "the environment variable LEGO_TEST_KEYSTORE_PASSWORD_UNSET of the password is not set")
+
+	password, err = newKeyStorePassword(map[string]string{"password-file": filepath.Join(t.TempDir(), "missing")})
+	require.NoError(t, err)
+
+	_, err = password.read()
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestKeyStoreDeployer_Deploy_pkcs12(t *testing.T) {
+	certPEM, keyPEM := generateTestCertificate(t, "example.com")
+	issuerPEM, _ := generateTestCertificate(t, "ca.example.com")
+
+	certRes := &certificate.Resource{
+		Domain:            "example.com",
+		Certificate:       certPEM,
+		IssuerCertificate: issuerPEM,
+		PrivateKey:        keyPEM,
+	}
+
+	dest := filepath.Join(t.TempDir(), "keystore.p12")
+
+	deployer, err := newDeployer("keystore format=pkcs12 dest=" + dest + " password=secret")
+	require.NoError(t, err)
+
+	err = deployer.Deploy(nil, certRes)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(dest)
+	require.NoError(t, err)
+
+	_, cert, caCerts, err := pkcs12.DecodeChain(data, "secret")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"example.com"}, cert.DNSNames)
+	require.Len(t, caCerts, 1)
+	assert.Equal(t, "ca.example.com", caCerts[0].Subject.CommonName)
+}
+
+func TestKeyStoreDeployer_Deploy_csr(t *testing.T) {
+	certPEM, _ := generateTestCertificate(t, "example.com")
+
+	deployer, err := newDeployer("keystore dest=" + filepath.Join(t.TempDir(), "keystore.jks") + " password=secret")
+	require.NoError(t, err)
+
+	err = deployer.Deploy(nil, &certificate.Resource{Domain: "example.com", Certificate: certPEM})
+	require.EqualError(t, err, "no private key: the certificate has been obtained with a CSR")
+}
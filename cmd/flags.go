@@ -40,6 +40,8 @@ const (
 	flgDNSPropagationDisableANS = "dns.propagation-disable-ans"
 	flgDNSPropagationRNS        = "dns.propagation-rns"
 	flgDNSResolvers             = "dns.resolvers"
+	flgDNSAccount               = "dns.account"
+	flgDNSPersist               = "dns-persist"
 	flgHTTPTimeout              = "http-timeout"
 	flgTLSSkipVerify            = "tls-skip-verify"
 	flgDNSTimeout               = "dns-timeout"
@@ -47,21 +49,33 @@ const (
 	flgPFX                      = "pfx"
 	flgPFXPass                  = "pfx.pass"
 	flgPFXFormat                = "pfx.format"
+	flgCertbotLayout            = "certbot-layout"
+	flgArchiveKeep              = "archive.keep"
+	flgDeploy                   = "deploy"
+	flgNotifyURL                = "notify.url"
+	flgNotifySecret             = "notify.secret"
+	flgNotifyExpiryWarning      = "notify.expiry-warning"
 	flgCertTimeout              = "cert.timeout"
 	flgOverallRequestLimit      = "overall-request-limit"
 	flgUserAgent                = "user-agent"
+	flgRateLimitMaxWait         = "rate-limit.max-wait"
+	flgRateLimitMaxRetries      = "rate-limit.max-retries"
+	flgConfig                   = "config"
+	flgStorage                  = "storage"
 )
 
 const (
-	envEAB         = "LEGO_EAB"
-	envEABHMAC     = "LEGO_EAB_HMAC"
-	envEABKID      = "LEGO_EAB_KID"
-	envEmail       = "LEGO_EMAIL"
-	envPath        = "LEGO_PATH"
-	envPFX         = "LEGO_PFX"
-	envPFXFormat   = "LEGO_PFX_FORMAT"
-	envPFXPassword = "LEGO_PFX_PASSWORD"
-	envServer      = "LEGO_SERVER"
+	envEAB          = "LEGO_EAB"
+	envEABHMAC      = "LEGO_EAB_HMAC"
+	envEABKID       = "LEGO_EAB_KID"
+	envEmail        = "LEGO_EMAIL"
+	envNotifySecret = "LEGO_NOTIFY_SECRET"
+	envPath         = "LEGO_PATH"
+	envPFX          = "LEGO_PFX"
+	envPFXFormat    = "LEGO_PFX_FORMAT"
+	envPFXPassword  = "LEGO_PFX_PASSWORD"
+	envServer       = "LEGO_SERVER"
+	envStorage      = "LEGO_STORAGE"
 )
 
 func CreateFlags(defaultPath string) []cli.Flag {
@@ -124,12 +138,23 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Name:  flgFilename,
 			Usage: "(deprecated) Filename of the generated certificate.",
 		},
+		&cli.StringFlag{
+			Name: flgConfig,
+			Usage: "Path to a configuration file (TOML) describing several certificates." +
+				" The run, renew, and list commands work over every certificate of the file.",
+		},
 		&cli.StringFlag{
 			Name:    flgPath,
 			EnvVars: []string{envPath},
 			Usage:   "Directory to use for storing the data.",
 			Value:   defaultPath,
 		},
+		&cli.StringFlag{
+			Name:    flgStorage,
+			EnvVars: []string{envStorage},
+			Usage: "Storage of the data, instead of the directory (--path)." +
+				" Supported: file:///path/to/dir, s3://bucket/prefix (options: region, endpoint, path-style), consul://host:port/prefix.",
+		},
 		&cli.BoolFlag{
 			Name:  flgHTTP,
 			Usage: "Use the HTTP-01 challenge to solve challenges. Can be mixed with other types of challenges.",
@@ -180,6 +205,11 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Name:  flgDNS,
 			Usage: "Solve a DNS-01 challenge using the specified provider. Can be mixed with other types of challenges. Run 'lego dnshelp' for help on usage.",
 		},
+		&cli.BoolFlag{
+			Name: flgDNSPersist,
+			Usage: "Solve a DNS-PERSIST-01 challenge using the persistent validation records. Can be mixed with other types of challenges." +
+				" Run 'lego dnspersist' to create the records.",
+		},
 		&cli.BoolFlag{
 			Name:  flgDNSDisableCP,
 			Usage: fmt.Sprintf("(deprecated) use %s instead.", flgDNSPropagationDisableANS),
@@ -203,6 +233,11 @@ func CreateFlags(defaultPath string) []cli.Flag {
 				" Supported: host:port." +
 				" The default is to use the system resolvers, or Google's DNS resolvers if the system's cannot be determined.",
 		},
+		&cli.BoolFlag{
+			Name: flgDNSAccount,
+			Usage: "Use the DNS-ACCOUNT-01 challenge (validation domain name scoped to the account) when the server supports it." +
+				" The DNS-01 challenge is used if the server doesn't support it.",
+		},
 		&cli.IntFlag{
 			Name:  flgHTTPTimeout,
 			Usage: "Set the HTTP timeout value to a specific value in seconds.",
@@ -237,6 +272,35 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Value:   "RC2",
 			EnvVars: []string{envPFXFormat},
 		},
+		&cli.BoolFlag{
+			Name:  flgCertbotLayout,
+			Usage: "Also store the certificates with the Certbot layout: every version is kept in the 'versions' directory, the 'live' directory contains symbolic links to the last version. Requires the local storage.",
+		},
+		&cli.IntFlag{
+			Name:  flgArchiveKeep,
+			Usage: "The number of archived versions kept for each certificate, the oldest versions are removed. By default, all the versions are kept.",
+		},
+		&cli.StringSliceFlag{
+			Name: flgDeploy,
+			Usage: "Deploy the certificates after they are obtained, renewed, or restored." +
+				" A deployer is described by its type (copy, bundle, signal, keystore) and its options, e.g. 'copy src=key dest=/etc/ssl/{name}.key mode=0640'." +
+				" Can be specified multiple times.",
+		},
+		&cli.StringSliceFlag{
+			Name: flgNotifyURL,
+			Usage: "Send the events of the certificates (issued, renewed, skipped, failed, expiring, renewal_window_changed) to this URL (JSON, POST)." +
+				" Can be specified multiple times.",
+		},
+		&cli.StringFlag{
+			Name:    flgNotifySecret,
+			Usage:   "The secret used to sign the events sent to the --" + flgNotifyURL + " URLs (HMAC-SHA256 of the X-Lego-Timestamp header and the body, X-Lego-Signature header).",
+			EnvVars: []string{envNotifySecret},
+		},
+		&cli.DurationFlag{
+			Name:  flgNotifyExpiryWarning,
+			Usage: "Send an expiring event when a certificate expires within this duration.",
+			Value: certificate.DefaultExpiryWarning,
+		},
 		&cli.IntFlag{
 			Name:  flgCertTimeout,
 			Usage: "Set the certificate timeout value to a specific value in seconds. Only used when obtaining certificates.",
@@ -251,6 +315,17 @@ func CreateFlags(defaultPath string) []cli.Flag {
 			Name:  flgUserAgent,
 			Usage: "Add to the user-agent sent to the CA to identify an application embedding lego-cli",
 		},
+		&cli.DurationFlag{
+			Name: flgRateLimitMaxWait,
+			Usage: "Wait and retry when a request is rejected by a rate limit, if the server asks to wait (Retry-After) less than this duration." +
+				" By default, the requests are not retried." +
+				" The 'renew' command defers the renewal to the next run when the server asks to wait longer.",
+		},
+		&cli.IntFlag{
+			Name:  flgRateLimitMaxRetries,
+			Usage: "The maximum number of retries of a request rejected by a rate limit. Only used with --" + flgRateLimitMaxWait + ".",
+			Value: 3,
+		},
 	}
 }
 
@@ -2,15 +2,32 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/certcrypto"
 	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Hook events.
+const (
+	hookEventPre     = "pre"
+	hookEventPost    = "post"
+	hookEventFailure = "failure"
+	hookEventSkip    = "skip"
 )
 
 const (
@@ -21,20 +38,239 @@ const (
 	hookEnvIssuerCertKeyPath = "LEGO_ISSUER_CERT_PATH"
 	hookEnvCertPEMPath       = "LEGO_CERT_PEM_PATH"
 	hookEnvCertPFXPath       = "LEGO_CERT_PFX_PATH"
+	hookEnvCertLivePath      = "LEGO_CERT_LIVE_PATH"
+	hookEnvEvent             = "LEGO_HOOK_EVENT"
 )
 
-func launchHook(hook string, timeout time.Duration, meta map[string]string) error {
+// hookPayload the JSON document sent to the hooks on stdin.
+type hookPayload struct {
+	Event   string   `json:"event"`
+	Command string   `json:"command"`
+	Account string   `json:"account,omitempty"`
+	Domain  string   `json:"domain"`
+	Domains []string `json:"domains,omitempty"`
+	Profile string   `json:"profile,omitempty"`
+
+	// Files the locations of the files of the certificate (post event).
+	Files map[string]string `json:"files,omitempty"`
+
+	// The certificate: the new certificate (post event), or the current certificate (renew).
+	Serial    string     `json:"serial,omitempty"`
+	Issuer    string     `json:"issuer,omitempty"`
+	NotBefore *time.Time `json:"notBefore,omitempty"`
+	NotAfter  *time.Time `json:"notAfter,omitempty"`
+
+	// RenewalWindow the renewal window suggested by the renewalInfo endpoint (ARI).
+	RenewalWindow *acme.Window `json:"renewalWindow,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// withCertificate adds the information of a certificate to the payload.
+func (p *hookPayload) withCertificate(cert *x509.Certificate) *hookPayload {
+	if cert == nil {
+		return p
+	}
+
+	p.Serial = fmt.Sprintf("%x", cert.SerialNumber)
+	p.Issuer = cert.Issuer.String()
+	p.NotBefore = &cert.NotBefore
+	p.NotAfter = &cert.NotAfter
+
+	if len(p.Domains) == 0 {
+		p.Domains = certcrypto.ExtractDomains(cert)
+	}
+
+	return p
+}
+
+// hookRunner launches the hooks of a command: a hook for each event (pre, post, failure, skip).
+// It also sends the events unknown by the Certifier to the notifier (skipped, expiring).
+type hookRunner struct {
+	command string
+	account string
+	profile string
+	hooks   map[string]string
+	timeout time.Duration
+
+	notifier      certificate.Notifier
+	expiryWarning time.Duration
+}
+
+// newHookRunner creates a hookRunner, the post hook depends on the command (--run-hook, --renew-hook).
+func newHookRunner(ctx *cli.Context, account, postHook string, timeout time.Duration) *hookRunner {
+	return &hookRunner{
+		command: ctx.Command.FullName(),
+		account: account,
+		profile: ctx.String(flgProfile),
+		hooks: map[string]string{
+			hookEventPre:     ctx.String(flgPreHook),
+			hookEventPost:    postHook,
+			hookEventFailure: ctx.String(flgFailureHook),
+			hookEventSkip:    ctx.String(flgSkipHook),
+		},
+		timeout:       timeout,
+		notifier:      newNotifier(ctx, certificate.EventIssued),
+		expiryWarning: ctx.Duration(flgNotifyExpiryWarning),
+	}
+}
+
+func (r *hookRunner) newPayload(event, domain string, domains []string) *hookPayload {
+	return &hookPayload{
+		Event:   event,
+		Command: r.command,
+		Account: r.account,
+		Domain:  domain,
+		Domains: domains,
+		Profile: r.profile,
+	}
+}
+
+// pre launches the hook executed before obtaining a certificate.
+// The current certificate and the renewal window are known when renewing a certificate.
+func (r *hookRunner) pre(domain string, domains []string, current *x509.Certificate, window *acme.Window) error {
+	payload := r.newPayload(hookEventPre, domain, domains).withCertificate(current)
+	payload.RenewalWindow = window
+
+	return r.launch(payload, nil)
+}
+
+// post launches the hook executed after obtaining a certificate.
+func (r *hookRunner) post(certRes *certificate.Resource, certsStorage *CertificatesStorage) error {
+	meta := map[string]string{}
+
+	addPathToMetadata(meta, certRes.Domain, certRes, certsStorage)
+
+	payload := r.newPayload(hookEventPost, certRes.Domain, nil)
+	payload.Files = hookFiles(meta)
+
+	certificates, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	if err == nil {
+		payload.withCertificate(certificates[0])
+	}
+
+	return r.launch(payload, meta)
+}
+
+// postGroup launches the post hook for each part of a split certificate.
+func (r *hookRunner) postGroup(resources []*certificate.Resource, certsStorage *CertificatesStorage) error {
+	for _, resource := range resources {
+		err := r.post(resource, certsStorage)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failure launches the hook executed when a certificate cannot be obtained.
+// The command fails anyway: the failure of the hook is only logged.
+func (r *hookRunner) failure(domain string, domains []string, current *x509.Certificate, cause error) {
+	payload := r.newPayload(hookEventFailure, domain, domains).withCertificate(current)
+	payload.Error = cause.Error()
+
+	err := r.launch(payload, nil)
+	if err != nil {
+		log.Warnf("[%s] The failure hook has failed: %v", domain, err)
+	}
+}
+
+// skip launches the hook executed when a certificate doesn't need to be renewed,
+// or when the renewal is deferred by the CA (cause).
+func (r *hookRunner) skip(domain string, current *x509.Certificate, window *acme.Window, cause error) error {
+	payload := r.newPayload(hookEventSkip, domain, nil).withCertificate(current)
+	payload.RenewalWindow = window
+
+	event := certificate.NewEvent(certificate.EventSkipped, domain, current)
+	event.RenewalWindow = window
+
+	if cause != nil {
+		payload.Error = cause.Error()
+		event.Error = cause.Error()
+	}
+
+	notifyEvent(r.notifier, event)
+
+	return r.launch(payload, nil)
+}
+
+// expiring sends an expiring event if the current certificate expires within the warning duration (--notify.expiry-warning).
+func (r *hookRunner) expiring(domain string, current *x509.Certificate) {
+	if current == nil || time.Until(current.NotAfter) >= r.expiryWarning {
+		return
+	}
+
+	notifyEvent(r.notifier, certificate.NewEvent(certificate.EventExpiring, domain, current))
+}
+
+// launch launches the hook of the event, with the environment variables and the payload.
+func (r *hookRunner) launch(payload *hookPayload, meta map[string]string) error {
+	hook := r.hooks[payload.Event]
+	if hook == "" {
+		return nil
+	}
+
+	env := maps.Clone(meta)
+	if env == nil {
+		env = map[string]string{}
+	}
+
+	env[hookEnvEvent] = payload.Event
+	env[hookEnvAccountEmail] = r.account
+	env[hookEnvCertDomain] = payload.Domain
+
+	return launchHook(hook, r.timeout, env, payload)
+}
+
+// hookFiles returns the locations of the files of a certificate, from the environment variables.
+func hookFiles(meta map[string]string) map[string]string {
+	names := map[string]string{
+		hookEnvCertPath:          "certificate",
+		hookEnvCertKeyPath:       "key",
+		hookEnvIssuerCertKeyPath: "issuer",
+		hookEnvCertPEMPath:       "pem",
+		hookEnvCertPFXPath:       "pfx",
+		hookEnvCertLivePath:      "live",
+	}
+
+	files := map[string]string{}
+
+	for env, name := range names {
+		if value, ok := meta[env]; ok {
+			files[name] = value
+		}
+	}
+
+	return files
+}
+
+// launchHook launches a hook command: the metadata are sent as environment variables, and the payload as JSON on stdin.
+func launchHook(hook string, timeout time.Duration, meta map[string]string, payload *hookPayload) error {
 	if hook == "" {
 		return nil
 	}
 
+	parts, err := splitQuoted(hook)
+	if err != nil {
+		return fmt.Errorf("parse command: %w", err)
+	}
+
+	if len(parts) == 0 {
+		return nil
+	}
+
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+
 	ctxCmd, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	parts := strings.Fields(hook)
-
 	cmd := exec.CommandContext(ctxCmd, parts[0], parts[1:]...)
 	cmd.Env = append(os.Environ(), metaToEnv(meta)...)
+	cmd.Stdin = bytes.NewReader(input)
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -99,4 +335,74 @@ func addPathToMetadata(meta map[string]string, domain string, certRes *certifica
 	if certsStorage.pfx {
 		meta[hookEnvCertPFXPath] = certsStorage.GetFileName(domain, pfxExt)
 	}
+
+	if certsStorage.certbot {
+		meta[hookEnvCertLivePath] = certsStorage.GetLivePath(domain)
+	}
+}
+
+// splitQuoted splits a string into fields separated by spaces, like a shell (hook commands, deployer descriptions).
+// The quoted strings (single or double quotes) are kept together and the quotes are removed:
+// in single quotes, the characters are kept as is;
+// in double quotes, a backslash escapes a double quote or a backslash.
+// The other backslashes are kept (Windows paths).
+func splitQuoted(s string) ([]string, error) {
+	var (
+		fields []string
+		field  strings.Builder
+		// inField is needed to keep the empty quoted fields (e.g. alias="").
+		inField bool
+		quote   rune
+	)
+
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				field.WriteRune(r)
+			}
+
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				field.WriteRune(runes[i])
+			default:
+				field.WriteRune(r)
+			}
+
+		case r == '\'' || r == '"':
+			quote = r
+			inField = true
+
+		case unicode.IsSpace(r):
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote (%c) in %q", quote, s)
+	}
+
+	if inField {
+		fields = append(fields, field.String())
+	}
+
+	return fields, nil
 }
@@ -1,18 +1,55 @@
 package cmd
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func Test_launchHook(t *testing.T) {
-	err := launchHook("echo foo", 1*time.Second, map[string]string{})
+	err := launchHook("echo foo", 1*time.Second, map[string]string{}, &hookPayload{})
 	require.NoError(t, err)
 }
 
+func Test_launchHook_payload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	dir := t.TempDir()
+
+	hook := `sh -c 'cat > "$0/payload.json"; echo "$LEGO_HOOK_EVENT" > "$0/event"' "` + dir + `"`
+
+	payload := &hookPayload{
+		Event:   hookEventFailure,
+		Command: "renew",
+		Domain:  "example.com",
+		Domains: []string{"example.com", "*.example.com"},
+		Error:   "boom",
+	}
+
+	err := launchHook(hook, 1*time.Second, map[string]string{hookEnvEvent: hookEventFailure}, payload)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "payload.json"))
+	require.NoError(t, err)
+
+	var actual hookPayload
+
+	err = json.Unmarshal(data, &actual)
+	require.NoError(t, err)
+
+	assert.Equal(t, payload, &actual)
+
+	assertFileContent(t, filepath.Join(dir, "event"), []byte("failure\n"))
+}
+
 func Test_launchHook_errors(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("skipping test on Windows")
@@ -54,8 +91,72 @@ func Test_launchHook_errors(t *testing.T) {
 		t.Run(test.desc, func(t *testing.T) {
 			t.Parallel()
 
-			err := launchHook(test.hook, test.timeout, map[string]string{})
+			err := launchHook(test.hook, test.timeout, map[string]string{}, &hookPayload{})
 			require.EqualError(t, err, test.expected)
 		})
 	}
 }
+
+func Test_splitQuoted(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		value    string
+		expected []string
+	}{
+		{
+			desc:     "simple",
+			value:    "./hook.sh foo  bar",
+			expected: []string{"./hook.sh", "foo", "bar"},
+		},
+		{
+			desc:     "single quotes",
+			value:    `sh -c 'systemctl reload "nginx"'`,
+			expected: []string{"sh", "-c", `systemctl reload "nginx"`},
+		},
+		{
+			desc:     "double quotes",
+			value:    `"/opt/my hooks/hook.sh" "it's" "a \"b\" \\c"`,
+			expected: []string{"/opt/my hooks/hook.sh", "it's", `a "b" \c`},
+		},
+		{
+			desc:     "concatenated quotes",
+			value:    `--name='a b'"c d"e`,
+			expected: []string{"--name=a bc de"},
+		},
+		{
+			desc:     "empty argument",
+			value:    `hook.sh ''`,
+			expected: []string{"hook.sh", ""},
+		},
+		{
+			desc:     "spaces",
+			value:    "  copy\tsrc=key\n dest=/tmp/key ",
+			expected: []string{"copy", "src=key", "dest=/tmp/key"},
+		},
+		{
+			desc:     "Windows path",
+			value:    `C:\hooks\hook.exe "C:\my certs"`,
+			expected: []string{`C:\hooks\hook.exe`, `C:\my certs`},
+		},
+		{
+			desc:  "empty",
+			value: "  ",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			args, err := splitQuoted(test.value)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, args)
+		})
+	}
+}
+
+func Test_splitQuoted_errors(t *testing.T) {
+	_, err := splitQuoted(`sh -c 'echo foo`)
+	require.EqualError(t, err, `unterminated quote (') in "sh -c 'echo foo"`)
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // Required by the JKS format.
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	jksMagic         = 0xFEEDFEED
+	jksVersion       = 2
+	jksPrivateKeyTag = 1
+	jksWhitener      = "Mighty Aphrodite"
+)
+
+// jksKeyProtectorOID the OID of the Sun proprietary algorithm protecting the private keys.
+var jksKeyProtectorOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// encodeJKS encodes a private key and its certificate chain to a Java KeyStore (JKS, version 2),
+// the keystore contains a single entry.
+func encodeJKS(privateKey crypto.PrivateKey, chain []*x509.Certificate, alias, password string, date time.Time) ([]byte, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("empty certificate chain")
+	}
+
+	plainKey, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordBytes := jksPassword(password)
+
+	protectedKey, err := jksProtectKey(plainKey, passwordBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedKey, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: jksKeyProtectorOID, Parameters: asn1.NullRawValue},
+		EncryptedData: protectedKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+
+	write := func(v any) {
+		// Writes to a bytes.Buffer don't fail.
+		_ = binary.Write(buf, binary.BigEndian, v)
+	}
+
+	writeUTF := func(s string) {
+		write(uint16(len(s)))
+		buf.WriteString(s)
+	}
+
+	write(uint32(jksMagic))
+	write(uint32(jksVersion))
+	write(uint32(1))
+
+	write(uint32(jksPrivateKeyTag))
+	writeUTF(alias)
+	write(date.UnixMilli())
+	write(uint32(len(encryptedKey)))
+	buf.Write(encryptedKey)
+
+	write(uint32(len(chain)))
+
+	for _, cert := range chain {
+		writeUTF("X.509")
+		write(uint32(len(cert.Raw)))
+		buf.Write(cert.Raw)
+	}
+
+	// The integrity of the keystore is checked with the password.
+	hash := sha1.New() //nolint:gosec // Required by the JKS format.
+	hash.Write(passwordBytes)
+	hash.Write([]byte(jksWhitener))
+	hash.Write(buf.Bytes())
+
+	buf.Write(hash.Sum(nil))
+
+	return buf.Bytes(), nil
+}
+
+// jksProtectKey protects a private key (PKCS#8) with the Sun proprietary algorithm (KeyProtector):
+// the key is XORed with a SHA-1 based key stream, followed by a SHA-1 checksum.
+func jksProtectKey(plainKey, password []byte) ([]byte, error) {
+	salt := make([]byte, sha1.Size)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := make([]byte, len(plainKey))
+
+	digest := salt
+
+	for offset := 0; offset < len(plainKey); offset += sha1.Size {
+		sum := sha1.Sum(slices.Concat(password, digest)) //nolint:gosec // Required by the JKS format.
+		digest = sum[:]
+
+		for i := offset; i < min(offset+sha1.Size, len(plainKey)); i++ {
+			encrypted[i] = plainKey[i] ^ digest[i-offset]
+		}
+	}
+
+	check := sha1.Sum(slices.Concat(password, plainKey)) //nolint:gosec // Required by the JKS format.
+
+	return slices.Concat(salt, encrypted, check[:]), nil
+}
+
+// jksPassword returns the bytes of a password, as used by the JKS format (UTF-16BE).
+func jksPassword(password string) []byte {
+	var b []byte
+
+	for _, c := range utf16.Encode([]rune(password)) {
+		b = binary.BigEndian.AppendUint16(b, c)
+	}
+
+	return b
+}
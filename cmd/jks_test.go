@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // Required by the JKS format.
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_encodeJKS(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	certPEM, _ := generateTestCertificate(t, "example.com")
+
+	cert, err := certcrypto.ParsePEMCertificate(certPEM)
+	require.NoError(t, err)
+
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	data, err := encodeJKS(privateKey, []*x509.Certificate{cert}, "example.com", "secret", date)
+	require.NoError(t, err)
+
+	passwordBytes := jksPassword("secret")
+
+	// The keystore ends with the hash of the password, the whitener and the content.
+	content, sum := data[:len(data)-sha1.Size], data[len(data)-sha1.Size:]
+
+	expectedSum := sha1.Sum(slices.Concat(passwordBytes, []byte(jksWhitener), content)) //nolint:gosec // Required by the JKS format.
+	assert.Equal(t, expectedSum[:], sum)
+
+	reader := bytes.NewReader(content)
+
+	read := func(v any) {
+		t.Helper()
+
+		require.NoError(t, binary.Read(reader, binary.BigEndian, v))
+	}
+
+	readBytes := func(n int) []byte {
+		t.Helper()
+
+		b := make([]byte, n)
+		_, errR := reader.Read(b)
+		require.NoError(t, errR)
+
+		return b
+	}
+
+	readUTF := func() string {
+		t.Helper()
+
+		var n uint16
+		read(&n)
+
+		return string(readBytes(int(n)))
+	}
+
+	var magic, version, count, tag, keyLength, chainLength, certLength uint32
+	var timestamp int64
+
+	read(&magic)
+	read(&version)
+	read(&count)
+	read(&tag)
+
+	assert.Equal(t, uint32(jksMagic), magic)
+	assert.Equal(t, uint32(jksVersion), version)
+	assert.Equal(t, uint32(1), count)
+	assert.Equal(t, uint32(jksPrivateKeyTag), tag)
+
+	assert.Equal(t, "example.com", readUTF())
+
+	read(&timestamp)
+	assert.Equal(t, date.UnixMilli(), timestamp)
+
+	read(&keyLength)
+
+	var info encryptedPrivateKeyInfo
+	_, err = asn1.Unmarshal(readBytes(int(keyLength)), &info)
+	require.NoError(t, err)
+
+	assert.Equal(t, jksKeyProtectorOID, info.Algorithm.Algorithm)
+
+	read(&chainLength)
+	assert.Equal(t, uint32(1), chainLength)
+
+	assert.Equal(t, "X.509", readUTF())
+
+	read(&certLength)
+	assert.Equal(t, cert.Raw, readBytes(int(certLength)))
+
+	assert.Zero(t, reader.Len())
+
+	// The private key is recovered with the password.
+	plainKey := jksRecoverKey(t, info.EncryptedData, passwordBytes)
+
+	expectedKey, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedKey, plainKey)
+}
+
+func Test_jksPassword(t *testing.T) {
+	assert.Equal(t, []byte{0, 'a', 0, 'b', 0x9b, 0xe9}, jksPassword("ab\u9be9"))
+
+	// UTF-16 surrogate pair.
+	assert.Equal(t, []byte{0xd8, 0x3d, 0xde, 0x00}, jksPassword("\U0001F600"))
+}
+
+// jksRecoverKey implements the recovery of a private key protected by jksProtectKey.
+func jksRecoverKey(t *testing.T, protected, password []byte) []byte {
+	t.Helper()
+
+	salt := protected[:sha1.Size]
+	encrypted := protected[sha1.Size : len(protected)-sha1.Size]
+	check := protected[len(protected)-sha1.Size:]
+
+	plainKey := make([]byte, len(encrypted))
+
+	digest := salt
+
+	for offset := 0; offset < len(encrypted); offset += sha1.Size {
+		sum := sha1.Sum(slices.Concat(password, digest)) //nolint:gosec // Required by the JKS format.
+		digest = sum[:]
+
+		for i := offset; i < min(offset+sha1.Size, len(encrypted)); i++ {
+			plainKey[i] = encrypted[i] ^ digest[i-offset]
+		}
+	}
+
+	expectedCheck := sha1.Sum(slices.Concat(password, plainKey)) //nolint:gosec // Required by the JKS format.
+	require.Equal(t, expectedCheck[:], check)
+
+	return plainKey
+}
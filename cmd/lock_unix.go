@@ -0,0 +1,37 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLockFile takes an exclusive (or shared) advisory lock on the file, without waiting.
+// Returns false if the lock is held by another process.
+func tryLockFile(file *os.File, exclusive bool) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), flockHow(exclusive)|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// lockFile takes an exclusive (or shared) advisory lock on the file, waiting for the lock if needed.
+func lockFile(file *os.File, exclusive bool) error {
+	return syscall.Flock(int(file.Fd()), flockHow(exclusive))
+}
+
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
+
+func flockHow(exclusive bool) int {
+	if exclusive {
+		return syscall.LOCK_EX
+	}
+
+	return syscall.LOCK_SH
+}
@@ -0,0 +1,42 @@
+//go:build windows
+
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLockFile takes an exclusive (or shared) lock on the file, without waiting.
+// Returns false if the lock is held by another process.
+func tryLockFile(file *os.File, exclusive bool) (bool, error) {
+	err := lockFileEx(file, lockFileFlags(exclusive)|windows.LOCKFILE_FAIL_IMMEDIATELY)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// lockFile takes an exclusive (or shared) lock on the file, waiting for the lock if needed.
+func lockFile(file *os.File, exclusive bool) error {
+	return lockFileEx(file, lockFileFlags(exclusive))
+}
+
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, new(windows.Overlapped))
+}
+
+func lockFileEx(file *os.File, flags uint32) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, new(windows.Overlapped))
+}
+
+func lockFileFlags(exclusive bool) uint32 {
+	if exclusive {
+		return windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+
+	return 0
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// newNotifier creates the notifier sending the events to the webhooks (--notify.url).
+// issued is the type of the events sent for the obtained certificates:
+// the certificates obtained by the renew command are renewals (certificate.EventRenewed).
+// Returns nil without webhook.
+func newNotifier(ctx *cli.Context, issued certificate.EventType) certificate.Notifier {
+	endpoints := ctx.StringSlice(flgNotifyURL)
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	var notifiers certificate.Notifiers
+
+	for _, endpoint := range endpoints {
+		notifiers = append(notifiers, certificate.NewWebhookNotifier(endpoint, ctx.String(flgNotifySecret)))
+	}
+
+	if issued == certificate.EventRenewed {
+		return renewalNotifier{Notifier: notifiers}
+	}
+
+	return notifiers
+}
+
+// checkNotifyURLs checks the webhook URLs.
+func checkNotifyURLs(endpoints []string) error {
+	for _, endpoint := range endpoints {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return err
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("%s: only the HTTP and HTTPS URLs are supported", endpoint)
+		}
+	}
+
+	return nil
+}
+
+// renewalNotifier sends the issued events as renewed events.
+type renewalNotifier struct {
+	certificate.Notifier
+}
+
+func (n renewalNotifier) Notify(ctx context.Context, event *certificate.Event) error {
+	if event.Type == certificate.EventIssued {
+		renewed := *event
+		renewed.Type = certificate.EventRenewed
+
+		event = &renewed
+	}
+
+	return n.Notifier.Notify(ctx, event)
+}
+
+// notifyEvent sends an event to the notifier, the failures are only logged.
+func notifyEvent(notifier certificate.Notifier, event *certificate.Event) {
+	if notifier == nil {
+		return
+	}
+
+	err := notifier.Notify(context.Background(), event)
+	if err != nil {
+		log.Warnf("[%s] notifier: %s event: %v", event.Domain, event.Type, err)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func Test_checkNotifyURLs(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		endpoints []string
+		expected  string
+	}{
+		{
+			desc:      "valid",
+			endpoints: []string{"https://hooks.example.com/lego", "http://localhost:8080"},
+		},
+		{
+			desc:      "unsupported scheme",
+			endpoints: []string{"ftp://example.com"},
+			expected:  "ftp://example.com: only the HTTP and HTTPS URLs are supported",
+		},
+		{
+			desc:      "no host",
+			endpoints: []string{"https:///lego"},
+			expected:  "https:///lego: only the HTTP and HTTPS URLs are supported",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkNotifyURLs(test.endpoints)
+			if test.expected == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expected)
+			}
+		})
+	}
+}
+
+func Test_newNotifier(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	require.NoError(t, (&cli.StringSliceFlag{Name: flgNotifyURL}).Apply(set))
+	require.NoError(t, (&cli.StringFlag{Name: flgNotifySecret}).Apply(set))
+
+	ctx := cli.NewContext(cli.NewApp(), set, nil)
+
+	assert.Nil(t, newNotifier(ctx, certificate.EventIssued))
+
+	require.NoError(t, set.Set(flgNotifyURL, "https://hooks.example.com/lego"))
+
+	assert.IsType(t, certificate.Notifiers{}, newNotifier(ctx, certificate.EventIssued))
+	assert.IsType(t, renewalNotifier{}, newNotifier(ctx, certificate.EventRenewed))
+}
+
+func Test_renewalNotifier(t *testing.T) {
+	var events []certificate.EventType
+
+	notifier := renewalNotifier{Notifier: certificate.NotifierFunc(func(_ context.Context, event *certificate.Event) error {
+		events = append(events, event.Type)
+		return nil
+	})}
+
+	for _, eventType := range []certificate.EventType{certificate.EventIssued, certificate.EventFailed} {
+		err := notifier.Notify(t.Context(), certificate.NewEvent(eventType, "example.com", nil))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []certificate.EventType{certificate.EventRenewed, certificate.EventFailed}, events)
+}
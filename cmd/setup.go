@@ -1,15 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/lego"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/go-acme/lego/v4/registration"
@@ -20,29 +24,123 @@ import (
 const filePerm os.FileMode = 0o600
 
 // setupClient creates a new client with challenge settings.
-func setupClient(ctx *cli.Context, account *Account, keyType certcrypto.KeyType) *lego.Client {
-	client := newClient(ctx, account, keyType)
+// issued is the type of the events sent for the obtained certificates (certificate.EventRenewed for the renew command).
+func setupClient(ctx *cli.Context, account *Account, keyType certcrypto.KeyType, issued certificate.EventType) (*lego.Client, error) {
+	client, err := initClient(ctx, account, keyType, issued)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setupChallenges(ctx, client)
+	if err != nil {
+		return nil, err
+	}
 
-	setupChallenges(ctx, client)
+	return client, nil
+}
 
-	return client
+// setupAccount loads the account, and creates its key if needed.
+// The account is used under its shared lock, released by calling the returned function:
+// the account cannot be changed by another lego process (e.g. keychange) until the end of the command.
+func setupAccount(ctx *cli.Context, accountsStorage *AccountsStorage) (*Account, certcrypto.KeyType, func()) {
+	account, keyType, unlock, err := openAccount(ctx, accountsStorage)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return account, keyType, unlock
+}
+
+// openAccount loads the account, and creates its key if needed (see setupAccount).
+func openAccount(ctx *cli.Context, accountsStorage *AccountsStorage) (*Account, certcrypto.KeyType, func(), error) {
+	keyType, err := parseKeyType(ctx)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	unlock, err := accountsStorage.rLock()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if _, err = accountsStorage.ReadPrivateKey(); errors.Is(err, os.ErrNotExist) {
+		unlock()
+
+		// The key is created by only one lego process.
+		err = createAccountKey(accountsStorage, keyType)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		unlock, err = accountsStorage.rLock()
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	account, err := loadAccount(accountsStorage, keyType)
+	if err != nil {
+		unlock()
+		return nil, "", nil, err
+	}
+
+	return account, keyType, unlock, nil
+}
+
+// createAccountKey creates the key of the account under the exclusive lock of the account.
+func createAccountKey(accountsStorage *AccountsStorage, keyType certcrypto.KeyType) error {
+	unlock, err := accountsStorage.lock()
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	_, err = accountsStorage.getOrCreatePrivateKey(keyType)
+
+	return err
 }
 
-func setupAccount(ctx *cli.Context, accountsStorage *AccountsStorage) (*Account, certcrypto.KeyType) {
-	keyType := getKeyType(ctx)
-	privateKey := accountsStorage.GetPrivateKey(keyType)
+// loadAccount loads the account, the caller holds the lock of the account.
+func loadAccount(accountsStorage *AccountsStorage, keyType certcrypto.KeyType) (*Account, error) {
+	privateKey, err := accountsStorage.getOrCreatePrivateKey(keyType)
+	if err != nil {
+		return nil, err
+	}
 
-	var account *Account
 	if accountsStorage.ExistsAccountFilePath() {
-		account = accountsStorage.LoadAccount(privateKey)
-	} else {
-		account = &Account{Email: accountsStorage.GetUserID(), key: privateKey}
+		return accountsStorage.readAccount(privateKey)
 	}
 
-	return account, keyType
+	return &Account{Email: accountsStorage.GetUserID(), key: privateKey}, nil
 }
 
+// newClient creates a client for the commands which do not obtain certificates.
 func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyType) *lego.Client {
+	client, err := initClient(ctx, acc, keyType, certificate.EventIssued)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return client
+}
+
+// initClient creates a client, and checks the requirements of the server.
+func initClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyType, issued certificate.EventType) (*lego.Client, error) {
+	client, err := createClient(ctx, acc, keyType, issued)
+	if err != nil {
+		return nil, fmt.Errorf("could not create client: %w", err)
+	}
+
+	if client.GetExternalAccountRequired() && !ctx.IsSet(flgEAB) {
+		return nil, fmt.Errorf("server requires External Account Binding: use --%s with --%s and --%s", flgEAB, flgKID, flgHMAC)
+	}
+
+	return client, nil
+}
+
+// createClient creates a client, without checking the requirements of the server.
+func createClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyType, issued certificate.EventType) (*lego.Client, error) {
 	config := lego.NewConfig(acc)
 	config.CADirURL = ctx.String(flgServer)
 
@@ -51,9 +149,18 @@ func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyTy
 		Timeout:             time.Duration(ctx.Int(flgCertTimeout)) * time.Second,
 		OverallRequestLimit: ctx.Int(flgOverallRequestLimit),
 		DisableCommonName:   ctx.Bool(flgDisableCommonName),
+		OrderStore:          NewCertificatesStorage(ctx),
+		Notifier:            newNotifier(ctx, issued),
 	}
 	config.UserAgent = getUserAgent(ctx)
 
+	if ctx.IsSet(flgRateLimitMaxWait) {
+		config.RetryPolicy = &api.RetryPolicy{
+			MaxWait:    ctx.Duration(flgRateLimitMaxWait),
+			MaxRetries: ctx.Int(flgRateLimitMaxRetries),
+		}
+	}
+
 	if ctx.IsSet(flgHTTPTimeout) {
 		config.HTTPClient.Timeout = time.Duration(ctx.Int(flgHTTPTimeout)) * time.Second
 	}
@@ -71,6 +178,7 @@ func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyTy
 	retryClient.RetryMax = 5
 	retryClient.HTTPClient = config.HTTPClient
 	retryClient.Logger = nil
+	retryClient.CheckRetry = checkRetry
 
 	if _, v := os.LookupEnv("LEGO_DEBUG_ACME_HTTP_CLIENT"); v {
 		retryClient.Logger = log.Logger
@@ -78,46 +186,55 @@ func newClient(ctx *cli.Context, acc registration.User, keyType certcrypto.KeyTy
 
 	config.HTTPClient = retryClient.StandardClient()
 
-	client, err := lego.NewClient(config)
-	if err != nil {
-		log.Fatalf("Could not create client: %v", err)
-	}
+	return lego.NewClient(config)
+}
 
-	if client.GetExternalAccountRequired() && !ctx.IsSet(flgEAB) {
-		log.Fatalf("Server requires External Account Binding. Use --%s with --%s and --%s.", flgEAB, flgKID, flgHMAC)
+// getKeyType the type from which private keys should be generated.
+func getKeyType(ctx *cli.Context) certcrypto.KeyType {
+	keyType, err := parseKeyType(ctx)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	return client
+	return keyType
 }
 
-// getKeyType the type from which private keys should be generated.
-func getKeyType(ctx *cli.Context) certcrypto.KeyType {
+func parseKeyType(ctx *cli.Context) (certcrypto.KeyType, error) {
 	keyType := ctx.String(flgKeyType)
 	switch strings.ToUpper(keyType) {
 	case "RSA2048":
-		return certcrypto.RSA2048
+		return certcrypto.RSA2048, nil
 	case "RSA3072":
-		return certcrypto.RSA3072
+		return certcrypto.RSA3072, nil
 	case "RSA4096":
-		return certcrypto.RSA4096
+		return certcrypto.RSA4096, nil
 	case "RSA8192":
-		return certcrypto.RSA8192
+		return certcrypto.RSA8192, nil
 	case "EC256":
-		return certcrypto.EC256
+		return certcrypto.EC256, nil
 	case "EC384":
-		return certcrypto.EC384
+		return certcrypto.EC384, nil
 	}
 
-	log.Fatalf("Unsupported KeyType: %s", keyType)
-	return ""
+	return "", fmt.Errorf("unsupported key type: %s", keyType)
 }
 
 func getEmail(ctx *cli.Context) string {
+	email, err := parseEmail(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return email
+}
+
+func parseEmail(ctx *cli.Context) (string, error) {
 	email := ctx.String(flgEmail)
 	if email == "" {
-		log.Fatalf("You have to pass an account (email address) to the program using --%s or -m", flgEmail)
+		return "", fmt.Errorf("you have to pass an account (email address) to the program using --%s or -m", flgEmail)
 	}
-	return email
+
+	return email, nil
 }
 
 func getUserAgent(ctx *cli.Context) string {
@@ -163,3 +280,14 @@ func readCSRFile(filename string) (*x509.CertificateRequest, error) {
 	// (if this assumption is wrong, parsing these bytes will fail)
 	return x509.ParseCertificateRequest(raw)
 }
+
+// checkRetry doesn't retry the responses with a Retry-After header:
+// they are handled by the ACME client (rate limits).
+func checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if resp != nil && resp.Header.Get("Retry-After") != "" &&
+		(resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		return false, nil
+	}
+
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
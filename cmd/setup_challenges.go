@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/go-acme/lego/v4/challenge"
 	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/go-acme/lego/v4/challenge/dnspersist01"
 	"github.com/go-acme/lego/v4/challenge/http01"
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
 	"github.com/go-acme/lego/v4/lego"
@@ -19,101 +21,122 @@ import (
 	"github.com/urfave/cli/v2"
 )
 
-func setupChallenges(ctx *cli.Context, client *lego.Client) {
-	if !ctx.Bool(flgHTTP) && !ctx.Bool(flgTLS) && !ctx.IsSet(flgDNS) {
-		log.Fatalf("No challenge selected. You must specify at least one challenge: `--%s`, `--%s`, `--%s`.", flgHTTP, flgTLS, flgDNS)
+func setupChallenges(ctx *cli.Context, client *lego.Client) error {
+	if !ctx.Bool(flgHTTP) && !ctx.Bool(flgTLS) && !ctx.IsSet(flgDNS) && !ctx.Bool(flgDNSPersist) {
+		return fmt.Errorf("no challenge selected: you must specify at least one challenge: `--%s`, `--%s`, `--%s`, `--%s`", flgHTTP, flgTLS, flgDNS, flgDNSPersist)
 	}
 
 	if ctx.Bool(flgHTTP) {
-		err := client.Challenge.SetHTTP01Provider(setupHTTPProvider(ctx), http01.SetDelay(ctx.Duration(flgHTTPDelay)))
+		provider, err := setupHTTPProvider(ctx)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+
+		err = client.Challenge.SetHTTP01Provider(provider, http01.SetDelay(ctx.Duration(flgHTTPDelay)))
+		if err != nil {
+			return err
 		}
 	}
 
 	if ctx.Bool(flgTLS) {
-		err := client.Challenge.SetTLSALPN01Provider(setupTLSProvider(ctx), tlsalpn01.SetDelay(ctx.Duration(flgTLSDelay)))
+		provider, err := setupTLSProvider(ctx)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+
+		err = client.Challenge.SetTLSALPN01Provider(provider, tlsalpn01.SetDelay(ctx.Duration(flgTLSDelay)))
+		if err != nil {
+			return err
 		}
 	}
 
 	if ctx.IsSet(flgDNS) {
 		err := setupDNS(ctx, client)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+	}
+
+	if ctx.Bool(flgDNSPersist) {
+		servers := ctx.StringSlice(flgDNSResolvers)
+
+		err := client.Challenge.SetDNSPersist01(
+			dnspersist01.CondOption(len(servers) > 0, dnspersist01.SetNameservers(dns01.ParseNameservers(servers))),
+		)
+		if err != nil {
+			return err
 		}
 	}
+
+	return nil
 }
 
 //nolint:gocyclo // the complexity is expected.
-func setupHTTPProvider(ctx *cli.Context) challenge.Provider {
+func setupHTTPProvider(ctx *cli.Context) (challenge.Provider, error) {
 	switch {
 	case ctx.IsSet(flgHTTPWebroot):
 		ps, err := webroot.NewHTTPProvider(ctx.String(flgHTTPWebroot))
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		return ps
+		return ps, nil
 	case ctx.IsSet(flgHTTPMemcachedHost):
 		ps, err := memcached.NewMemcachedProvider(ctx.StringSlice(flgHTTPMemcachedHost))
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		return ps
+		return ps, nil
 	case ctx.IsSet(flgHTTPS3Bucket):
 		ps, err := s3.NewHTTPProvider(ctx.String(flgHTTPS3Bucket))
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
-		return ps
+		return ps, nil
 	case ctx.IsSet(flgHTTPPort):
 		iface := ctx.String(flgHTTPPort)
 		if !strings.Contains(iface, ":") {
-			log.Fatalf("The --%s switch only accepts interface:port or :port for its argument.", flgHTTPPort)
+			return nil, fmt.Errorf("the --%s switch only accepts interface:port or :port for its argument", flgHTTPPort)
 		}
 
 		host, port, err := net.SplitHostPort(iface)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 
 		srv := http01.NewProviderServer(host, port)
 		if header := ctx.String(flgHTTPProxyHeader); header != "" {
 			srv.SetProxyHeader(header)
 		}
-		return srv
+		return srv, nil
 	case ctx.Bool(flgHTTP):
 		srv := http01.NewProviderServer("", "")
 		if header := ctx.String(flgHTTPProxyHeader); header != "" {
 			srv.SetProxyHeader(header)
 		}
-		return srv
+		return srv, nil
 	default:
-		log.Fatal("Invalid HTTP challenge options.")
-		return nil
+		return nil, errors.New("invalid HTTP challenge options")
 	}
 }
 
-func setupTLSProvider(ctx *cli.Context) challenge.Provider {
+func setupTLSProvider(ctx *cli.Context) (challenge.Provider, error) {
 	switch {
 	case ctx.IsSet(flgTLSPort):
 		iface := ctx.String(flgTLSPort)
 		if !strings.Contains(iface, ":") {
-			log.Fatalf("The --%s switch only accepts interface:port or :port for its argument.", flgTLSPort)
+			return nil, fmt.Errorf("the --%s switch only accepts interface:port or :port for its argument", flgTLSPort)
 		}
 
 		host, port, err := net.SplitHostPort(iface)
 		if err != nil {
-			log.Fatal(err)
+			return nil, err
 		}
 
-		return tlsalpn01.NewProviderServer(host, port)
+		return tlsalpn01.NewProviderServer(host, port), nil
 	case ctx.Bool(flgTLS):
-		return tlsalpn01.NewProviderServer("", "")
+		return tlsalpn01.NewProviderServer("", ""), nil
 	default:
-		log.Fatal("Invalid HTTP challenge options.")
-		return nil
+		return nil, errors.New("invalid TLS challenge options")
 	}
 }
 
@@ -135,7 +158,7 @@ func setupDNS(ctx *cli.Context, client *lego.Client) error {
 
 	servers := ctx.StringSlice(flgDNSResolvers)
 
-	err = client.Challenge.SetDNS01Provider(provider,
+	opts := []dns01.ChallengeOption{
 		dns01.CondOption(len(servers) > 0,
 			dns01.AddRecursiveNameservers(dns01.ParseNameservers(ctx.StringSlice(flgDNSResolvers)))),
 
@@ -145,16 +168,24 @@ func setupDNS(ctx *cli.Context, client *lego.Client) error {
 		dns01.CondOption(ctx.Duration(flgDNSPropagationWait) > 0,
 			// TODO(ldez): inside the next major version we will use flgDNSDisableCP here.
 			// This will change the meaning of this flag to really disable all propagation checks.
-			dns01.PropagationWait(wait, true)),
+			dns01.InitialPropagationWait(wait, true)),
 
 		dns01.CondOption(ctx.Bool(flgDNSPropagationRNS),
 			dns01.RecursiveNSsPropagationRequirement()),
 
 		dns01.CondOption(ctx.IsSet(flgDNSTimeout),
 			dns01.AddDNSTimeout(time.Duration(ctx.Int(flgDNSTimeout))*time.Second)),
-	)
+	}
+
+	if ctx.Bool(flgDNSAccount) {
+		// The solver of the DNS-ACCOUNT-01 challenge is preferred to the solver of the DNS-01 challenge.
+		err = client.Challenge.SetDNSAccount01Provider(provider, opts...)
+		if err != nil {
+			return err
+		}
+	}
 
-	return err
+	return client.Challenge.SetDNS01Provider(provider, opts...)
 }
 
 func checkPropagationExclusiveOptions(ctx *cli.Context) error {
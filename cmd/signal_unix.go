@@ -0,0 +1,20 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// parseSignal returns the signal named name (without the SIG prefix, e.g. HUP).
+func parseSignal(name string) (os.Signal, error) {
+	signal := unix.SignalNum("SIG" + name)
+	if signal == 0 {
+		return nil, fmt.Errorf("unknown signal %q", name)
+	}
+
+	return signal, nil
+}
@@ -0,0 +1,14 @@
+//go:build windows
+
+package cmd
+
+import (
+	"errors"
+	"os"
+)
+
+// parseSignal returns the signal named name (without the SIG prefix, e.g. HUP).
+// The signals are not supported on Windows.
+func parseSignal(_ string) (os.Signal, error) {
+	return nil, errors.New("the signals are not supported on Windows")
+}
@@ -0,0 +1,749 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-acme/lego/v4/log"
+	"github.com/urfave/cli/v2"
+)
+
+// Storage a storage backend: it stores the accounts and their keys, the certificates, their keys and resources, and the archives.
+//
+// The files are identified by their names:
+// the filesystem paths for the local storage (default),
+// the paths relative to the root of the storage for the other backends (e.g. "certificates/example.com.crt").
+//
+// The storage is selected with the "storage" option:
+//
+//	file:///var/lib/lego
+//	s3://bucket/prefix?region=eu-west-1&endpoint=https://minio.example.com&path-style=true
+//	consul://localhost:8500/prefix
+type Storage interface {
+	// ReadFile reads a file, the error wraps os.ErrNotExist if the file doesn't exist.
+	ReadFile(name string) ([]byte, error)
+
+	// WriteFile writes a file atomically: the readers get the previous content or the new content.
+	WriteFile(name string, data []byte) error
+
+	// WriteFiles writes several files (e.g. a certificate and its private key) as atomically as possible.
+	// The local storage swaps in the files of a same directory together (see fileStorage.WriteFiles).
+	WriteFiles(files map[string][]byte) error
+
+	// Remove removes a file, it's not an error if the file doesn't exist.
+	Remove(name string) error
+
+	// Rename renames (moves) a file.
+	Rename(oldName, newName string) error
+
+	// List returns the names of the files of a directory and its subdirectories, sorted.
+	List(dir string) ([]string, error)
+
+	// Location returns the location of a file, as displayed to the users and passed to the hooks.
+	Location(name string) string
+
+	// Lock takes an advisory lock, identified by the name of a lock file, to serialize the lego processes.
+	// The lock is released by calling the returned function.
+	// The remote storages don't support locking: the lock is a no-op, and a warning is logged (see unsupportedLock).
+	Lock(name string) (func() error, error)
+
+	// RLock takes a shared advisory lock: the lego processes holding the shared lock run concurrently,
+	// the exclusive lock (Lock) waits for all of them.
+	RLock(name string) (func() error, error)
+
+	// TryLock takes the exclusive lock (Lock) without waiting.
+	// Returns false if the lock is held by another lego process.
+	TryLock(name string) (func() error, bool, error)
+}
+
+// newStorage creates the storage selected by the "storage" option,
+// and returns the root directory of the lego files in the storage.
+func newStorage(ctx *cli.Context) (Storage, string) {
+	storage, root, err := openStorage(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return storage, root
+}
+
+// openStorage creates the storage selected by the "storage" option,
+// and returns the root directory of the lego files in the storage.
+func openStorage(ctx *cli.Context) (Storage, string, error) {
+	raw := ctx.String(flgStorage)
+	if raw == "" {
+		return &fileStorage{}, ctx.String(flgPath), nil
+	}
+
+	uri, err := url.Parse(raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid storage %q: %w", raw, err)
+	}
+
+	var storage Storage
+
+	switch uri.Scheme {
+	case "file":
+		return &fileStorage{}, filepath.FromSlash(uri.Path), nil
+
+	case "s3":
+		storage, err = newS3Storage(uri)
+
+	case "consul":
+		storage = newKVStorage(newConsulKV(uri), uri)
+
+	default:
+		err = fmt.Errorf("unsupported scheme %q", uri.Scheme)
+	}
+
+	if err != nil {
+		return nil, "", fmt.Errorf("could not create the storage %q: %w", raw, err)
+	}
+
+	return storage, "", nil
+}
+
+// unsupportedLock the locks of a remote storage, which doesn't support locking:
+// the locks are no-ops, a warning is logged the first time a lock is taken.
+type unsupportedLock struct {
+	location string
+	once     sync.Once
+}
+
+func (l *unsupportedLock) Lock(_ string) (func() error, error) {
+	l.once.Do(func() {
+		log.Warnf("The storage %s doesn't support locking: avoid concurrent lego processes with the same storage.", l.location)
+	})
+
+	return func() error { return nil }, nil
+}
+
+func (l *unsupportedLock) RLock(name string) (func() error, error) {
+	return l.Lock(name)
+}
+
+func (l *unsupportedLock) TryLock(name string) (func() error, bool, error) {
+	unlock, err := l.Lock(name)
+
+	return unlock, err == nil, err
+}
+
+const (
+	// setsDirName the directory of the sets of files, in the directory of the files (see fileStorage.WriteFiles).
+	setsDirName = ".sets"
+
+	// currentLinkName the symbolic link to the current version of a set.
+	currentLinkName = "current"
+
+	// linksDirExt the extension of the hidden directories of links (see fileStorage.SymlinkDir).
+	linksDirExt = ".links"
+)
+
+// fileStorage stores the files in the local filesystem.
+type fileStorage struct{}
+
+func (s *fileStorage) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(name)
+}
+
+// WriteFile writes the file to a temporary file, and renames it: the rename of a file is atomic.
+func (s *fileStorage) WriteFile(name string, data []byte) error {
+	return writeFilesOneByOne(map[string][]byte{name: data})
+}
+
+// WriteFiles writes the files of a same directory as a set:
+// the files are written to a new version directory, and the version is swapped in with a single rename of a symbolic link.
+//
+//	./certificates/example.com.crt -> .sets/example.com.crt/current/example.com.crt
+//	./certificates/.sets/example.com.crt/current -> v123456
+//
+// The set is named after its first file, the previous versions of the set are removed.
+func (s *fileStorage) WriteFiles(files map[string][]byte) error {
+	if !symlinkSets || len(files) == 1 {
+		return writeFilesOneByOne(files)
+	}
+
+	dirs := make(map[string]map[string][]byte)
+
+	for name, data := range files {
+		dir := filepath.Dir(name)
+
+		if dirs[dir] == nil {
+			dirs[dir] = make(map[string][]byte)
+		}
+
+		dirs[dir][filepath.Base(name)] = data
+	}
+
+	// The sets of different directories are swapped one after the other.
+	for _, dir := range slices.Sorted(maps.Keys(dirs)) {
+		err := s.writeSet(dir, dirs[dir])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeSet writes a set of files (by base name) to a new version directory, and swaps in the version.
+func (s *fileStorage) writeSet(dir string, files map[string][]byte) error {
+	set := slices.Min(slices.Collect(maps.Keys(files)))
+
+	setDir := filepath.Join(dir, setsDirName, set)
+
+	err := createNonExistingFolder(setDir)
+	if err != nil {
+		return err
+	}
+
+	previous, err := setFiles(setDir)
+	if err != nil {
+		return err
+	}
+
+	version, err := os.MkdirTemp(setDir, "v")
+	if err != nil {
+		return err
+	}
+
+	err = writeVersion(version, files)
+	if err != nil {
+		_ = os.RemoveAll(version)
+		return err
+	}
+
+	err = s.Symlink(filepath.Base(version), filepath.Join(setDir, currentLinkName))
+	if err != nil {
+		_ = os.RemoveAll(version)
+		return err
+	}
+
+	// The links of the files are created once: they always point to the current version of the set.
+	for _, base := range slices.Sorted(maps.Keys(files)) {
+		err = s.linkToSet(dir, set, base)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The files of the previous version which are not in the new version are removed.
+	for _, base := range previous {
+		if _, ok := files[base]; ok {
+			continue
+		}
+
+		if linkedSet, ok := setOfLink(filepath.Join(dir, base)); ok && linkedSet == set {
+			err = os.Remove(filepath.Join(dir, base))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = syncDir(dir)
+	if err != nil {
+		return err
+	}
+
+	return removeVersions(setDir, filepath.Base(version))
+}
+
+// linkToSet links a file to the current version of a set, if the file is not already linked to it.
+func (s *fileStorage) linkToSet(dir, set, base string) error {
+	name := filepath.Join(dir, base)
+
+	previousSet, linked := setOfLink(name)
+	if linked && previousSet == set {
+		return nil
+	}
+
+	err := s.Symlink(filepath.Join(setsDirName, set, currentLinkName, base), name)
+	if err != nil {
+		return err
+	}
+
+	if linked {
+		// The file was part of another set.
+		return removeUnusedSet(dir, previousSet)
+	}
+
+	return nil
+}
+
+func (s *fileStorage) Remove(name string) error {
+	set, linked := setOfLink(name)
+
+	err := os.Remove(name)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if linked {
+		return removeUnusedSet(filepath.Dir(name), set)
+	}
+
+	return nil
+}
+
+func (s *fileStorage) Rename(oldName, newName string) error {
+	if _, linked := setOfLink(oldName); linked {
+		// The links of the sets are relative to their directory: the content of the file is moved.
+		data, err := os.ReadFile(oldName)
+		if err != nil {
+			return err
+		}
+
+		err = s.WriteFile(newName, data)
+		if err != nil {
+			return err
+		}
+
+		return s.Remove(oldName)
+	}
+
+	err := createNonExistingFolder(filepath.Dir(newName))
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(oldName, newName)
+	if err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(newName))
+}
+
+func (s *fileStorage) List(dir string) ([]string, error) {
+	var names []string
+
+	err := filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() && entry.Name() == setsDirName {
+			return fs.SkipDir
+		}
+
+		if !entry.IsDir() {
+			names = append(names, name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func (s *fileStorage) Location(name string) string {
+	return name
+}
+
+func (s *fileStorage) Lock(name string) (func() error, error) {
+	return s.lock(name, true)
+}
+
+func (s *fileStorage) RLock(name string) (func() error, error) {
+	return s.lock(name, false)
+}
+
+func (s *fileStorage) TryLock(name string) (func() error, bool, error) {
+	file, err := openLockFile(name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ok, err := tryLockFile(file, true)
+	if err != nil || !ok {
+		_ = file.Close()
+		return nil, false, err
+	}
+
+	return unlockFunc(file), true, nil
+}
+
+func (s *fileStorage) lock(name string, exclusive bool) (func() error, error) {
+	file, err := openLockFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := tryLockFile(file, exclusive)
+	if err == nil && !ok {
+		log.Infof("Waiting for the lock %s, another lego process is running.", name)
+
+		err = lockFile(file, exclusive)
+	}
+
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return unlockFunc(file), nil
+}
+
+func openLockFile(name string) (*os.File, error) {
+	err := createNonExistingFolder(filepath.Dir(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(name, os.O_CREATE|os.O_RDWR, filePerm)
+}
+
+// unlockFunc returns the function releasing the lock.
+// The lock file is not removed: another process can be waiting for it.
+func unlockFunc(file *os.File) func() error {
+	return func() error {
+		defer func() { _ = file.Close() }()
+
+		return unlockFile(file)
+	}
+}
+
+// SymlinkDir creates the links in a new hidden directory, and swaps it in with a symbolic link:
+// the links are replaced together, and the links which are not in the new directory are removed.
+//
+//	./live/example.com -> .example.com.123456.links
+//	./live/.example.com.123456.links/cert.pem -> ../../versions/example.com/cert2.pem
+//
+// The targets are relative to the directory: the hidden directory is in the same parent directory.
+// Implements linkStorage.
+func (s *fileStorage) SymlinkDir(dir string, links map[string]string) error {
+	parent := filepath.Dir(dir)
+
+	err := createNonExistingFolder(parent)
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.MkdirTemp(parent, "."+filepath.Base(dir)+".*"+linksDirExt)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(links)) {
+		err = os.Symlink(links[name], filepath.Join(temp, name))
+		if err != nil {
+			_ = os.RemoveAll(temp)
+			return err
+		}
+	}
+
+	err = syncDir(temp)
+	if err != nil {
+		_ = os.RemoveAll(temp)
+		return err
+	}
+
+	// A directory cannot be replaced by a symbolic link.
+	if info, errS := os.Lstat(dir); errS == nil && info.IsDir() {
+		err = os.RemoveAll(dir)
+		if err != nil {
+			_ = os.RemoveAll(temp)
+			return err
+		}
+	}
+
+	err = s.Symlink(filepath.Base(temp), dir)
+	if err != nil {
+		_ = os.RemoveAll(temp)
+		return err
+	}
+
+	return removeLinkDirs(dir, filepath.Base(temp))
+}
+
+// RemoveSymlinkDir removes the symbolic link of the directory, and the hidden directory of the links.
+// Implements linkStorage.
+func (s *fileStorage) RemoveSymlinkDir(dir string) error {
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return err
+	}
+
+	err = removeLinkDirs(dir, "")
+	if err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(dir))
+}
+
+// Symlink creates a temporary symbolic link and renames it, the link is replaced atomically.
+func (s *fileStorage) Symlink(target, name string) error {
+	err := createNonExistingFolder(filepath.Dir(name))
+	if err != nil {
+		return err
+	}
+
+	temp := filepath.Join(filepath.Dir(name), fmt.Sprintf(".%s.%d.tmp", filepath.Base(name), os.Getpid()))
+
+	_ = os.Remove(temp)
+
+	err = os.Symlink(target, temp)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(temp, name)
+	if err != nil {
+		_ = os.Remove(temp)
+		return err
+	}
+
+	return syncDir(filepath.Dir(name))
+}
+
+// writeFilesOneByOne writes all the files to temporary files, and then renames them one by one.
+func writeFilesOneByOne(files map[string][]byte) error {
+	temps := make(map[string]string)
+
+	defer func() {
+		// Removes the temporary files which have not been renamed.
+		for _, temp := range temps {
+			_ = os.Remove(temp)
+		}
+	}()
+
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		temp, err := writeTempFile(name, files[name])
+		if err != nil {
+			return err
+		}
+
+		temps[name] = temp
+	}
+
+	dirs := make(map[string]struct{})
+
+	for _, name := range slices.Sorted(maps.Keys(temps)) {
+		set, linked := setOfLink(name)
+
+		err := os.Rename(temps[name], name)
+		if err != nil {
+			return err
+		}
+
+		delete(temps, name)
+
+		dirs[filepath.Dir(name)] = struct{}{}
+
+		if linked {
+			// The file was part of a set.
+			err = removeUnusedSet(filepath.Dir(name), set)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, dir := range slices.Sorted(maps.Keys(dirs)) {
+		err := syncDir(dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeVersion writes the files of a version of a set, and syncs them.
+func writeVersion(version string, files map[string][]byte) error {
+	for base, data := range files {
+		file, err := os.OpenFile(filepath.Join(version, base), os.O_CREATE|os.O_WRONLY|os.O_EXCL, filePerm)
+		if err != nil {
+			return err
+		}
+
+		_, err = file.Write(data)
+		if err == nil {
+			err = file.Sync()
+		}
+
+		if errC := file.Close(); err == nil {
+			err = errC
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return syncDir(version)
+}
+
+// setFiles returns the base names of the files of the current version of a set.
+func setFiles(setDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(setDir, currentLinkName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	return names, nil
+}
+
+// setOfLink returns the set of a file, if the file is a link to a set.
+func setOfLink(name string) (string, bool) {
+	target, err := os.Readlink(name)
+	if err != nil {
+		return "", false
+	}
+
+	parts := strings.Split(filepath.ToSlash(target), "/")
+	if len(parts) != 4 || parts[0] != setsDirName || parts[2] != currentLinkName {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+// removeVersions removes the versions of a set, except the current version.
+func removeVersions(setDir, current string) error {
+	entries, err := os.ReadDir(setDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Name() == current || entry.Name() == currentLinkName || !entry.IsDir() {
+			continue
+		}
+
+		err = os.RemoveAll(filepath.Join(setDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeLinkDirs removes the hidden directories of the links of a directory (see fileStorage.SymlinkDir), except the current one.
+func removeLinkDirs(dir, current string) error {
+	entries, err := os.ReadDir(filepath.Dir(dir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	prefix := "." + filepath.Base(dir) + "."
+
+	for _, entry := range entries {
+		if entry.Name() == current || !entry.IsDir() {
+			continue
+		}
+
+		// The random part of the name of the hidden directory is a number (os.MkdirTemp).
+		random, ok := strings.CutSuffix(strings.TrimPrefix(entry.Name(), prefix), linksDirExt)
+		if !ok || !strings.HasPrefix(entry.Name(), prefix) || random == "" || strings.Trim(random, "0123456789") != "" {
+			continue
+		}
+
+		err = os.RemoveAll(filepath.Join(filepath.Dir(dir), entry.Name()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeUnusedSet removes a set when none of the files of the directory is linked to it anymore.
+func removeUnusedSet(dir, set string) error {
+	setDir := filepath.Join(dir, setsDirName, set)
+
+	names, err := setFiles(setDir)
+	if err != nil {
+		return err
+	}
+
+	for _, base := range names {
+		if linkedSet, ok := setOfLink(filepath.Join(dir, base)); ok && linkedSet == set {
+			return nil
+		}
+	}
+
+	err = os.RemoveAll(setDir)
+	if err != nil {
+		return err
+	}
+
+	// The directory of the sets is removed when it's empty.
+	_ = os.Remove(filepath.Join(dir, setsDirName))
+
+	return nil
+}
+
+// writeTempFile writes the data to a temporary file, in the directory of the file, and syncs it.
+func writeTempFile(name string, data []byte) (string, error) {
+	err := createNonExistingFolder(filepath.Dir(name))
+	if err != nil {
+		return "", err
+	}
+
+	// The permissions of the temporary file are 0o600 (filePerm).
+	file, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if errC := file.Close(); err == nil {
+		err = errC
+	}
+
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// storageKey converts a file name to a key of a remote storage.
+func storageKey(prefix, name string) string {
+	return strings.Trim(path.Join(prefix, filepath.ToSlash(name)), "/")
+}
+
+// storageName converts a key of a remote storage to a file name.
+func storageName(prefix, key string) string {
+	return filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(key, prefix), "/"))
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keyValueStore a key-value store used by kvStorage.
+type keyValueStore interface {
+	// Get returns the value of a key, the error wraps os.ErrNotExist if the key doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put sets the value of a key.
+	Put(ctx context.Context, key string, value []byte) error
+
+	// Delete deletes a key, it's not an error if the key doesn't exist.
+	Delete(ctx context.Context, key string) error
+
+	// Keys returns the keys starting with the prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// kvStorage stores the files in a key-value store: one key per file.
+type kvStorage struct {
+	unsupportedLock
+
+	store  keyValueStore
+	host   string
+	prefix string
+}
+
+func newKVStorage(store keyValueStore, uri *url.URL) *kvStorage {
+	s := &kvStorage{
+		store:  store,
+		host:   uri.Scheme + "://" + uri.Host,
+		prefix: strings.Trim(uri.Path, "/"),
+	}
+
+	s.location = s.Location("")
+
+	return s
+}
+
+func (s *kvStorage) ReadFile(name string) ([]byte, error) {
+	return s.store.Get(context.Background(), storageKey(s.prefix, name))
+}
+
+func (s *kvStorage) WriteFile(name string, data []byte) error {
+	return s.store.Put(context.Background(), storageKey(s.prefix, name), data)
+}
+
+func (s *kvStorage) WriteFiles(files map[string][]byte) error {
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		err := s.WriteFile(name, files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *kvStorage) Remove(name string) error {
+	return s.store.Delete(context.Background(), storageKey(s.prefix, name))
+}
+
+func (s *kvStorage) Rename(oldName, newName string) error {
+	data, err := s.ReadFile(oldName)
+	if err != nil {
+		return err
+	}
+
+	err = s.WriteFile(newName, data)
+	if err != nil {
+		return err
+	}
+
+	return s.Remove(oldName)
+}
+
+func (s *kvStorage) List(dir string) ([]string, error) {
+	prefix := storageKey(s.prefix, dir)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	keys, err := s.store.Keys(context.Background(), prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, key := range keys {
+		names = append(names, storageName(s.prefix, key))
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func (s *kvStorage) Location(name string) string {
+	return s.host + "/" + storageKey(s.prefix, name)
+}
+
+// consulKV a client of the Consul KV HTTP API.
+// The token and the TLS are defined by the Consul environment variables (CONSUL_HTTP_TOKEN, CONSUL_HTTP_SSL).
+//
+//	consul://localhost:8500/prefix
+//
+// https://developer.hashicorp.com/consul/api-docs/kv
+type consulKV struct {
+	baseURL    *url.URL
+	token      string
+	httpClient *http.Client
+}
+
+func newConsulKV(uri *url.URL) *consulKV {
+	scheme := "http"
+	if ssl, _ := strconv.ParseBool(os.Getenv("CONSUL_HTTP_SSL")); ssl {
+		scheme = "https"
+	}
+
+	return &consulKV{
+		baseURL:    &url.URL{Scheme: scheme, Host: uri.Host, Path: "/v1/kv/"},
+		token:      os.Getenv("CONSUL_HTTP_TOKEN"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *consulKV) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, "raw", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("consul: %s: %w", key, os.ErrNotExist)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func (c *consulKV) Put(ctx context.Context, key string, value []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, key, "", value)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (c *consulKV) Delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (c *consulKV) Keys(ctx context.Context, prefix string) ([]string, error) {
+	resp, err := c.do(ctx, http.MethodGet, prefix, "keys", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	// No keys.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	var keys []string
+
+	err = json.NewDecoder(resp.Body).Decode(&keys)
+	if err != nil {
+		return nil, fmt.Errorf("consul: %s: %w", prefix, err)
+	}
+
+	return keys, nil
+}
+
+// do sends a request, the responses with an error status (except 404) are returned as errors.
+func (c *consulKV) do(ctx context.Context, method, key, query string, body []byte) (*http.Response, error) {
+	endpoint := c.baseURL.JoinPath(key)
+	endpoint.RawQuery = query
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("consul: %s: %w", key, err)
+	}
+
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("consul: %s: %w", key, err)
+	}
+
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		raw, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		return nil, fmt.Errorf("consul: %s: unexpected status code: %d: %s", key, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulKV(t *testing.T) {
+	t.Setenv("CONSUL_HTTP_TOKEN", "secret")
+
+	var tokens []string
+
+	fake := newFakeConsul(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		tokens = append(tokens, req.Header.Get("X-Consul-Token"))
+
+		fake.ServeHTTP(rw, req)
+	}))
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(strings.Replace(server.URL, "http://", "consul://", 1) + "/lego")
+	require.NoError(t, err)
+
+	kv := newConsulKV(uri)
+
+	_, err = kv.Get(t.Context(), "lego/example.com.crt")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	keys, err := kv.Keys(t.Context(), "lego/")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	err = kv.Put(t.Context(), "lego/example.com.crt", []byte("cert"))
+	require.NoError(t, err)
+
+	value, err := kv.Get(t.Context(), "lego/example.com.crt")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cert"), value)
+
+	keys, err = kv.Keys(t.Context(), "lego/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lego/example.com.crt"}, keys)
+
+	err = kv.Delete(t.Context(), "lego/example.com.crt")
+	require.NoError(t, err)
+
+	_, err = kv.Get(t.Context(), "lego/example.com.crt")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	for _, token := range tokens {
+		assert.Equal(t, "secret", token)
+	}
+}
+
+func TestConsulKV_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		http.Error(rw, "Permission denied", http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(strings.Replace(server.URL, "http://", "consul://", 1))
+	require.NoError(t, err)
+
+	kv := newConsulKV(uri)
+
+	_, err = kv.Get(t.Context(), "lego/example.com.crt")
+	require.EqualError(t, err, "consul: lego/example.com.crt: unexpected status code: 403: Permission denied")
+
+	err = kv.Put(t.Context(), "lego/example.com.crt", []byte("cert"))
+	require.EqualError(t, err, "consul: lego/example.com.crt: unexpected status code: 403: Permission denied")
+}
+
+func TestKVStorage_Lock(t *testing.T) {
+	uri, _ := url.Parse("memory://kv/lego")
+
+	storage := newKVStorage(&memoryKV{values: map[string][]byte{}}, uri)
+
+	assert.Equal(t, "memory://kv/lego", storage.location)
+
+	// The storage doesn't support locking: the locks are not exclusive.
+	unlock, err := storage.Lock("accounts/account.lock")
+	require.NoError(t, err)
+
+	_, ok, err := storage.TryLock("accounts/account.lock")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, unlock())
+}
+
+func TestCertificatesStorage_keyValue(t *testing.T) {
+	kv := &memoryKV{values: map[string][]byte{}}
+
+	uri, _ := url.Parse("memory:///lego")
+
+	storage := &CertificatesStorage{
+		storage:     newKVStorage(kv, uri),
+		rootPath:    baseCertificatesFolderName,
+		archivePath: baseArchivesFolderName,
+	}
+
+	storage.SaveResource(&certificate.Resource{
+		Domain:      "*.example.com",
+		Certificate: []byte("cert"),
+		PrivateKey:  []byte("key"),
+	})
+
+	assert.Equal(t, []string{"lego/certificates/_.example.com.crt", "lego/certificates/_.example.com.json", "lego/certificates/_.example.com.key"}, kv.keys())
+	assert.Equal(t, "memory:///lego/certificates/_.example.com.crt", storage.GetFileName("*.example.com", certExt))
+
+	names, err := storage.ListCertificates()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"_.example.com"}, names)
+
+	err = storage.MoveToArchive("*.example.com")
+	require.NoError(t, err)
+
+	for _, key := range kv.keys() {
+		assert.Regexp(t, `^lego/archives/\d+\._\.example\.com\.(crt|json|key)$`, key)
+	}
+}
+
+// memoryKV an in-memory key-value store.
+type memoryKV struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func (m *memoryKV) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.values[key]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", key, os.ErrNotExist)
+	}
+
+	return value, nil
+}
+
+func (m *memoryKV) Put(_ context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[key] = value
+
+	return nil
+}
+
+func (m *memoryKV) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.values, key)
+
+	return nil
+}
+
+func (m *memoryKV) Keys(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for _, key := range m.keys() {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+func (m *memoryKV) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []string
+	for key := range m.values {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// newFakeConsul creates a handler implementing the Consul KV HTTP API.
+func newFakeConsul(t *testing.T) http.Handler {
+	t.Helper()
+
+	kv := &memoryKV{values: map[string][]byte{}}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		key, ok := strings.CutPrefix(req.URL.Path, "/v1/kv/")
+		if !ok {
+			http.NotFound(rw, req)
+			return
+		}
+
+		switch {
+		case req.Method == http.MethodGet && req.URL.Query().Has("keys"):
+			keys, _ := kv.Keys(req.Context(), key)
+			if len(keys) == 0 {
+				http.NotFound(rw, req)
+				return
+			}
+
+			_ = json.NewEncoder(rw).Encode(keys)
+
+		case req.Method == http.MethodGet && req.URL.Query().Has("raw"):
+			value, err := kv.Get(req.Context(), key)
+			if err != nil {
+				http.NotFound(rw, req)
+				return
+			}
+
+			_, _ = rw.Write(value)
+
+		case req.Method == http.MethodPut:
+			value, _ := io.ReadAll(req.Body)
+			_ = kv.Put(req.Context(), key, value)
+
+			_, _ = rw.Write([]byte("true"))
+
+		case req.Method == http.MethodDelete:
+			_ = kv.Delete(req.Context(), key)
+
+			_, _ = rw.Write([]byte("true"))
+
+		default:
+			http.Error(rw, "unsupported request", http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3Storage stores the files in an S3 (or S3-compatible) bucket.
+// The credentials are read from the environment (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_PROFILE, ...).
+//
+//	s3://bucket/prefix?region=eu-west-1&endpoint=https://minio.example.com&path-style=true
+type s3Storage struct {
+	unsupportedLock
+
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func newS3Storage(uri *url.URL) (*s3Storage, error) {
+	if uri.Host == "" {
+		return nil, errors.New("s3: bucket name missing")
+	}
+
+	query := uri.Query()
+
+	var loadOptions []func(*config.LoadOptions) error
+	if region := query.Get("region"); region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("s3: unable to create AWS config: %w", err)
+	}
+
+	pathStyle, _ := strconv.ParseBool(query.Get("path-style"))
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint := query.Get("endpoint"); endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+
+		o.UsePathStyle = pathStyle
+	})
+
+	s := &s3Storage{
+		client: client,
+		bucket: uri.Host,
+		prefix: strings.Trim(uri.Path, "/"),
+	}
+
+	s.location = s.Location("")
+
+	return s, nil
+}
+
+func (s *s3Storage) ReadFile(name string) ([]byte, error) {
+	output, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(storageKey(s.prefix, name)),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("s3: %s: %w", s.Location(name), os.ErrNotExist)
+		}
+
+		return nil, fmt.Errorf("s3: %s: %w", s.Location(name), err)
+	}
+
+	defer func() { _ = output.Body.Close() }()
+
+	return io.ReadAll(output.Body)
+}
+
+func (s *s3Storage) WriteFile(name string, data []byte) error {
+	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(storageKey(s.prefix, name)),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: %s: %w", s.Location(name), err)
+	}
+
+	return nil
+}
+
+func (s *s3Storage) WriteFiles(files map[string][]byte) error {
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		err := s.WriteFile(name, files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *s3Storage) Remove(name string) error {
+	// Deleting a missing object is not an error.
+	_, err := s.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(storageKey(s.prefix, name)),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: %s: %w", s.Location(name), err)
+	}
+
+	return nil
+}
+
+func (s *s3Storage) Rename(oldName, newName string) error {
+	_, err := s.client.CopyObject(context.Background(), &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		CopySource: aws.String(url.PathEscape(s.bucket) + "/" + escapeKey(storageKey(s.prefix, oldName))),
+		Key:        aws.String(storageKey(s.prefix, newName)),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: %s: %w", s.Location(oldName), err)
+	}
+
+	return s.Remove(oldName)
+}
+
+func (s *s3Storage) List(dir string) ([]string, error) {
+	prefix := storageKey(s.prefix, dir)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	var names []string
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("s3: %s: %w", s.Location(dir), err)
+		}
+
+		for _, object := range page.Contents {
+			names = append(names, storageName(s.prefix, aws.ToString(object.Key)))
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+func (s *s3Storage) Location(name string) string {
+	return "s3://" + path.Join(s.bucket, storageKey(s.prefix, name))
+}
+
+// escapeKey escapes the segments of an object key.
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
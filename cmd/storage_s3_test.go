@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Storage(t *testing.T) {
+	fake := &fakeS3{bucket: "my-bucket", objects: map[string][]byte{}}
+
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	setupS3Env(t)
+
+	uri, err := url.Parse("s3://my-bucket/lego?region=eu-west-1&path-style=true&endpoint=" + url.QueryEscape(server.URL))
+	require.NoError(t, err)
+
+	storage, err := newS3Storage(uri)
+	require.NoError(t, err)
+
+	testStorage(t, storage, "")
+
+	assert.Equal(t, []string{"lego/accounts/example.org/account.json", "lego/certificates/example.com.key"}, fake.keys())
+
+	assert.Equal(t, "s3://my-bucket/lego/certificates/example.com.crt", storage.Location(filepath.Join("certificates", "example.com.crt")))
+	assert.Equal(t, "s3://my-bucket/lego", storage.location)
+}
+
+func TestS3Storage_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "application/xml")
+		rw.WriteHeader(http.StatusForbidden)
+
+		_, _ = io.WriteString(rw, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+	}))
+	t.Cleanup(server.Close)
+
+	setupS3Env(t)
+
+	uri, err := url.Parse("s3://my-bucket?region=eu-west-1&path-style=true&endpoint=" + url.QueryEscape(server.URL))
+	require.NoError(t, err)
+
+	storage, err := newS3Storage(uri)
+	require.NoError(t, err)
+
+	_, err = storage.ReadFile(filepath.Join("certificates", "example.com.crt"))
+	require.ErrorContains(t, err, "s3: s3://my-bucket/certificates/example.com.crt: ")
+	require.ErrorContains(t, err, "AccessDenied")
+}
+
+func Test_newS3Storage_noBucket(t *testing.T) {
+	uri, err := url.Parse("s3:///lego")
+	require.NoError(t, err)
+
+	_, err = newS3Storage(uri)
+	require.EqualError(t, err, "s3: bucket name missing")
+}
+
+// setupS3Env defines the AWS credentials, without the shared configuration files of the user.
+func setupS3Env(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "access")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	// The object bodies are sent as is (without aws-chunked encoding).
+	t.Setenv("AWS_REQUEST_CHECKSUM_CALCULATION", "when_required")
+}
+
+// fakeS3 implements the S3 API used by s3Storage, with path-style requests (/bucket/key).
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	key, ok := strings.CutPrefix(req.URL.Path, "/"+f.bucket)
+	if !ok {
+		// Virtual-hosted-style request.
+		f.writeError(rw, http.StatusBadRequest, "InvalidRequest")
+		return
+	}
+
+	key = strings.TrimPrefix(key, "/")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case req.Method == http.MethodGet && key == "" && req.URL.Query().Get("list-type") == "2":
+		f.list(rw, req.URL.Query().Get("prefix"))
+
+	case req.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			f.writeError(rw, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		_, _ = rw.Write(data)
+
+	case req.Method == http.MethodPut && req.Header.Get("X-Amz-Copy-Source") != "":
+		source, _ := url.PathUnescape(req.Header.Get("X-Amz-Copy-Source"))
+
+		data, ok := f.objects[strings.TrimPrefix(source, f.bucket+"/")]
+		if !ok {
+			f.writeError(rw, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+
+		f.objects[key] = data
+
+		_, _ = io.WriteString(rw, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+
+	case req.Method == http.MethodPut:
+		data, _ := io.ReadAll(req.Body)
+
+		f.objects[key] = data
+
+		rw.Header().Set("ETag", `"etag"`)
+
+	case req.Method == http.MethodDelete:
+		delete(f.objects, key)
+
+		rw.WriteHeader(http.StatusNoContent)
+
+	default:
+		f.writeError(rw, http.StatusBadRequest, "InvalidRequest")
+	}
+}
+
+func (f *fakeS3) list(rw http.ResponseWriter, prefix string) {
+	type content struct {
+		Key string `xml:"Key"`
+	}
+
+	result := struct {
+		XMLName     xml.Name  `xml:"ListBucketResult"`
+		Name        string    `xml:"Name"`
+		Prefix      string    `xml:"Prefix"`
+		KeyCount    int       `xml:"KeyCount"`
+		IsTruncated bool      `xml:"IsTruncated"`
+		Contents    []content `xml:"Contents"`
+	}{Name: f.bucket, Prefix: prefix}
+
+	for key := range f.objects {
+		if strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, content{Key: key})
+		}
+	}
+
+	result.KeyCount = len(result.Contents)
+
+	rw.Header().Set("Content-Type", "application/xml")
+
+	_ = xml.NewEncoder(rw).Encode(result)
+}
+
+func (f *fakeS3) writeError(rw http.ResponseWriter, status int, code string) {
+	rw.Header().Set("Content-Type", "application/xml")
+	rw.WriteHeader(status)
+
+	_, _ = io.WriteString(rw, "<Error><Code>"+code+"</Code><Message>"+code+"</Message></Error>")
+}
+
+func (f *fakeS3) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var keys []string
+	for key := range f.objects {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
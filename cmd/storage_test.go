@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		storage func(t *testing.T) (Storage, string)
+	}{
+		{
+			desc: "file",
+			storage: func(t *testing.T) (Storage, string) {
+				t.Helper()
+
+				return &fileStorage{}, t.TempDir()
+			},
+		},
+		{
+			desc: "key-value",
+			storage: func(t *testing.T) (Storage, string) {
+				t.Helper()
+
+				uri, _ := url.Parse("memory:///lego")
+
+				return newKVStorage(&memoryKV{values: map[string][]byte{}}, uri), ""
+			},
+		},
+		{
+			desc: "consul",
+			storage: func(t *testing.T) (Storage, string) {
+				t.Helper()
+
+				server := httptest.NewServer(newFakeConsul(t))
+				t.Cleanup(server.Close)
+
+				uri, _ := url.Parse(strings.Replace(server.URL, "http://", "consul://", 1) + "/lego")
+
+				return newKVStorage(newConsulKV(uri), uri), ""
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			storage, root := test.storage(t)
+
+			testStorage(t, storage, root)
+		})
+	}
+}
+
+// testStorage tests the operations of a storage, the files are stored in the root directory.
+func testStorage(t *testing.T, storage Storage, root string) {
+	t.Helper()
+
+	name := filepath.Join(root, "certificates", "example.com.crt")
+
+	_, err := storage.ReadFile(name)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	names, err := storage.List(filepath.Join(root, "certificates"))
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	err = storage.WriteFile(name, []byte("cert"))
+	require.NoError(t, err)
+
+	err = storage.WriteFile(filepath.Join(root, "certificates", "example.com.key"), []byte("key"))
+	require.NoError(t, err)
+
+	err = storage.WriteFile(filepath.Join(root, "accounts", "example.org", "account.json"), []byte("{}"))
+	require.NoError(t, err)
+
+	data, err := storage.ReadFile(name)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cert"), data)
+
+	names, err = storage.List(filepath.Join(root, "certificates"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{name, filepath.Join(root, "certificates", "example.com.key")}, names)
+
+	archived := filepath.Join(root, "archives", "1.example.com.crt")
+
+	err = storage.Rename(name, archived)
+	require.NoError(t, err)
+
+	_, err = storage.ReadFile(name)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	data, err = storage.ReadFile(archived)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cert"), data)
+
+	err = storage.Remove(archived)
+	require.NoError(t, err)
+
+	err = storage.Remove(archived)
+	require.NoError(t, err)
+
+	names, err = storage.List(root)
+	require.NoError(t, err)
+
+	expected := []string{
+		filepath.Join(root, "accounts", "example.org", "account.json"),
+		filepath.Join(root, "certificates", "example.com.key"),
+	}
+	assert.Equal(t, expected, names)
+}
+
+func TestFileStorage_WriteFiles(t *testing.T) {
+	if !symlinkSets {
+		t.Skip("the files are renamed one by one")
+	}
+
+	dir := t.TempDir()
+
+	storage := &fileStorage{}
+
+	cert := filepath.Join(dir, "example.com.crt")
+	key := filepath.Join(dir, "example.com.key")
+
+	err := storage.WriteFiles(map[string][]byte{cert: []byte("cert1"), key: []byte("key1")})
+	require.NoError(t, err)
+
+	err = storage.WriteFiles(map[string][]byte{cert: []byte("cert2"), key: []byte("key2")})
+	require.NoError(t, err)
+
+	// The files point to the current version of the set.
+	for name, expected := range map[string]string{cert: "cert2", key: "key2"} {
+		target, errR := os.Readlink(name)
+		require.NoError(t, errR)
+		assert.Equal(t, filepath.Join(setsDirName, "example.com.crt", currentLinkName, filepath.Base(name)), target)
+
+		data, errR := storage.ReadFile(name)
+		require.NoError(t, errR)
+		assert.Equal(t, expected, string(data))
+	}
+
+	// The previous version is removed.
+	versions, err := os.ReadDir(filepath.Join(dir, setsDirName, "example.com.crt"))
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+
+	names, err := storage.List(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{cert, key}, names)
+
+	// The file which is not in the new version is removed.
+	err = storage.WriteFiles(map[string][]byte{cert: []byte("cert3"), filepath.Join(dir, "example.com.json"): []byte("{}")})
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, key)
+
+	// The content of a moved file is moved.
+	archived := filepath.Join(dir, "archives", "1.example.com.crt")
+
+	err = storage.Rename(cert, archived)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(archived)
+	require.NoError(t, err)
+	assert.Equal(t, "cert3", string(data))
+
+	// The set is removed with its last file.
+	err = storage.Remove(filepath.Join(dir, "example.com.json"))
+	require.NoError(t, err)
+
+	assert.NoDirExists(t, filepath.Join(dir, setsDirName))
+}
+
+func TestFileStorage_TryLock(t *testing.T) {
+	storage := &fileStorage{}
+
+	name := filepath.Join(t.TempDir(), "account.lock")
+
+	runlock, err := storage.RLock(name)
+	require.NoError(t, err)
+
+	_, ok, err := storage.TryLock(name)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, runlock())
+
+	unlock, ok, err := storage.TryLock(name)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, unlock())
+}
+
+func TestFileStorage_RLock(t *testing.T) {
+	storage := &fileStorage{}
+
+	name := filepath.Join(t.TempDir(), "account.lock")
+
+	unlock, err := storage.RLock(name)
+	require.NoError(t, err)
+
+	// Another "process" (another open file description).
+	file, err := os.OpenFile(name, os.O_RDWR, 0)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = file.Close() })
+
+	// The shared lock is held by several processes, the exclusive lock waits for them.
+	ok, err := tryLockFile(file, false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, unlockFile(file))
+
+	ok, err = tryLockFile(file, true)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, unlock())
+
+	ok, err = tryLockFile(file, true)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	require.NoError(t, unlockFile(file))
+}
+
+func Test_storageKey(t *testing.T) {
+	assert.Equal(t, "certificates/example.com.crt", storageKey("", filepath.Join("certificates", "example.com.crt")))
+	assert.Equal(t, "lego/certificates/example.com.crt", storageKey("lego", filepath.Join("certificates", "example.com.crt")))
+
+	assert.Equal(t, filepath.Join("certificates", "example.com.crt"), storageName("lego", "lego/certificates/example.com.crt"))
+	assert.Equal(t, filepath.Join("certificates", "example.com.crt"), storageName("", "certificates/example.com.crt"))
+}
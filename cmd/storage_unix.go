@@ -0,0 +1,24 @@
+//go:build !windows
+
+package cmd
+
+import "os"
+
+// symlinkSets the sets of files are swapped in with a symbolic link (see fileStorage.WriteFiles).
+const symlinkSets = true
+
+// syncDir syncs a directory, to persist the creation, the removal, and the renaming of its entries.
+func syncDir(dir string) error {
+	file, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	err = file.Sync()
+
+	if errC := file.Close(); err == nil {
+		err = errC
+	}
+
+	return err
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package cmd
+
+// symlinkSets the symbolic links require specific privileges on Windows: the files are renamed one by one.
+const symlinkSets = false
+
+// syncDir the directories cannot be synced on Windows.
+func syncDir(_ string) error {
+	return nil
+}
@@ -56,6 +56,13 @@ func generate() error {
 		{"lego", "help", "renew"},
 		{"lego", "help", "revoke"},
 		{"lego", "help", "list"},
+		{"lego", "help", "keychange"},
+		{"lego", "help", "preauthorize"},
+		{"lego", "help", "dnspersist"},
+		{"lego", "help", "daemon"},
+		{"lego", "archive", "help", "list"},
+		{"lego", "archive", "help", "restore"},
+		{"lego", "help", "check"},
 		{"lego", "dnshelp"},
 	} {
 		content, err := run(app, args)
@@ -50,6 +50,8 @@ func NewClient(config *Config) (*Client, error) {
 		return nil, err
 	}
 
+	core.SetRetryPolicy(config.RetryPolicy)
+
 	solversManager := resolver.NewSolversManager(core)
 
 	prober := resolver.NewProber(solversManager)
@@ -59,6 +61,8 @@ func NewClient(config *Config) (*Client, error) {
 		Timeout:             config.Certificate.Timeout,
 		OverallRequestLimit: config.Certificate.OverallRequestLimit,
 		DisableCommonName:   config.Certificate.DisableCommonName,
+		OrderStore:          config.Certificate.OrderStore,
+		Notifier:            config.Certificate.Notifier,
 	}
 
 	certifier := certificate.NewCertifier(core, prober, options)
@@ -11,7 +11,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-acme/lego/v4/acme/api"
 	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
 	"github.com/go-acme/lego/v4/registration"
 )
 
@@ -46,6 +48,8 @@ type Config struct {
 	UserAgent   string
 	HTTPClient  *http.Client
 	Certificate CertificateConfig
+	// RetryPolicy allows retrying the requests rejected by a rate limit (optional).
+	RetryPolicy *api.RetryPolicy
 }
 
 func NewConfig(user registration.User) *Config {
@@ -65,6 +69,10 @@ type CertificateConfig struct {
 	Timeout             time.Duration
 	OverallRequestLimit int
 	DisableCommonName   bool
+	// OrderStore allows resuming the in-flight orders after a crash or a timeout (optional).
+	OrderStore certificate.OrderStore
+	// Notifier receives the events of the certificates: issued, renewed, failed (optional).
+	Notifier certificate.Notifier
 }
 
 // createDefaultHTTPClient Creates an HTTP client with a reasonable timeout value
@@ -0,0 +1,232 @@
+package acmeserver
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-jose/go-jose/v4"
+)
+
+type account struct {
+	id         string
+	key        *jose.JSONWebKey
+	thumbprint string
+
+	Status  string
+	Contact []string
+
+	orderIDs []string
+}
+
+func (a *account) url(base string) string {
+	return base + accountPath + a.id
+}
+
+func (a *account) toACME(base string) acme.Account {
+	return acme.Account{
+		Status:  a.Status,
+		Contact: a.Contact,
+		Orders:  a.url(base) + "/orders",
+	}
+}
+
+func (s *Server) handleNewAccount(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, true)
+	if !ok {
+		return
+	}
+
+	if req.jwk == nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "newAccount requests must be signed by an embedded key (jwk)"))
+		return
+	}
+
+	var msg acme.Account
+
+	err := json.Unmarshal(req.payload, &msg)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the account: %v", err))
+		return
+	}
+
+	tp, err := thumbprint(req.jwk)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.BadPublicKeyErr, "unable to compute the thumbprint of the key: %v", err))
+		return
+	}
+
+	base := baseURL(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing := s.findAccountByThumbprint(tp); existing != nil {
+		w.Header().Set("Location", existing.url(base))
+		writeJSON(w, http.StatusOK, existing.toACME(base))
+
+		return
+	}
+
+	if msg.OnlyReturnExisting {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.AccountDoesNotExistErr, "no account exists with the provided key"))
+		return
+	}
+
+	acc := &account{
+		id:         s.newID(),
+		key:        req.jwk,
+		thumbprint: tp,
+		Status:     acme.StatusValid,
+		Contact:    msg.Contact,
+	}
+
+	s.accounts[acc.id] = acc
+
+	w.Header().Set("Location", acc.url(base))
+	writeJSON(w, http.StatusCreated, acc.toACME(base))
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	if req.account.id != r.PathValue("id") {
+		writeProblem(w, newProblem(http.StatusUnauthorized, acme.UnauthorizedErr, "the request is not signed by the account"))
+		return
+	}
+
+	var msg acme.Account
+
+	if !req.postAsGet() {
+		err := json.Unmarshal(req.payload, &msg)
+		if err != nil {
+			writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the account: %v", err))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg.Contact != nil {
+		req.account.Contact = msg.Contact
+	}
+
+	if msg.Status == acme.StatusDeactivated {
+		req.account.Status = acme.StatusDeactivated
+	}
+
+	writeJSON(w, http.StatusOK, req.account.toACME(baseURL(r)))
+}
+
+func (s *Server) handleAccountOrders(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	if req.account.id != r.PathValue("id") {
+		writeProblem(w, newProblem(http.StatusUnauthorized, acme.UnauthorizedErr, "the request is not signed by the account"))
+		return
+	}
+
+	base := baseURL(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := acme.OrdersList{Orders: []string{}}
+	for _, id := range req.account.orderIDs {
+		list.Orders = append(list.Orders, s.orders[id].url(base))
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleKeyChange(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	inner, err := jose.ParseSigned(string(req.payload), signatureAlgorithms)
+	if err != nil || len(inner.Signatures) != 1 {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the inner JWS: %v", err))
+		return
+	}
+
+	header := inner.Signatures[0].Protected
+
+	innerURL, _ := header.ExtraHeaders[jose.HeaderKey("url")].(string)
+	if header.JSONWebKey == nil || innerURL != baseURL(r)+r.URL.Path {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "the inner JWS must contain a jwk and the same url as the outer JWS"))
+		return
+	}
+
+	payload, err := inner.Verify(header.JSONWebKey)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "inner JWS verification error: %v", err))
+		return
+	}
+
+	var msg acme.KeyChangeMessage
+
+	err = json.Unmarshal(payload, &msg)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the key change: %v", err))
+		return
+	}
+
+	var oldKey jose.JSONWebKey
+
+	err = json.Unmarshal(msg.OldKey, &oldKey)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the old key: %v", err))
+		return
+	}
+
+	oldTP, errO := thumbprint(&oldKey)
+	newTP, errN := thumbprint(header.JSONWebKey)
+
+	if errO != nil || errN != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.BadPublicKeyErr, "unable to compute the thumbprints of the keys"))
+		return
+	}
+
+	base := baseURL(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg.Account != req.account.url(base) || oldTP != req.account.thumbprint {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "the key change doesn't match the account"))
+		return
+	}
+
+	if existing := s.findAccountByThumbprint(newTP); existing != nil {
+		w.Header().Set("Location", existing.url(base))
+		writeProblem(w, newProblem(http.StatusConflict, acme.MalformedErr, "the new key is already in use for a different account"))
+
+		return
+	}
+
+	req.account.key = header.JSONWebKey
+	req.account.thumbprint = newTP
+
+	writeJSON(w, http.StatusOK, req.account.toACME(base))
+}
+
+// findAccountByThumbprint returns the account using the key.
+// The caller must hold the lock.
+func (s *Server) findAccountByThumbprint(tp string) *account {
+	for _, acc := range s.accounts {
+		if acc.thumbprint == tp {
+			return acc
+		}
+	}
+
+	return nil
+}
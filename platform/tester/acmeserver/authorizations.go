@@ -0,0 +1,284 @@
+package acmeserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+type authorization struct {
+	id        string
+	accountID string
+
+	Status     string
+	Expires    time.Time
+	Identifier acme.Identifier
+	Wildcard   bool
+
+	challengeIDs []string
+}
+
+func (a *authorization) url(base string) string {
+	return base + authzPath + a.id
+}
+
+// failedChallenge returns the invalid challenge of the authorization, if any.
+// The caller must hold the lock.
+func (a *authorization) failedChallenge(s *Server) *challengeState {
+	for _, id := range a.challengeIDs {
+		if chlg := s.challenges[id]; chlg.Status == acme.StatusInvalid {
+			return chlg
+		}
+	}
+
+	return nil
+}
+
+type challengeState struct {
+	id      string
+	authzID string
+
+	acme.Challenge
+}
+
+func (c *challengeState) url(base string) string {
+	return base + challengePath + c.id
+}
+
+func (s *Server) toACMEAuthorization(base string, authz *authorization) acme.Authorization {
+	result := acme.Authorization{
+		Status:     authz.Status,
+		Expires:    authz.Expires,
+		Identifier: authz.Identifier,
+		Wildcard:   authz.Wildcard,
+	}
+
+	for _, id := range authz.challengeIDs {
+		result.Challenges = append(result.Challenges, s.toACMEChallenge(base, s.challenges[id]))
+	}
+
+	return result
+}
+
+func (s *Server) toACMEChallenge(base string, chlg *challengeState) acme.Challenge {
+	result := chlg.Challenge
+	result.URL = chlg.url(base)
+
+	return result
+}
+
+func (s *Server) handleNewAuthz(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	var msg acme.NewAuthzMessage
+
+	err := json.Unmarshal(req.payload, &msg)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the authorization request: %v", err))
+		return
+	}
+
+	if problem := checkIdentifier(msg.Identifier); problem != nil {
+		writeProblem(w, problem)
+		return
+	}
+
+	if strings.HasPrefix(msg.Identifier.Value, "*.") {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.RejectedIdentifierErr, "wildcard domains cannot be pre-authorized"))
+		return
+	}
+
+	base := baseURL(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authz := s.newAuthorization(req.account.id, msg.Identifier)
+
+	w.Header().Set("Location", authz.url(base))
+	writeJSON(w, http.StatusCreated, s.toACMEAuthorization(base, authz))
+}
+
+func (s *Server) handleAuthorization(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	var msg acme.Authorization
+
+	if !req.postAsGet() {
+		err := json.Unmarshal(req.payload, &msg)
+		if err != nil {
+			writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the authorization: %v", err))
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authz, exists := s.authorizations[r.PathValue("id")]
+	if !exists || authz.accountID != req.account.id {
+		writeProblem(w, newProblem(http.StatusNotFound, acme.MalformedErr, "unknown authorization"))
+		return
+	}
+
+	s.updateAuthorization(authz)
+
+	if msg.Status == acme.StatusDeactivated {
+		if authz.Status != acme.StatusPending && authz.Status != acme.StatusValid {
+			writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "the authorization is %s", authz.Status))
+			return
+		}
+
+		authz.Status = acme.StatusDeactivated
+	}
+
+	writeJSON(w, http.StatusOK, s.toACMEAuthorization(baseURL(r), authz))
+}
+
+func (s *Server) handleChallenge(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	base := baseURL(r)
+
+	s.mu.Lock()
+
+	chlg, exists := s.challenges[r.PathValue("id")]
+	if !exists || s.authorizations[chlg.authzID].accountID != req.account.id {
+		s.mu.Unlock()
+		writeProblem(w, newProblem(http.StatusNotFound, acme.MalformedErr, "unknown challenge"))
+
+		return
+	}
+
+	authz := s.authorizations[chlg.authzID]
+	s.updateAuthorization(authz)
+
+	// The validation is started by a POST request with an empty JSON object.
+	start := !req.postAsGet() && chlg.Status == acme.StatusPending && authz.Status == acme.StatusPending
+	if start {
+		chlg.Status = acme.StatusProcessing
+	}
+
+	validation := ValidationRequest{
+		Type:             chlg.Type,
+		Identifier:       authz.Identifier,
+		Wildcard:         authz.Wildcard,
+		Token:            chlg.Token,
+		KeyAuthorization: chlg.Token + "." + req.account.thumbprint,
+		AccountURL:       req.account.url(base),
+	}
+
+	s.mu.Unlock()
+
+	if start {
+		// The validation is done synchronously: the challenge is valid or invalid in the response.
+		err := s.validator.Validate(r.Context(), validation)
+
+		s.mu.Lock()
+		s.completeChallenge(chlg, authz, err)
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Link", "<"+authz.url(base)+`>;rel="up"`)
+	writeJSON(w, http.StatusOK, s.toACMEChallenge(base, chlg))
+}
+
+// completeChallenge updates the challenge and its authorization with the result of the validation.
+// The caller must hold the lock.
+func (s *Server) completeChallenge(chlg *challengeState, authz *authorization, err error) {
+	if err != nil {
+		chlg.Status = acme.StatusInvalid
+		chlg.Error = toProblem(err)
+		authz.Status = acme.StatusInvalid
+
+		return
+	}
+
+	chlg.Status = acme.StatusValid
+	chlg.Validated = time.Now().UTC()
+
+	if authz.Status == acme.StatusPending {
+		authz.Status = acme.StatusValid
+	}
+}
+
+// newAuthorization creates a pending authorization.
+// The caller must hold the lock.
+func (s *Server) newAuthorization(accountID string, ident acme.Identifier) *authorization {
+	authz := &authorization{
+		id:         s.newID(),
+		accountID:  accountID,
+		Status:     acme.StatusPending,
+		Expires:    time.Now().Add(authzLifetime).UTC().Truncate(time.Second),
+		Identifier: acme.Identifier{Type: ident.Type, Value: strings.TrimPrefix(ident.Value, "*.")},
+		Wildcard:   strings.HasPrefix(ident.Value, "*."),
+	}
+
+	for _, typ := range s.challengeTypes {
+		if authz.Wildcard && !strings.HasPrefix(typ, "dns-") {
+			continue
+		}
+
+		if ident.Type == "ip" && strings.HasPrefix(typ, "dns-") {
+			continue
+		}
+
+		chlg := &challengeState{
+			id:      s.newID(),
+			authzID: authz.id,
+			Challenge: acme.Challenge{
+				Type:   typ,
+				Status: acme.StatusPending,
+				Token:  randomString(),
+			},
+		}
+
+		s.challenges[chlg.id] = chlg
+		authz.challengeIDs = append(authz.challengeIDs, chlg.id)
+	}
+
+	s.authorizations[authz.id] = authz
+
+	return authz
+}
+
+// findValidAuthorization returns a valid authorization of the account for the identifier, if any.
+// The caller must hold the lock.
+func (s *Server) findValidAuthorization(accountID string, ident acme.Identifier) *authorization {
+	value := strings.TrimPrefix(ident.Value, "*.")
+	wildcard := strings.HasPrefix(ident.Value, "*.")
+
+	for _, authz := range s.authorizations {
+		s.updateAuthorization(authz)
+
+		if authz.accountID == accountID && authz.Status == acme.StatusValid &&
+			authz.Identifier.Type == ident.Type && authz.Identifier.Value == value && authz.Wildcard == wildcard {
+			return authz
+		}
+	}
+
+	return nil
+}
+
+// updateAuthorization updates the status of an authorization.
+// The caller must hold the lock.
+func (s *Server) updateAuthorization(authz *authorization) {
+	if (authz.Status == acme.StatusPending || authz.Status == acme.StatusValid) && time.Now().After(authz.Expires) {
+		authz.Status = acme.StatusExpired
+	}
+}
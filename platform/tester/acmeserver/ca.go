@@ -0,0 +1,248 @@
+package acmeserver
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+// certificateAuthority a root and an intermediate certificates, used to sign the certificates.
+type certificateAuthority struct {
+	root *x509.Certificate
+
+	intermediate    *x509.Certificate
+	intermediateKey crypto.Signer
+}
+
+func newCertificateAuthority() (*certificateAuthority, error) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "acmeserver root"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	root, err := createCertificate(rootTemplate, rootTemplate, rootKey.Public(), rootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	intermediateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	intermediateTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "acmeserver intermediate"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(5 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	intermediate, err := createCertificate(intermediateTemplate, root, intermediateKey.Public(), rootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &certificateAuthority{
+		root:            root,
+		intermediate:    intermediate,
+		intermediateKey: intermediateKey,
+	}, nil
+}
+
+func createCertificate(template, parent *x509.Certificate, pub crypto.PublicKey, priv crypto.Signer) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(der)
+}
+
+type certificate struct {
+	id        string
+	accountID string
+
+	cert     *x509.Certificate
+	chainPEM []byte
+	ariID    string
+
+	replaced bool
+	revoked  bool
+	reason   uint
+
+	// window overrides the suggested renewal window.
+	window *acme.Window
+}
+
+func (c *certificate) url(base string) string {
+	return base + certificatePath + c.id
+}
+
+// issue signs the certificate of an order.
+// The caller must hold the lock.
+func (s *Server) issue(o *order) (*certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	notBefore := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	notAfter := notBefore.Add(DefaultValidity)
+
+	if profile, ok := s.profiles[o.Profile]; ok && profile.Validity > 0 {
+		notAfter = notBefore.Add(profile.Validity)
+	}
+
+	if o.NotBefore != "" {
+		if t, errP := time.Parse(time.RFC3339, o.NotBefore); errP == nil {
+			notBefore = t
+		}
+	}
+
+	if o.NotAfter != "" {
+		if t, errP := time.Parse(time.RFC3339, o.NotAfter); errP == nil {
+			notAfter = t
+		}
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:   serial,
+		Subject:        pkix.Name{CommonName: o.csr.Subject.CommonName},
+		DNSNames:       o.csr.DNSNames,
+		IPAddresses:    o.csr.IPAddresses,
+		NotBefore:      notBefore,
+		NotAfter:       notAfter,
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		AuthorityKeyId: s.ca.intermediate.SubjectKeyId,
+	}
+
+	leaf, err := createCertificate(template, s.ca.intermediate, o.csr.PublicKey, s.ca.intermediateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ariID, err := makeARIID(leaf)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := bytes.NewBuffer(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}))
+	chain.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.ca.intermediate.Raw}))
+
+	cert := &certificate{
+		id:        s.newID(),
+		accountID: o.accountID,
+		cert:      leaf,
+		chainPEM:  chain.Bytes(),
+		ariID:     ariID,
+	}
+
+	s.certificates[cert.id] = cert
+
+	return cert, nil
+}
+
+func (s *Server) handleCertificate(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cert, exists := s.certificates[r.PathValue("id")]
+	if !exists || cert.accountID != req.account.id {
+		writeProblem(w, newProblem(http.StatusNotFound, acme.MalformedErr, "unknown certificate"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pem-certificate-chain")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(cert.chainPEM)
+}
+
+// RootCertificate returns the root certificate of the server.
+func (s *Server) RootCertificate() *x509.Certificate {
+	return s.ca.root
+}
+
+// IntermediateCertificate returns the certificate used to sign the certificates.
+func (s *Server) IntermediateCertificate() *x509.Certificate {
+	return s.ca.intermediate
+}
+
+// IsRevoked returns true if the certificate has been revoked.
+func (s *Server) IsRevoked(cert *x509.Certificate) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.findCertificate(cert)
+
+	return c != nil && c.revoked
+}
+
+// findCertificate returns the issued certificate matching a certificate.
+// The caller must hold the lock.
+func (s *Server) findCertificate(cert *x509.Certificate) *certificate {
+	for _, c := range s.certificates {
+		if bytes.Equal(c.cert.Raw, cert.Raw) {
+			return c
+		}
+	}
+
+	return nil
+}
+
+// findCertificateByARI returns the ID of the certificate identified by a renewal information ID (RFC 9773).
+// The caller must hold the lock.
+func (s *Server) findCertificateByARI(ariID string) string {
+	for id, c := range s.certificates {
+		if c.ariID == ariID {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// makeARIID returns the certificate identifier described in RFC 9773, section 4.1.
+func makeARIID(cert *x509.Certificate) (string, error) {
+	der, err := asn1.Marshal(cert.SerialNumber)
+	if err != nil {
+		return "", err
+	}
+
+	// Skip the tag and the length of the DER encoded integer.
+	return base64.RawURLEncoding.EncodeToString(cert.AuthorityKeyId) + "." + base64.RawURLEncoding.EncodeToString(der[2:]), nil
+}
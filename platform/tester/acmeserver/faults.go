@@ -0,0 +1,71 @@
+package acmeserver
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+type faults struct {
+	badNonces int
+
+	rateLimits int
+	retryAfter time.Duration
+}
+
+// InjectBadNonce rejects the next n signed requests with a badNonce error.
+func (s *Server) InjectBadNonce(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults.badNonces = n
+}
+
+// InjectRateLimit rejects the next n signed requests with a rateLimited error,
+// and a Retry-After header (if retryAfter is greater than 0).
+func (s *Server) InjectRateLimit(n int, retryAfter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults.rateLimits = n
+	s.faults.retryAfter = retryAfter
+}
+
+// SetProcessingDelay defines the time spent by the orders in the "processing" state after the finalization.
+func (s *Server) SetProcessingDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.processingDelay = delay
+}
+
+// injectFault writes the error of the next injected fault, returns false if there is no fault to inject.
+func (s *Server) injectFault(w http.ResponseWriter) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.faults.badNonces > 0:
+		s.faults.badNonces--
+
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.BadNonceErr, "JWS has an invalid anti-replay nonce (injected)"))
+
+		return true
+
+	case s.faults.rateLimits > 0:
+		s.faults.rateLimits--
+
+		if s.faults.retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(s.faults.retryAfter.Round(time.Second).Seconds())))
+		}
+
+		writeProblem(w, newProblem(http.StatusTooManyRequests, acme.RateLimitedErr, "too many requests (injected)"))
+
+		return true
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,149 @@
+package acmeserver
+
+import (
+	"crypto"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-jose/go-jose/v4"
+)
+
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// signedRequest a verified JWS request.
+type signedRequest struct {
+	payload []byte
+
+	// The embedded key (jwk), nil if the request is signed by an account (kid).
+	jwk *jose.JSONWebKey
+
+	// The account which signed the request (kid), nil if the key is embedded.
+	account *account
+}
+
+// postAsGet returns true if the request is a POST-as-GET request.
+func (r *signedRequest) postAsGet() bool {
+	return len(r.payload) == 0
+}
+
+// verify verifies a JWS request.
+// The embedded keys (jwk) are only allowed if allowJWK is true, otherwise an account (kid) is required.
+// The response contains a new nonce.
+func (s *Server) verify(w http.ResponseWriter, r *http.Request, allowJWK bool) (*signedRequest, bool) {
+	s.addNonce(w)
+
+	req, problem := s.parseSignedRequest(r, allowJWK)
+	if problem != nil {
+		writeProblem(w, problem)
+		return nil, false
+	}
+
+	if s.injectFault(w) {
+		return nil, false
+	}
+
+	return req, true
+}
+
+func (s *Server) parseSignedRequest(r *http.Request, allowJWK bool) (*signedRequest, *acme.ProblemDetails) {
+	if ct := r.Header.Get("Content-Type"); ct != "application/jose+json" {
+		return nil, newProblem(http.StatusUnsupportedMediaType, acme.MalformedErr, "invalid Content-Type header: %q", ct)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to read the request body: %v", err)
+	}
+
+	jws, err := jose.ParseSigned(string(body), signatureAlgorithms)
+	if err != nil {
+		return nil, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the JWS: %v", err)
+	}
+
+	if len(jws.Signatures) != 1 {
+		return nil, newProblem(http.StatusBadRequest, acme.MalformedErr, "the JWS must contain exactly one signature")
+	}
+
+	header := jws.Signatures[0].Protected
+
+	reqURL, _ := header.ExtraHeaders[jose.HeaderKey("url")].(string)
+	if reqURL != baseURL(r)+r.URL.Path {
+		return nil, newProblem(http.StatusUnauthorized, acme.UnauthorizedErr, "the JWS url header %q doesn't match the request URL", reqURL)
+	}
+
+	if !s.useNonce(header.Nonce) {
+		return nil, newProblem(http.StatusBadRequest, acme.BadNonceErr, "JWS has an invalid anti-replay nonce: %q", header.Nonce)
+	}
+
+	req := &signedRequest{}
+
+	var key any
+
+	switch {
+	case header.JSONWebKey != nil && header.KeyID == "":
+		if !allowJWK {
+			return nil, newProblem(http.StatusBadRequest, acme.MalformedErr, "the JWS must be signed by an account (kid)")
+		}
+
+		req.jwk = header.JSONWebKey
+		key = header.JSONWebKey
+
+	case header.KeyID != "" && header.JSONWebKey == nil:
+		req.account = s.getAccountByURL(header.KeyID)
+		if req.account == nil {
+			return nil, newProblem(http.StatusBadRequest, acme.AccountDoesNotExistErr, "unknown account: %s", header.KeyID)
+		}
+
+		s.mu.Lock()
+		status, accountKey := req.account.Status, req.account.key
+		s.mu.Unlock()
+
+		if status != acme.StatusValid {
+			return nil, newProblem(http.StatusUnauthorized, acme.UnauthorizedErr, "the account is %s", status)
+		}
+
+		key = accountKey
+
+	default:
+		return nil, newProblem(http.StatusBadRequest, acme.MalformedErr, "the JWS must contain either a jwk or a kid")
+	}
+
+	req.payload, err = jws.Verify(key)
+	if err != nil {
+		return nil, newProblem(http.StatusBadRequest, acme.MalformedErr, "JWS verification error: %v", err)
+	}
+
+	return req, nil
+}
+
+// getAccountByURL returns the account identified by its URL (kid).
+func (s *Server) getAccountByURL(accountURL string) *account {
+	u, err := url.Parse(accountURL)
+	if err != nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.accounts[strings.TrimPrefix(u.Path, accountPath)]
+}
+
+// thumbprint returns the JWK thumbprint (RFC 7638) of a key.
+func thumbprint(key *jose.JSONWebKey) (string, error) {
+	raw, err := key.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,350 @@
+package acmeserver
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+// invalidProfileErr the error type for unknown profiles (draft-aaron-acme-profiles).
+const invalidProfileErr = "urn:ietf:params:acme:error:invalidProfile"
+
+const (
+	orderLifetime = 7 * 24 * time.Hour
+	authzLifetime = 30 * 24 * time.Hour
+)
+
+type order struct {
+	id        string
+	accountID string
+
+	acme.Order
+
+	authzIDs    []string
+	certID      string
+	finalizedAt time.Time
+	csr         *x509.CertificateRequest
+}
+
+func (o *order) url(base string) string {
+	return base + orderPath + o.id
+}
+
+func (s *Server) toACMEOrder(base string, o *order) acme.Order {
+	result := o.Order
+	result.Authorizations = nil
+
+	for _, id := range o.authzIDs {
+		result.Authorizations = append(result.Authorizations, s.authorizations[id].url(base))
+	}
+
+	result.Finalize = o.url(base) + "/finalize"
+
+	if o.certID != "" {
+		result.Certificate = s.certificates[o.certID].url(base)
+	}
+
+	return result
+}
+
+func (s *Server) handleNewOrder(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	var msg acme.Order
+
+	err := json.Unmarshal(req.payload, &msg)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the order: %v", err))
+		return
+	}
+
+	if len(msg.Identifiers) == 0 {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "the order doesn't contain identifiers"))
+		return
+	}
+
+	for _, ident := range msg.Identifiers {
+		if problem := checkIdentifier(ident); problem != nil {
+			writeProblem(w, problem)
+			return
+		}
+	}
+
+	base := baseURL(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if msg.Profile != "" {
+		if _, exists := s.profiles[msg.Profile]; !exists {
+			writeProblem(w, newProblem(http.StatusBadRequest, invalidProfileErr, "unknown profile: %s", msg.Profile))
+			return
+		}
+	}
+
+	if msg.Replaces != "" {
+		cert, exists := s.certificates[s.findCertificateByARI(msg.Replaces)]
+		if !exists || cert.accountID != req.account.id {
+			writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unknown replaced certificate: %s", msg.Replaces))
+			return
+		}
+
+		if cert.replaced {
+			writeProblem(w, newProblem(http.StatusConflict, acme.AlreadyReplacedErr, "the certificate %s has already been replaced", msg.Replaces))
+			return
+		}
+
+		cert.replaced = true
+	}
+
+	o := &order{
+		id:        s.newID(),
+		accountID: req.account.id,
+		Order: acme.Order{
+			Status:      acme.StatusPending,
+			Expires:     time.Now().Add(orderLifetime).UTC().Format(time.RFC3339),
+			Identifiers: msg.Identifiers,
+			Profile:     msg.Profile,
+			NotBefore:   msg.NotBefore,
+			NotAfter:    msg.NotAfter,
+			Replaces:    msg.Replaces,
+		},
+	}
+
+	for _, ident := range msg.Identifiers {
+		authz := s.findValidAuthorization(req.account.id, ident)
+		if authz == nil {
+			authz = s.newAuthorization(req.account.id, ident)
+		}
+
+		o.authzIDs = append(o.authzIDs, authz.id)
+	}
+
+	s.orders[o.id] = o
+	req.account.orderIDs = append(req.account.orderIDs, o.id)
+
+	s.updateOrder(o)
+
+	w.Header().Set("Location", o.url(base))
+	writeJSON(w, http.StatusCreated, s.toACMEOrder(base, o))
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, exists := s.orders[r.PathValue("id")]
+	if !exists || o.accountID != req.account.id {
+		writeProblem(w, newProblem(http.StatusNotFound, acme.MalformedErr, "unknown order"))
+		return
+	}
+
+	s.updateOrder(o)
+
+	if o.Status == acme.StatusProcessing {
+		w.Header().Set("Retry-After", "1")
+	}
+
+	writeJSON(w, http.StatusOK, s.toACMEOrder(baseURL(r), o))
+}
+
+func (s *Server) handleFinalize(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, false)
+	if !ok {
+		return
+	}
+
+	var msg acme.CSRMessage
+
+	err := json.Unmarshal(req.payload, &msg)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the finalization request: %v", err))
+		return
+	}
+
+	der, err := base64.RawURLEncoding.DecodeString(msg.Csr)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.BadCSRErr, "unable to decode the CSR: %v", err))
+		return
+	}
+
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.BadCSRErr, "unable to parse the CSR: %v", err))
+		return
+	}
+
+	err = csr.CheckSignature()
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.BadCSRErr, "invalid CSR signature: %v", err))
+		return
+	}
+
+	base := baseURL(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, exists := s.orders[r.PathValue("id")]
+	if !exists || o.accountID != req.account.id {
+		writeProblem(w, newProblem(http.StatusNotFound, acme.MalformedErr, "unknown order"))
+		return
+	}
+
+	s.updateOrder(o)
+
+	if o.Status != acme.StatusReady {
+		writeProblem(w, newProblem(http.StatusForbidden, acme.OrderNotReadyErr, "the order is %s", o.Status))
+		return
+	}
+
+	if !slices.Equal(csrIdentifiers(csr), orderIdentifiers(o)) {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.BadCSRErr, "the CSR identifiers don't match the order identifiers"))
+		return
+	}
+
+	o.Status = acme.StatusProcessing
+	o.finalizedAt = time.Now()
+	o.csr = csr
+
+	s.updateOrder(o)
+
+	if o.Status == acme.StatusProcessing {
+		w.Header().Set("Retry-After", "1")
+	}
+
+	w.Header().Set("Location", o.url(base))
+	writeJSON(w, http.StatusOK, s.toACMEOrder(base, o))
+}
+
+// updateOrder updates the status of an order.
+// The caller must hold the lock.
+func (s *Server) updateOrder(o *order) {
+	switch o.Status {
+	case acme.StatusPending:
+		expires, _ := time.Parse(time.RFC3339, o.Expires)
+		if time.Now().After(expires) {
+			o.Status = acme.StatusInvalid
+			return
+		}
+
+		ready := true
+
+		for _, id := range o.authzIDs {
+			authz := s.authorizations[id]
+			s.updateAuthorization(authz)
+
+			switch authz.Status {
+			case acme.StatusValid:
+			case acme.StatusPending:
+				ready = false
+			default:
+				o.Status = acme.StatusInvalid
+				o.Error = newProblem(http.StatusForbidden, acme.UnauthorizedErr, "the authorization for %s is %s", authz.Identifier.Value, authz.Status)
+
+				if chlg := authz.failedChallenge(s); chlg != nil && chlg.Error != nil {
+					o.Error = chlg.Error
+				}
+
+				return
+			}
+		}
+
+		if ready {
+			o.Status = acme.StatusReady
+		}
+
+	case acme.StatusProcessing:
+		if time.Since(o.finalizedAt) < s.processingDelay {
+			return
+		}
+
+		cert, err := s.issue(o)
+		if err != nil {
+			o.Status = acme.StatusInvalid
+			o.Error = newProblem(http.StatusInternalServerError, acme.ServerInternalErr, "unable to issue the certificate: %v", err)
+
+			return
+		}
+
+		o.certID = cert.id
+		o.Status = acme.StatusValid
+	}
+}
+
+// checkIdentifier checks that an identifier is supported.
+func checkIdentifier(ident acme.Identifier) *acme.ProblemDetails {
+	switch ident.Type {
+	case "dns":
+		value := strings.TrimPrefix(ident.Value, "*.")
+		if value == "" || strings.Contains(value, "*") || net.ParseIP(value) != nil {
+			return newProblem(http.StatusBadRequest, acme.RejectedIdentifierErr, "invalid DNS identifier: %q", ident.Value)
+		}
+
+		return nil
+
+	case "ip":
+		if net.ParseIP(ident.Value) == nil {
+			return newProblem(http.StatusBadRequest, acme.RejectedIdentifierErr, "invalid IP identifier: %q", ident.Value)
+		}
+
+		return nil
+
+	default:
+		return newProblem(http.StatusBadRequest, acme.UnsupportedIdentifierErr, "unsupported identifier type: %q", ident.Type)
+	}
+}
+
+// csrIdentifiers returns the sorted and unique identifiers of a CSR.
+func csrIdentifiers(csr *x509.CertificateRequest) []string {
+	var values []string
+
+	if csr.Subject.CommonName != "" {
+		values = append(values, strings.ToLower(csr.Subject.CommonName))
+	}
+
+	for _, name := range csr.DNSNames {
+		values = append(values, strings.ToLower(name))
+	}
+
+	for _, ip := range csr.IPAddresses {
+		values = append(values, ip.String())
+	}
+
+	slices.Sort(values)
+
+	return slices.Compact(values)
+}
+
+// orderIdentifiers returns the sorted and unique identifiers of an order.
+func orderIdentifiers(o *order) []string {
+	var values []string
+
+	for _, ident := range o.Identifiers {
+		if ident.Type == "ip" {
+			values = append(values, net.ParseIP(ident.Value).String())
+			continue
+		}
+
+		values = append(values, strings.ToLower(ident.Value))
+	}
+
+	slices.Sort(values)
+
+	return slices.Compact(values)
+}
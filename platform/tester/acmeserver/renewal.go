@@ -0,0 +1,64 @@
+package acmeserver
+
+import (
+	"crypto/x509"
+	"net/http"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+// renewalInfoRetryAfter the delay suggested to the clients before checking the renewal information again.
+const renewalInfoRetryAfter = "21600"
+
+// SetRenewalWindow overrides the renewal window suggested for a certificate (RFC 9773).
+// Returns false if the certificate was not issued by the server.
+func (s *Server) SetRenewalWindow(cert *x509.Certificate, start, end time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.findCertificate(cert)
+	if c == nil {
+		return false
+	}
+
+	c.window = &acme.Window{Start: start.UTC(), End: end.UTC()}
+
+	return true
+}
+
+func (s *Server) handleRenewalInfo(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cert, exists := s.certificates[s.findCertificateByARI(r.PathValue("id"))]
+	if !exists {
+		writeProblem(w, newProblem(http.StatusNotFound, acme.MalformedErr, "unknown certificate"))
+		return
+	}
+
+	w.Header().Set("Retry-After", renewalInfoRetryAfter)
+	writeJSON(w, http.StatusOK, acme.RenewalInfoResponse{SuggestedWindow: suggestedWindow(cert)})
+}
+
+// suggestedWindow returns the renewal window of a certificate:
+// the window starts at 2/3 of the lifetime and ends at 5/6 of the lifetime,
+// or is in the past if the certificate has been revoked.
+func suggestedWindow(cert *certificate) acme.Window {
+	if cert.window != nil {
+		return *cert.window
+	}
+
+	if cert.revoked {
+		now := time.Now().UTC().Truncate(time.Second)
+		return acme.Window{Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)}
+	}
+
+	notBefore := cert.cert.NotBefore
+	lifetime := cert.cert.NotAfter.Sub(notBefore)
+
+	return acme.Window{
+		Start: notBefore.Add(lifetime * 2 / 3).UTC(),
+		End:   notBefore.Add(lifetime * 5 / 6).UTC(),
+	}
+}
@@ -0,0 +1,118 @@
+package acmeserver
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-jose/go-jose/v4"
+)
+
+func (s *Server) handleRevokeCert(w http.ResponseWriter, r *http.Request) {
+	req, ok := s.verify(w, r, true)
+	if !ok {
+		return
+	}
+
+	var msg acme.RevokeCertMessage
+
+	err := json.Unmarshal(req.payload, &msg)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the revocation request: %v", err))
+		return
+	}
+
+	der, err := base64.RawURLEncoding.DecodeString(msg.Certificate)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to decode the certificate: %v", err))
+		return
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.MalformedErr, "unable to parse the certificate: %v", err))
+		return
+	}
+
+	var reason uint
+	if msg.Reason != nil {
+		reason = *msg.Reason
+	}
+
+	// The reason code 7 is not used (RFC 5280, section 5.3.1).
+	if reason == 7 || reason > acme.CRLReasonAACompromise {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.BadRevocationReasonErr, "unsupported revocation reason: %d", reason))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cert := s.findCertificate(leaf)
+	if cert == nil {
+		writeProblem(w, newProblem(http.StatusNotFound, acme.MalformedErr, "unknown certificate"))
+		return
+	}
+
+	if !s.canRevoke(req, cert) {
+		writeProblem(w, newProblem(http.StatusForbidden, acme.UnauthorizedErr, "the requester is not authorized to revoke the certificate"))
+		return
+	}
+
+	if cert.revoked {
+		writeProblem(w, newProblem(http.StatusBadRequest, acme.AlreadyRevokedErr, "the certificate has already been revoked"))
+		return
+	}
+
+	cert.revoked = true
+	cert.reason = reason
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// canRevoke checks if the request is signed by the key of the certificate,
+// by the account which issued the certificate, or by an account authorized for all the identifiers of the certificate.
+// The caller must hold the lock.
+func (s *Server) canRevoke(req *signedRequest, cert *certificate) bool {
+	if req.jwk != nil {
+		return samePublicKey(req.jwk, cert.cert)
+	}
+
+	if cert.accountID == req.account.id {
+		return true
+	}
+
+	var identifiers []acme.Identifier
+
+	for _, name := range cert.cert.DNSNames {
+		identifiers = append(identifiers, acme.Identifier{Type: "dns", Value: name})
+	}
+
+	for _, ip := range cert.cert.IPAddresses {
+		identifiers = append(identifiers, acme.Identifier{Type: "ip", Value: ip.String()})
+	}
+
+	for _, ident := range identifiers {
+		if s.findValidAuthorization(req.account.id, ident) == nil {
+			return false
+		}
+	}
+
+	return len(identifiers) > 0
+}
+
+func samePublicKey(jwk *jose.JSONWebKey, cert *x509.Certificate) bool {
+	type equaler interface {
+		Equal(x any) bool
+	}
+
+	pub, ok := jwk.Key.(equaler)
+	if !ok {
+		return reflect.DeepEqual(jwk.Key, cert.PublicKey)
+	}
+
+	return pub.Equal(cert.PublicKey)
+}
@@ -0,0 +1,288 @@
+// Package acmeserver provides an in-memory ACME server (RFC 8555), to run hermetic tests of the code built on top of lego.
+//
+// The server supports the accounts, the orders, the authorizations (including the pre-authorizations),
+// the challenges (validated by a pluggable Validator), the certificate profiles, the renewal information (RFC 9773),
+// and the revocation.
+// It can also inject faults: badNonce, rateLimited, and slow processing of the orders.
+package acmeserver
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+// Paths of the server endpoints.
+const (
+	DirectoryPath   = "/directory"
+	newNoncePath    = "/new-nonce"
+	newAccountPath  = "/new-account"
+	newOrderPath    = "/new-order"
+	newAuthzPath    = "/new-authz"
+	revokeCertPath  = "/revoke-cert"
+	keyChangePath   = "/key-change"
+	renewalInfoPath = "/renewal-info"
+	accountPath     = "/account/"
+	orderPath       = "/order/"
+	authzPath       = "/authz/"
+	challengePath   = "/chall/"
+	certificatePath = "/cert/"
+)
+
+// The default challenge types.
+const (
+	HTTP01    = "http-01"
+	DNS01     = "dns-01"
+	TLSALPN01 = "tls-alpn-01"
+)
+
+// DefaultValidity the default validity of the certificates.
+const DefaultValidity = 90 * 24 * time.Hour
+
+// Profile a certificate profile (draft-aaron-acme-profiles).
+type Profile struct {
+	Description string
+
+	// Validity of the certificates.
+	Validity time.Duration
+}
+
+// Option configures the server.
+type Option func(*Server)
+
+// WithValidator defines the validator of the challenges.
+// By default, all the challenges are valid.
+func WithValidator(validator Validator) Option {
+	return func(s *Server) {
+		s.validator = validator
+	}
+}
+
+// WithChallengeTypes defines the challenge types offered by the server.
+// By default, http-01, dns-01, and tls-alpn-01 are offered.
+// Only the challenge types starting with "dns-" are offered for the wildcard domains.
+func WithChallengeTypes(types ...string) Option {
+	return func(s *Server) {
+		s.challengeTypes = types
+	}
+}
+
+// WithProfile adds a certificate profile.
+func WithProfile(name string, profile Profile) Option {
+	return func(s *Server) {
+		s.profiles[name] = profile
+	}
+}
+
+// WithProcessingDelay defines the time spent by the orders in the "processing" state after the finalization.
+func WithProcessingDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.processingDelay = delay
+	}
+}
+
+// Server an in-memory ACME server.
+type Server struct {
+	mu sync.Mutex
+
+	mux *http.ServeMux
+
+	validator       Validator
+	challengeTypes  []string
+	profiles        map[string]Profile
+	processingDelay time.Duration
+
+	ca *certificateAuthority
+
+	nonces map[string]struct{}
+	lastID int
+
+	accounts       map[string]*account
+	orders         map[string]*order
+	authorizations map[string]*authorization
+	challenges     map[string]*challengeState
+	certificates   map[string]*certificate
+
+	faults faults
+}
+
+// New creates a new Server.
+func New(opts ...Option) (*Server, error) {
+	ca, err := newCertificateAuthority()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		mux:            http.NewServeMux(),
+		validator:      AcceptAll(),
+		challengeTypes: []string{HTTP01, DNS01, TLSALPN01},
+		profiles:       map[string]Profile{},
+		ca:             ca,
+		nonces:         map[string]struct{}{},
+		accounts:       map[string]*account{},
+		orders:         map[string]*order{},
+		authorizations: map[string]*authorization{},
+		challenges:     map[string]*challengeState{},
+		certificates:   map[string]*certificate{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("GET "+DirectoryPath, s.handleDirectory)
+	s.mux.HandleFunc(newNoncePath, s.handleNewNonce)
+	s.mux.HandleFunc("POST "+newAccountPath, s.handleNewAccount)
+	s.mux.HandleFunc("POST "+newOrderPath, s.handleNewOrder)
+	s.mux.HandleFunc("POST "+newAuthzPath, s.handleNewAuthz)
+	s.mux.HandleFunc("POST "+revokeCertPath, s.handleRevokeCert)
+	s.mux.HandleFunc("POST "+keyChangePath, s.handleKeyChange)
+	s.mux.HandleFunc("GET "+renewalInfoPath+"/{id}", s.handleRenewalInfo)
+	s.mux.HandleFunc("POST "+accountPath+"{id}", s.handleAccount)
+	s.mux.HandleFunc("POST "+accountPath+"{id}/orders", s.handleAccountOrders)
+	s.mux.HandleFunc("POST "+orderPath+"{id}", s.handleOrder)
+	s.mux.HandleFunc("POST "+orderPath+"{id}/finalize", s.handleFinalize)
+	s.mux.HandleFunc("POST "+authzPath+"{id}", s.handleAuthorization)
+	s.mux.HandleFunc("POST "+challengePath+"{id}", s.handleChallenge)
+	s.mux.HandleFunc("POST "+certificatePath+"{id}", s.handleCertificate)
+
+	return s, nil
+}
+
+// Start starts a Server with httptest, and returns it with the URL of the directory.
+// The server is closed at the end of the test.
+func Start(t testing.TB, opts ...Option) (*Server, string) {
+	t.Helper()
+
+	s, err := New(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	return s, server.URL + DirectoryPath
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleDirectory(w http.ResponseWriter, r *http.Request) {
+	base := baseURL(r)
+
+	dir := acme.Directory{
+		NewNonceURL:   base + newNoncePath,
+		NewAccountURL: base + newAccountPath,
+		NewOrderURL:   base + newOrderPath,
+		NewAuthzURL:   base + newAuthzPath,
+		RevokeCertURL: base + revokeCertPath,
+		KeyChangeURL:  base + keyChangePath,
+		RenewalInfo:   base + renewalInfoPath,
+	}
+
+	s.mu.Lock()
+	if len(s.profiles) > 0 {
+		dir.Meta.Profiles = map[string]string{}
+		for name, profile := range s.profiles {
+			dir.Meta.Profiles[name] = profile.Description
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, dir)
+}
+
+func (s *Server) handleNewNonce(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodHead && r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.addNonce(w)
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.Method == http.MethodGet {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// addNonce adds a new nonce to the response.
+func (s *Server) addNonce(w http.ResponseWriter) {
+	nonce := randomString()
+
+	s.mu.Lock()
+	s.nonces[nonce] = struct{}{}
+	s.mu.Unlock()
+
+	w.Header().Set("Replay-Nonce", nonce)
+}
+
+// useNonce consumes a nonce, returns false if the nonce is unknown or already used.
+func (s *Server) useNonce(nonce string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.nonces[nonce]; !ok {
+		return false
+	}
+
+	delete(s.nonces, nonce)
+
+	return true
+}
+
+// newID returns a new resource identifier.
+// The caller must hold the lock.
+func (s *Server) newID() string {
+	s.lastID++
+	return fmt.Sprintf("%d", s.lastID)
+}
+
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + r.Host
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeProblem(w http.ResponseWriter, problem *acme.ProblemDetails) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.HTTPStatus)
+
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+func newProblem(status int, typ, format string, args ...any) *acme.ProblemDetails {
+	return &acme.ProblemDetails{
+		Type:       typ,
+		Detail:     fmt.Sprintf(format, args...),
+		HTTPStatus: status,
+	}
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,386 @@
+package acmeserver_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-acme/lego/v4/acme"
+	"github.com/go-acme/lego/v4/acme/api"
+	"github.com/go-acme/lego/v4/certcrypto"
+	"github.com/go-acme/lego/v4/certificate"
+	"github.com/go-acme/lego/v4/lego"
+	"github.com/go-acme/lego/v4/platform/tester/acmeserver"
+	"github.com/go-acme/lego/v4/registration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_obtain(t *testing.T) {
+	provider := &mockProvider{}
+
+	server, dirURL := acmeserver.Start(t, acmeserver.WithValidator(provider))
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(provider)
+	require.NoError(t, err)
+
+	certRes, err := client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains: []string{"example.com", "www.example.com"},
+		Bundle:  true,
+	})
+	require.NoError(t, err)
+
+	certs, err := certcrypto.ParsePEMBundle(certRes.Certificate)
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+
+	assert.ElementsMatch(t, []string{"example.com", "www.example.com"}, certs[0].DNSNames)
+	assert.Equal(t, server.IntermediateCertificate().Raw, certs[1].Raw)
+	assert.NoError(t, certs[1].CheckSignatureFrom(server.RootCertificate()))
+	assert.InDelta(t, acmeserver.DefaultValidity.Seconds(), certs[0].NotAfter.Sub(certs[0].NotBefore).Seconds(), 1)
+
+	assert.ElementsMatch(t, []string{"example.com", "www.example.com"}, provider.presented())
+}
+
+func TestServer_obtain_invalidChallenge(t *testing.T) {
+	validator := acmeserver.ValidatorFunc(func(_ context.Context, _ acmeserver.ValidationRequest) error {
+		return errors.New("connection refused")
+	})
+
+	_, dirURL := acmeserver.Start(t, acmeserver.WithValidator(validator))
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	_, err = client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), acme.IncorrectResponseErr)
+}
+
+func TestServer_profile(t *testing.T) {
+	_, dirURL := acmeserver.Start(t, acmeserver.WithProfile("shortlived", acmeserver.Profile{
+		Description: "short-lived certificates",
+		Validity:    6 * 24 * time.Hour,
+	}))
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	certRes, err := client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains: []string{"example.com"},
+		Profile: "shortlived",
+	})
+	require.NoError(t, err)
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, 6*24*time.Hour, cert.NotAfter.Sub(cert.NotBefore))
+
+	_, err = client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains: []string{"example.com"},
+		Profile: "unknown",
+	})
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "invalidProfile")
+}
+
+func TestServer_renewalInfo(t *testing.T) {
+	server, dirURL := acmeserver.Start(t)
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	certRes, err := client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	require.NoError(t, err)
+
+	info, err := client.Certificate.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: cert})
+	require.NoError(t, err)
+
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+
+	assert.Equal(t, cert.NotBefore.Add(lifetime*2/3), info.SuggestedWindow.Start)
+	assert.Equal(t, cert.NotBefore.Add(lifetime*5/6), info.SuggestedWindow.End)
+	assert.Equal(t, 6*time.Hour, info.RetryAfter)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.True(t, server.SetRenewalWindow(cert, start, start.Add(time.Minute)))
+
+	info, err = client.Certificate.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: cert})
+	require.NoError(t, err)
+
+	assert.True(t, info.SuggestedWindow.Start.Equal(start))
+
+	certID, err := certificate.MakeARICertID(cert)
+	require.NoError(t, err)
+
+	_, err = client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains:        []string{"example.com"},
+		ReplacesCertID: certID,
+	})
+	require.NoError(t, err)
+
+	// The certificate is already replaced: the client retries without the "replaces" field.
+	_, err = client.Certificate.Obtain(certificate.ObtainRequest{
+		Domains:        []string{"example.com"},
+		ReplacesCertID: certID,
+	})
+	require.NoError(t, err)
+}
+
+func TestServer_revoke(t *testing.T) {
+	server, dirURL := acmeserver.Start(t)
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	certRes, err := client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	require.NoError(t, err)
+
+	assert.False(t, server.IsRevoked(cert))
+
+	reason := acme.CRLReasonKeyCompromise
+
+	err = client.Certificate.RevokeWithReason(certRes.Certificate, &reason)
+	require.NoError(t, err)
+
+	assert.True(t, server.IsRevoked(cert))
+
+	err = client.Certificate.Revoke(certRes.Certificate)
+	require.Error(t, err)
+
+	var alreadyRevoked *acme.AlreadyRevokedError
+	assert.ErrorAs(t, err, &alreadyRevoked)
+
+	info, err := client.Certificate.GetRenewalInfo(certificate.RenewalInfoRequest{Cert: cert})
+	require.NoError(t, err)
+
+	assert.True(t, info.SuggestedWindow.End.Before(time.Now()))
+}
+
+func TestServer_revoke_certificateKey(t *testing.T) {
+	server, dirURL := acmeserver.Start(t)
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	certRes, err := client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	cert, err := certcrypto.ParsePEMCertificate(certRes.Certificate)
+	require.NoError(t, err)
+
+	certKey, err := certcrypto.ParsePEMPrivateKey(certRes.PrivateKey)
+	require.NoError(t, err)
+
+	// A client without account.
+	config := lego.NewConfig(&mockUser{key: certKey})
+	config.CADirURL = dirURL
+
+	other, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	err = other.Certificate.RevokeWithKey(certRes.Certificate, otherKey, nil)
+	require.EqualError(t, err, "the private key doesn't match the certificate")
+
+	err = other.Certificate.RevokeWithKey(certRes.Certificate, certKey, nil)
+	require.NoError(t, err)
+
+	assert.True(t, server.IsRevoked(cert))
+}
+
+func TestServer_revoke_otherAccount(t *testing.T) {
+	_, dirURL := acmeserver.Start(t)
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	certRes, err := client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	other := setupClient(t, dirURL, nil)
+
+	err = other.Certificate.Revoke(certRes.Certificate)
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), acme.UnauthorizedErr)
+}
+
+func TestServer_badNonce(t *testing.T) {
+	server, dirURL := acmeserver.Start(t)
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	server.InjectBadNonce(2)
+
+	_, err = client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+}
+
+func TestServer_rateLimit(t *testing.T) {
+	server, dirURL := acmeserver.Start(t)
+
+	client := setupClient(t, dirURL, &api.RetryPolicy{MaxWait: 5 * time.Second, MaxRetries: 2})
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	server.InjectRateLimit(1, time.Second)
+
+	_, err = client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	server.InjectRateLimit(3, time.Second)
+
+	_, err = client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.Error(t, err)
+
+	retryAfter, ok := api.GetRetryAfter(err)
+	require.True(t, ok)
+
+	assert.Equal(t, time.Second, retryAfter)
+}
+
+func TestServer_processingDelay(t *testing.T) {
+	_, dirURL := acmeserver.Start(t, acmeserver.WithProcessingDelay(1500*time.Millisecond))
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	certRes, err := client.Certificate.Obtain(certificate.ObtainRequest{Domains: []string{"example.com"}})
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, certRes.Certificate)
+	assert.GreaterOrEqual(t, time.Since(start), 1500*time.Millisecond)
+}
+
+func TestServer_preAuthorize(t *testing.T) {
+	_, dirURL := acmeserver.Start(t)
+
+	client := setupClient(t, dirURL, nil)
+
+	err := client.Challenge.SetHTTP01Provider(&mockProvider{})
+	require.NoError(t, err)
+
+	authzs, err := client.Certificate.PreAuthorize([]string{"example.com"})
+	require.NoError(t, err)
+	require.Len(t, authzs, 1)
+
+	assert.Equal(t, acme.StatusValid, authzs[0].Status)
+}
+
+func setupClient(t *testing.T, dirURL string, policy *api.RetryPolicy) *lego.Client {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	user := &mockUser{key: key}
+
+	config := lego.NewConfig(user)
+	config.CADirURL = dirURL
+	config.RetryPolicy = policy
+
+	client, err := lego.NewClient(config)
+	require.NoError(t, err)
+
+	user.reg, err = client.Registration.Register(registration.RegisterOptions{TermsOfServiceAgreed: true})
+	require.NoError(t, err)
+
+	return client
+}
+
+type mockUser struct {
+	key crypto.PrivateKey
+	reg *registration.Resource
+}
+
+func (u *mockUser) GetEmail() string                        { return "test@example.com" }
+func (u *mockUser) GetRegistration() *registration.Resource { return u.reg }
+func (u *mockUser) GetPrivateKey() crypto.PrivateKey        { return u.key }
+
+// mockProvider an HTTP-01 provider, also used as validator to check the presented key authorizations.
+type mockProvider struct {
+	mu      sync.Mutex
+	records map[string]string
+}
+
+func (p *mockProvider) Present(domain, token, keyAuth string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.records == nil {
+		p.records = map[string]string{}
+	}
+
+	p.records[domain+"/"+token] = keyAuth
+
+	return nil
+}
+
+func (p *mockProvider) CleanUp(_, _, _ string) error {
+	return nil
+}
+
+func (p *mockProvider) Validate(_ context.Context, req acmeserver.ValidationRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.records[req.Identifier.Value+"/"+req.Token] != req.KeyAuthorization {
+		return &acme.ProblemDetails{Type: acme.UnauthorizedErr, Detail: "invalid key authorization", HTTPStatus: 403}
+	}
+
+	return nil
+}
+
+func (p *mockProvider) presented() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var domains []string
+	for key := range p.records {
+		domain, _, _ := strings.Cut(key, "/")
+		domains = append(domains, domain)
+	}
+
+	return domains
+}
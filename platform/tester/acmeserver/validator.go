@@ -0,0 +1,57 @@
+package acmeserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-acme/lego/v4/acme"
+)
+
+// ValidationRequest the information used to validate a challenge.
+type ValidationRequest struct {
+	// Type of the challenge (ex: http-01).
+	Type string
+
+	// Identifier of the authorization (without the wildcard prefix).
+	Identifier acme.Identifier
+	Wildcard   bool
+
+	Token            string
+	KeyAuthorization string
+
+	// AccountURL the URL of the account which owns the authorization.
+	AccountURL string
+}
+
+// Validator validates the challenges.
+type Validator interface {
+	// Validate returns an error if the challenge is invalid.
+	// If the error is an *acme.ProblemDetails, it is used as the error of the challenge,
+	// otherwise the error is reported as an incorrectResponse.
+	Validate(ctx context.Context, req ValidationRequest) error
+}
+
+// ValidatorFunc an adapter to use a function as a Validator.
+type ValidatorFunc func(ctx context.Context, req ValidationRequest) error
+
+// Validate calls f(ctx, req).
+func (f ValidatorFunc) Validate(ctx context.Context, req ValidationRequest) error {
+	return f(ctx, req)
+}
+
+// AcceptAll returns a Validator that accepts all the challenges.
+func AcceptAll() Validator {
+	return ValidatorFunc(func(_ context.Context, _ ValidationRequest) error {
+		return nil
+	})
+}
+
+func toProblem(err error) *acme.ProblemDetails {
+	var problem *acme.ProblemDetails
+	if errors.As(err, &problem) {
+		return problem
+	}
+
+	return newProblem(http.StatusForbidden, acme.IncorrectResponseErr, "%v", err)
+}
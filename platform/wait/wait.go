@@ -1,6 +1,7 @@
 package wait
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -9,6 +10,11 @@ import (
 
 // For polls the given function 'f', once every 'interval', up to 'timeout'.
 func For(msg string, timeout, interval time.Duration, f func() (bool, error)) error {
+	return ForWithContext(context.Background(), msg, timeout, interval, f)
+}
+
+// ForWithContext polls the given function 'f', once every 'interval', up to 'timeout' or until the context is done.
+func ForWithContext(ctx context.Context, msg string, timeout, interval time.Duration, f func() (bool, error)) error {
 	log.Infof("Wait for %s [timeout: %s, interval: %s]", msg, timeout, interval)
 
 	var lastErr error
@@ -20,6 +26,11 @@ func For(msg string, timeout, interval time.Duration, f func() (bool, error)) er
 				return fmt.Errorf("%s: time limit exceeded", msg)
 			}
 			return fmt.Errorf("%s: time limit exceeded: last error: %w", msg, lastErr)
+		case <-ctx.Done():
+			if lastErr == nil {
+				return fmt.Errorf("%s: %w", msg, ctx.Err())
+			}
+			return fmt.Errorf("%s: %w: last error: %w", msg, ctx.Err(), lastErr)
 		default:
 		}
 
@@ -31,6 +42,9 @@ func For(msg string, timeout, interval time.Duration, f func() (bool, error)) er
 			lastErr = err
 		}
 
-		time.Sleep(interval)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+		}
 	}
 }
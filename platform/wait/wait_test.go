@@ -1,6 +1,8 @@
 package wait
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -24,3 +26,26 @@ func TestForTimeout(t *testing.T) {
 		t.Logf("%v", err)
 	}
 }
+
+func TestForWithContext_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan error)
+	go func() {
+		c <- ForWithContext(ctx, "", 10*time.Second, 1*time.Second, func() (bool, error) {
+			return false, nil
+		})
+	}()
+
+	cancel()
+
+	timeout := time.After(3 * time.Second)
+	select {
+	case <-timeout:
+		t.Fatal("context cancellation not honored")
+	case err := <-c:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context canceled error; got %v", err)
+		}
+	}
+}
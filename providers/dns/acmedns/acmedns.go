@@ -37,7 +37,10 @@ const (
 	EnvStorageBaseURL = envNamespace + "STORAGE_BASE_URL"
 )
 
-var _ challenge.Provider = (*DNSProvider)(nil)
+var (
+	_ challenge.Provider            = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -163,9 +166,12 @@ func (e ErrCNAMERequired) Error() string {
 // If there is not an account for the given domain present in the DNSProvider storage
 // one will be created and registered with the ACME DNS server and an ErrCNAMERequired error is returned.
 // This will halt issuance and indicate to the user that a one-time manual setup is required for the domain.
-func (d *DNSProvider) Present(domain, _, keyAuth string) error {
-	ctx := context.Background()
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
 
+// PresentWithContext is the context-aware counterpart of Present.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, _, keyAuth string) error {
 	// Compute the challenge response FQDN and TXT value for the domain based on the keyAuth.
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
@@ -196,6 +202,11 @@ func (d *DNSProvider) CleanUp(_, _, _ string) error {
 	return nil
 }
 
+// CleanUpWithContext is the context-aware counterpart of CleanUp.
+func (d *DNSProvider) CleanUpWithContext(_ context.Context, domain, token, keyAuth string) error {
+	return d.CleanUp(domain, token, keyAuth)
+}
+
 // register creates a new ACME-DNS account for the given domain.
 // If account creation works as expected a ErrCNAMERequired error is returned describing
 // the one-time manual CNAME setup required to complete setup of the ACME-DNS hook for the domain.
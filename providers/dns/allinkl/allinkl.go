@@ -27,7 +27,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -114,6 +117,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record using the specified parameters.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -121,8 +129,6 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("allinkl: could not find zone for domain %q: %w", domain, err)
 	}
 
-	ctx := context.Background()
-
 	credential, err := d.identifier.Authentication(ctx, 60, true)
 	if err != nil {
 		return fmt.Errorf("allinkl: %w", err)
@@ -156,9 +162,12 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
 
-	ctx := context.Background()
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	credential, err := d.identifier.Authentication(ctx, 60, true)
 	if err != nil {
@@ -29,7 +29,10 @@ const (
 
 const minTTL = 600
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -110,6 +113,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -137,7 +145,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		},
 	}
 
-	newRecord, err := d.client.CreateRecord(context.Background(), authZone, record)
+	newRecord, err := d.client.CreateRecord(ctx, authZone, record)
 	if err != nil {
 		return fmt.Errorf("arvancloud: failed to add TXT record: fqdn=%s: %w", info.EffectiveFQDN, err)
 	}
@@ -151,6 +159,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -168,7 +181,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("arvancloud: unknown record ID for '%s' '%s'", info.EffectiveFQDN, token)
 	}
 
-	if err := d.client.DeleteRecord(context.Background(), authZone, recordID); err != nil {
+	if err := d.client.DeleteRecord(ctx, authZone, recordID); err != nil {
 		return fmt.Errorf("arvancloud: failed to delete TXT record: id=%s: %w", recordID, err)
 	}
 
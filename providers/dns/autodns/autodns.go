@@ -30,7 +30,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -116,6 +119,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	records := []*internal.ResourceRecord{{
@@ -125,7 +133,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Value: info.Value,
 	}}
 
-	_, err := d.client.AddTxtRecords(context.Background(), info.EffectiveFQDN, records)
+	_, err := d.client.AddTxtRecords(ctx, info.EffectiveFQDN, records)
 	if err != nil {
 		return fmt.Errorf("autodns: %w", err)
 	}
@@ -135,6 +143,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record previously created.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record previously created.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	records := []*internal.ResourceRecord{{
@@ -144,7 +157,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		Value: info.Value,
 	}}
 
-	if err := d.client.RemoveTXTRecords(context.Background(), info.EffectiveFQDN, records); err != nil {
+	if err := d.client.RemoveTXTRecords(ctx, info.EffectiveFQDN, records); err != nil {
 		return fmt.Errorf("autodns: %w", err)
 	}
 
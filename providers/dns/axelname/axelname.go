@@ -92,6 +92,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record using the specified parameters.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -110,7 +115,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Value: info.Value,
 	}
 
-	err = d.client.AddRecord(context.Background(), dns01.UnFqdn(authZone), record)
+	err = d.client.AddRecord(ctx, dns01.UnFqdn(authZone), record)
 	if err != nil {
 		return fmt.Errorf("axelname: add record: %w", err)
 	}
@@ -120,8 +125,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
 
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -108,9 +108,14 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record using the specified parameters.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	ctxAuth := authContext(context.Background(), d.config.PersonalToken)
+	ctxAuth := authContext(ctx, d.config.PersonalToken)
 
 	zone, err := d.findZone(ctxAuth, info.EffectiveFQDN)
 	if err != nil {
@@ -167,9 +172,14 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	ctxAuth := authContext(context.Background(), d.config.PersonalToken)
+	ctxAuth := authContext(ctx, d.config.PersonalToken)
 
 	zone, err := d.findZone(ctxAuth, info.EffectiveFQDN)
 	if err != nil {
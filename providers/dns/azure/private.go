@@ -27,7 +27,11 @@ func (d *dnsProviderPrivate) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *dnsProviderPrivate) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *dnsProviderPrivate) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
@@ -86,7 +90,11 @@ func (d *dnsProviderPrivate) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *dnsProviderPrivate) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *dnsProviderPrivate) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZoneID(ctx, info.EffectiveFQDN)
@@ -17,7 +17,10 @@ import (
 	"github.com/go-acme/lego/v4/providers/dns/internal/ptr"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProviderPrivate)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProviderPrivate)(nil)
+	_ challenge.ProviderWithContext = (*DNSProviderPrivate)(nil)
+)
 
 // DNSProviderPrivate implements the challenge.Provider interface for Azure Private Zone DNS.
 type DNSProviderPrivate struct {
@@ -47,8 +50,12 @@ func (d *DNSProviderPrivate) Timeout() (timeout, interval time.Duration) {
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
-func (d *DNSProviderPrivate) Present(domain, _, keyAuth string) error {
-	ctx := context.Background()
+func (d *DNSProviderPrivate) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProviderPrivate) PresentWithContext(ctx context.Context, domain, _, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
@@ -100,8 +107,12 @@ func (d *DNSProviderPrivate) Present(domain, _, keyAuth string) error {
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
-func (d *DNSProviderPrivate) CleanUp(domain, _, keyAuth string) error {
-	ctx := context.Background()
+func (d *DNSProviderPrivate) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProviderPrivate) CleanUpWithContext(ctx context.Context, domain, _, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
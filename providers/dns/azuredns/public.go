@@ -17,7 +17,10 @@ import (
 	"github.com/go-acme/lego/v4/providers/dns/internal/ptr"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProviderPublic)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProviderPublic)(nil)
+	_ challenge.ProviderWithContext = (*DNSProviderPublic)(nil)
+)
 
 // DNSProviderPublic implements the challenge.Provider interface for Azure Public Zone DNS.
 type DNSProviderPublic struct {
@@ -47,8 +50,12 @@ func (d *DNSProviderPublic) Timeout() (timeout, interval time.Duration) {
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
-func (d *DNSProviderPublic) Present(domain, _, keyAuth string) error {
-	ctx := context.Background()
+func (d *DNSProviderPublic) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProviderPublic) PresentWithContext(ctx context.Context, domain, _, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
@@ -99,8 +106,12 @@ func (d *DNSProviderPublic) Present(domain, _, keyAuth string) error {
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
-func (d *DNSProviderPublic) CleanUp(domain, _, keyAuth string) error {
-	ctx := context.Background()
+func (d *DNSProviderPublic) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProviderPublic) CleanUpWithContext(ctx context.Context, domain, _, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.getHostedZone(info.EffectiveFQDN)
@@ -31,7 +31,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -126,6 +129,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domainName, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domainName, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record using the specified parameters.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domainName, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domainName, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -133,8 +141,6 @@ func (d *DNSProvider) Present(domainName, token, keyAuth string) error {
 		return fmt.Errorf("dnsmadeeasy: could not find zone for domain %q: %w", domainName, err)
 	}
 
-	ctx := context.Background()
-
 	// fetch the domain details
 	domain, err := d.client.GetDomain(ctx, authZone)
 	if err != nil {
@@ -154,6 +160,11 @@ func (d *DNSProvider) Present(domainName, token, keyAuth string) error {
 
 // CleanUp removes the TXT records matching the specified parameters.
 func (d *DNSProvider) CleanUp(domainName, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domainName, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT records matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domainName, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domainName, keyAuth)
 
 	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -161,8 +172,6 @@ func (d *DNSProvider) CleanUp(domainName, token, keyAuth string) error {
 		return fmt.Errorf("dnsmadeeasy: could not find zone for domain %q: %w", domainName, err)
 	}
 
-	ctx := context.Background()
-
 	// fetch the domain details
 	domain, err := d.client.GetDomain(ctx, authZone)
 	if err != nil {
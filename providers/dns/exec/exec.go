@@ -28,7 +28,11 @@ const (
 	EnvSequenceInterval   = envNamespace + "SEQUENCE_INTERVAL"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ dns01.RecordProvider          = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config Provider configuration.
 type Config struct {
@@ -80,7 +84,12 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	err := d.run(context.Background(), "present", domain, token, keyAuth)
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
+	err := d.run(ctx, "present", domain, token, keyAuth)
 	if err != nil {
 		return fmt.Errorf("exec: %w", err)
 	}
@@ -90,7 +99,32 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	err := d.run(context.Background(), "cleanup", domain, token, keyAuth)
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
+	err := d.run(ctx, "cleanup", domain, token, keyAuth)
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+// PresentRecord creates a TXT record from its FQDN and its value.
+func (d *DNSProvider) PresentRecord(ctx context.Context, _ string, info dns01.ChallengeInfo) error {
+	err := d.runRecord(ctx, "present", info)
+	if err != nil {
+		return fmt.Errorf("exec: %w", err)
+	}
+
+	return nil
+}
+
+// CleanUpRecord removes the TXT record matching the specified FQDN and value.
+func (d *DNSProvider) CleanUpRecord(ctx context.Context, _ string, info dns01.ChallengeInfo) error {
+	err := d.runRecord(ctx, "cleanup", info)
 	if err != nil {
 		return fmt.Errorf("exec: %w", err)
 	}
@@ -111,14 +145,25 @@ func (d *DNSProvider) Sequential() time.Duration {
 }
 
 func (d *DNSProvider) run(ctx context.Context, command, domain, token, keyAuth string) error {
-	var args []string
 	if d.config.Mode == "RAW" {
-		args = []string{command, "--", domain, token, keyAuth}
-	} else {
-		info := dns01.GetChallengeInfo(domain, keyAuth)
-		args = []string{command, info.EffectiveFQDN, info.Value}
+		return d.execute(ctx, command, "--", domain, token, keyAuth)
 	}
 
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	return d.execute(ctx, command, info.EffectiveFQDN, info.Value)
+}
+
+func (d *DNSProvider) runRecord(ctx context.Context, command string, info dns01.ChallengeInfo) error {
+	if d.config.Mode == "RAW" {
+		// The program only receives the key authorization in RAW mode, and the value of the record cannot be derived from it.
+		return errors.New("the RAW mode does not support records other than the dns-01 challenge records")
+	}
+
+	return d.execute(ctx, command, info.EffectiveFQDN, info.Value)
+}
+
+func (d *DNSProvider) execute(ctx context.Context, args ...string) error {
 	cmd := exec.CommandContext(ctx, d.config.Program, args...)
 
 	stdout, err := cmd.StdoutPipe()
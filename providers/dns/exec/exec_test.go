@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/go-acme/lego/v4/challenge/dns01"
 	"github.com/go-acme/lego/v4/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -157,3 +158,72 @@ func TestDNSProvider_CleanUp(t *testing.T) {
 		})
 	}
 }
+
+func TestDNSProvider_PresentRecord(t *testing.T) {
+	backupLogger := log.Logger
+	defer func() {
+		log.Logger = backupLogger
+	}()
+
+	logRecorder := &LogRecorder{}
+	log.Logger = logRecorder
+
+	type expected struct {
+		args  string
+		error bool
+	}
+
+	testCases := []struct {
+		desc     string
+		config   *Config
+		expected expected
+	}{
+		{
+			desc: "Standard mode",
+			config: &Config{
+				Program: "echo",
+				Mode:    "",
+			},
+			expected: expected{
+				args: "present _account._acme-challenge.domain. value",
+			},
+		},
+		{
+			desc: "Raw mode",
+			config: &Config{
+				Program: "echo",
+				Mode:    "RAW",
+			},
+			expected: expected{error: true},
+		},
+	}
+
+	var message string
+	logRecorder.On("Println", mock.Anything).Run(func(args mock.Arguments) {
+		message = args.String(0)
+		fmt.Fprintln(os.Stdout, "XXX", message)
+	})
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			message = ""
+
+			provider, err := NewDNSProviderConfig(test.config)
+			require.NoError(t, err)
+
+			info := dns01.ChallengeInfo{
+				FQDN:          "_account._acme-challenge.domain.",
+				EffectiveFQDN: "_account._acme-challenge.domain.",
+				Value:         "value",
+			}
+
+			err = provider.PresentRecord(t.Context(), "domain", info)
+			if test.expected.error {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected.args, strings.TrimSpace(message))
+			}
+		})
+	}
+}
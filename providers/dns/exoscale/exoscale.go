@@ -31,7 +31,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -104,7 +107,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, recordName, err := d.findZoneAndRecordName(info.EffectiveFQDN)
@@ -112,7 +119,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("exoscale: %w", err)
 	}
 
-	zone, err := d.findExistingZone(zoneName)
+	zone, err := d.findExistingZone(ctx, zoneName)
 	if err != nil {
 		return fmt.Errorf("exoscale: %w", err)
 	}
@@ -142,7 +149,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
-	ctx := context.Background()
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, recordName, err := d.findZoneAndRecordName(info.EffectiveFQDN)
@@ -150,7 +161,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("exoscale: %w", err)
 	}
 
-	zone, err := d.findExistingZone(zoneName)
+	zone, err := d.findExistingZone(ctx, zoneName)
 	if err != nil {
 		return fmt.Errorf("exoscale: %w", err)
 	}
@@ -158,7 +169,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("exoscale: zone %q not found", zoneName)
 	}
 
-	recordID, err := d.findExistingRecordID(zone.ID, recordName, info.Value)
+	recordID, err := d.findExistingRecordID(ctx, zone.ID, recordName, info.Value)
 	if err != nil {
 		return err
 	}
@@ -188,8 +199,7 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // findExistingZone Query Exoscale to find an existing zone for this name.
 // Returns nil result if no zone could be found.
-func (d *DNSProvider) findExistingZone(zoneName string) (*egoscale.DNSDomain, error) {
-	ctx := context.Background()
+func (d *DNSProvider) findExistingZone(ctx context.Context, zoneName string) (*egoscale.DNSDomain, error) {
 
 	zones, err := d.client.ListDNSDomains(ctx)
 	if err != nil {
@@ -207,8 +217,7 @@ func (d *DNSProvider) findExistingZone(zoneName string) (*egoscale.DNSDomain, er
 
 // findExistingRecordID Query Exoscale to find an existing record for this name.
 // Returns empty result if no record could be found.
-func (d *DNSProvider) findExistingRecordID(zoneID egoscale.UUID, recordName, value string) (egoscale.UUID, error) {
-	ctx := context.Background()
+func (d *DNSProvider) findExistingRecordID(ctx context.Context, zoneID egoscale.UUID, recordName, value string) (egoscale.UUID, error) {
 
 	records, err := d.client.ListDNSDomainRecords(ctx, zoneID)
 	if err != nil {
@@ -31,7 +31,10 @@ const (
 	defaultPollingInterval    = 20 * time.Second
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config for DNSProvider.
 type Config struct {
@@ -96,10 +99,13 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 }
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
-func (d *DNSProvider) Present(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
 
-	ctx := context.Background()
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, _, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.guessZone(ctx, info.EffectiveFQDN)
 	if err != nil {
@@ -115,10 +121,13 @@ func (d *DNSProvider) Present(domain, _, keyAuth string) error {
 }
 
 // CleanUp removes the record matching the specified parameters.
-func (d *DNSProvider) CleanUp(domain, _, keyAuth string) error {
-	info := dns01.GetChallengeInfo(domain, keyAuth)
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
 
-	ctx := context.Background()
+// CleanUpWithContext removes the record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, _, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := d.guessZone(ctx, info.EffectiveFQDN)
 	if err != nil {
@@ -27,7 +27,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -102,6 +105,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record using the specified parameters.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	query := internal.RecordQuery{
@@ -111,7 +119,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		TTL:            d.config.TTL,
 	}
 
-	err := d.client.AddRecord(context.Background(), query)
+	err := d.client.AddRecord(ctx, query)
 	if err != nil {
 		return fmt.Errorf("internetbs: %w", err)
 	}
@@ -121,6 +129,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	query := internal.RecordQuery{
@@ -130,7 +143,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		TTL:            d.config.TTL,
 	}
 
-	err := d.client.RemoveRecord(context.Background(), query)
+	err := d.client.RemoveRecord(ctx, query)
 	if err != nil {
 		return fmt.Errorf("internetbs: %w", err)
 	}
@@ -27,7 +27,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -90,6 +93,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	sub, root, err := splitDomain(dns01.UnFqdn(info.EffectiveFQDN))
@@ -97,7 +105,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("ipv64: %w", err)
 	}
 
-	err = d.client.AddRecord(context.Background(), root, sub, "TXT", info.Value)
+	err = d.client.AddRecord(ctx, root, sub, "TXT", info.Value)
 	if err != nil {
 		return fmt.Errorf("ipv64: %w", err)
 	}
@@ -107,6 +115,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp clears IPv64 TXT record.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext clears IPv64 TXT record.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	sub, root, err := splitDomain(dns01.UnFqdn(info.EffectiveFQDN))
@@ -114,7 +127,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("ipv64: %w", err)
 	}
 
-	err = d.client.DeleteRecord(context.Background(), root, sub, "TXT", info.Value)
+	err = d.client.DeleteRecord(ctx, root, sub, "TXT", info.Value)
 	if err != nil {
 		return fmt.Errorf("ipv64: %w", err)
 	}
@@ -13,7 +13,10 @@ import (
 	"github.com/go-acme/lego/v4/providers/dns/joker/internal/dmapi"
 )
 
-var _ challenge.ProviderTimeout = (*dmapiProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*dmapiProvider)(nil)
+	_ challenge.ProviderWithContext = (*dmapiProvider)(nil)
+)
 
 // dmapiProvider implements the challenge.Provider interface.
 type dmapiProvider struct {
@@ -77,6 +80,11 @@ func (d *dmapiProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *dmapiProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record using the specified parameters.
+func (d *dmapiProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -93,7 +101,7 @@ func (d *dmapiProvider) Present(domain, token, keyAuth string) error {
 		log.Infof("[%s] joker: adding TXT record %q to zone %q with value %q", domain, subDomain, zone, info.Value)
 	}
 
-	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
+	ctx, err = d.client.CreateAuthenticatedContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -115,6 +123,11 @@ func (d *dmapiProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *dmapiProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *dmapiProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
@@ -131,7 +144,7 @@ func (d *dmapiProvider) CleanUp(domain, token, keyAuth string) error {
 		log.Infof("[%s] joker: removing entry %q from zone %q", domain, subDomain, zone)
 	}
 
-	ctx, err := d.client.CreateAuthenticatedContext(context.Background())
+	ctx, err = d.client.CreateAuthenticatedContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -35,7 +35,10 @@ const (
 	dnsUpdateFudgeSecs = 120
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -130,9 +133,14 @@ func (d *DNSProvider) Timeout() (time.Duration, time.Duration) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record using the specified parameters.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	zone, err := d.getHostedZoneInfo(info.EffectiveFQDN)
+	zone, err := d.getHostedZoneInfo(ctx, info.EffectiveFQDN)
 	if err != nil {
 		return err
 	}
@@ -144,22 +152,27 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Type:   linodego.RecordTypeTXT,
 	}
 
-	_, err = d.client.CreateDomainRecord(context.Background(), zone.domainID, createOpts)
+	_, err = d.client.CreateDomainRecord(ctx, zone.domainID, createOpts)
 	return err
 }
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	zone, err := d.getHostedZoneInfo(info.EffectiveFQDN)
+	zone, err := d.getHostedZoneInfo(ctx, info.EffectiveFQDN)
 	if err != nil {
 		return err
 	}
 
 	// Get all TXT records for the specified domain.
 	listOpts := linodego.NewListOptions(0, `{"type":"TXT"}`)
-	resources, err := d.client.ListDomainRecords(context.Background(), zone.domainID, listOpts)
+	resources, err := d.client.ListDomainRecords(ctx, zone.domainID, listOpts)
 	if err != nil {
 		return err
 	}
@@ -168,7 +181,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	for _, resource := range resources {
 		if (resource.Name == dns01.UnFqdn(info.EffectiveFQDN) || resource.Name == zone.resourceName) &&
 			resource.Target == info.Value {
-			if err := d.client.DeleteDomainRecord(context.Background(), zone.domainID, resource.ID); err != nil {
+			if err := d.client.DeleteDomainRecord(ctx, zone.domainID, resource.ID); err != nil {
 				return err
 			}
 		}
@@ -177,7 +190,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 	return nil
 }
 
-func (d *DNSProvider) getHostedZoneInfo(fqdn string) (*hostedZoneInfo, error) {
+func (d *DNSProvider) getHostedZoneInfo(ctx context.Context, fqdn string) (*hostedZoneInfo, error) {
 	// Lookup the zone that handles the specified FQDN.
 	authZone, err := dns01.FindZoneByFqdn(fqdn)
 	if err != nil {
@@ -191,7 +204,7 @@ func (d *DNSProvider) getHostedZoneInfo(fqdn string) (*hostedZoneInfo, error) {
 	}
 
 	listOpts := linodego.NewListOptions(0, string(filter))
-	domains, err := d.client.ListDomains(context.Background(), listOpts)
+	domains, err := d.client.ListDomains(ctx, listOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +29,10 @@ const (
 
 const txtType = "TXT"
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -106,9 +109,14 @@ func (d *DNSProvider) Sequential() time.Duration {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	domains, err := d.client.ListDomains(context.Background())
+	domains, err := d.client.ListDomains(ctx)
 	if err != nil {
 		return fmt.Errorf("mijnhost: list domains: %w", err)
 	}
@@ -118,7 +126,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		return fmt.Errorf("mijnhost: find domain: %w", err)
 	}
 
-	records, err := d.client.GetRecords(context.Background(), dom.Domain)
+	records, err := d.client.GetRecords(ctx, dom.Domain)
 	if err != nil {
 		return fmt.Errorf("mijnhost: get records: %w", err)
 	}
@@ -143,7 +151,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 	cleanedRecords = append(cleanedRecords, record)
 
-	err = d.client.UpdateRecords(context.Background(), dom.Domain, cleanedRecords)
+	err = d.client.UpdateRecords(ctx, dom.Domain, cleanedRecords)
 	if err != nil {
 		return fmt.Errorf("mijnhost: update records: %w", err)
 	}
@@ -153,9 +161,14 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
-	domains, err := d.client.ListDomains(context.Background())
+	domains, err := d.client.ListDomains(ctx)
 	if err != nil {
 		return fmt.Errorf("mijnhost: list domains: %w", err)
 	}
@@ -165,7 +178,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("mijnhost: find domain: %w", err)
 	}
 
-	records, err := d.client.GetRecords(context.Background(), dom.Domain)
+	records, err := d.client.GetRecords(ctx, dom.Domain)
 	if err != nil {
 		return fmt.Errorf("mijnhost: get records: %w", err)
 	}
@@ -174,7 +187,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return record.Type == txtType && record.Value == info.Value
 	})
 
-	err = d.client.UpdateRecords(context.Background(), dom.Domain, cleanedRecords)
+	err = d.client.UpdateRecords(ctx, dom.Domain, cleanedRecords)
 	if err != nil {
 		return fmt.Errorf("mijnhost: update records: %w", err)
 	}
@@ -31,7 +31,10 @@ const (
 
 const minTTL = 300
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -115,6 +118,11 @@ func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
 
 // Present creates a TXT record to fulfill the dns-01 challenge.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	zoneName, hostName, err := splitDomain(info.EffectiveFQDN)
@@ -129,8 +137,6 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		TTL:     strconv.Itoa(d.config.TTL),
 	}
 
-	ctx := context.Background()
-
 	recordID, err := d.client.CreateRecord(ctx, dns01.UnFqdn(zoneName), record)
 	if err != nil {
 		return fmt.Errorf("porkbun: failed to create record: %w", err)
@@ -145,6 +151,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	// gets the record's unique ID from when we created it
@@ -160,8 +171,6 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		return fmt.Errorf("porkbun: %w", err)
 	}
 
-	ctx := context.Background()
-
 	err = d.client.DeleteRecord(ctx, dns01.UnFqdn(zoneName), recordID)
 	if err != nil {
 		return fmt.Errorf("porkbun: failed to delete record: %w", err)
@@ -64,10 +64,10 @@ func TestDNSProvider_addAndCleanupRecords(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("addTXTRecord", func(t *testing.T) {
-		err = p.addTXTRecord("test.example.com.", "dummyValue", 10)
+		err = p.addTXTRecord(t.Context(), "test.example.com.", "dummyValue", 10)
 		require.NoError(t, err)
 
-		updZone, e := p.getHostedZone("test.example.com.")
+		updZone, e := p.getHostedZone(t.Context(), "test.example.com.")
 		require.NoError(t, e)
 		require.NotNil(t, updZone)
 
@@ -75,10 +75,10 @@ func TestDNSProvider_addAndCleanupRecords(t *testing.T) {
 	})
 
 	t.Run("cleanupTXTRecord", func(t *testing.T) {
-		err = p.cleanupTXTRecord("test.example.com.", "dummyValue")
+		err = p.cleanupTXTRecord(t.Context(), "test.example.com.", "dummyValue")
 		require.NoError(t, err)
 
-		updZone, e := p.getHostedZone("test.example.com.")
+		updZone, e := p.getHostedZone(t.Context(), "test.example.com.")
 		require.NoError(t, e)
 		require.NotNil(t, updZone)
 
@@ -110,7 +110,7 @@ func TestDNSProvider_concurrentAddAndCleanupRecords(t *testing.T) {
 
 		for i, p := range providers {
 			go func(j int, client *DNSProvider) {
-				err := client.addTXTRecord(fmt.Sprintf("test%d.example.com.", j), "dummyValue", 10)
+				err := client.addTXTRecord(t.Context(), fmt.Sprintf("test%d.example.com.", j), "dummyValue", 10)
 				require.NoError(t, err)
 				wg.Done()
 			}(i, p)
@@ -118,7 +118,7 @@ func TestDNSProvider_concurrentAddAndCleanupRecords(t *testing.T) {
 
 		wg.Wait()
 
-		updZone, err := providers[0].getHostedZone("example.com.")
+		updZone, err := providers[0].getHostedZone(t.Context(), "example.com.")
 		require.NoError(t, err)
 		require.NotNil(t, updZone)
 
@@ -130,7 +130,7 @@ func TestDNSProvider_concurrentAddAndCleanupRecords(t *testing.T) {
 
 		for i, p := range providers {
 			go func(i int, client *DNSProvider) {
-				err := client.cleanupTXTRecord(fmt.Sprintf("test%d.example.com.", i), "dummyValue")
+				err := client.cleanupTXTRecord(t.Context(), fmt.Sprintf("test%d.example.com.", i), "dummyValue")
 				require.NoError(t, err)
 				wg.Done()
 			}(i, p)
@@ -138,7 +138,7 @@ func TestDNSProvider_concurrentAddAndCleanupRecords(t *testing.T) {
 
 		wg.Wait()
 
-		updZone, err := providers[0].getHostedZone("example.com.")
+		updZone, err := providers[0].getHostedZone(t.Context(), "example.com.")
 		require.NoError(t, err)
 		require.NotNil(t, updZone)
 
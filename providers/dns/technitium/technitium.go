@@ -27,7 +27,10 @@ const (
 	EnvHTTPTimeout        = envNamespace + "HTTP_TIMEOUT"
 )
 
-var _ challenge.ProviderTimeout = (*DNSProvider)(nil)
+var (
+	_ challenge.ProviderTimeout     = (*DNSProvider)(nil)
+	_ challenge.ProviderWithContext = (*DNSProvider)(nil)
+)
 
 // Config is used to configure the creation of the DNSProvider.
 type Config struct {
@@ -95,6 +98,11 @@ func NewDNSProviderConfig(config *Config) (*DNSProvider, error) {
 
 // Present creates a TXT record using the specified parameters.
 func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	return d.PresentWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// PresentWithContext creates a TXT record using the specified parameters.
+func (d *DNSProvider) PresentWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	record := internal.Record{
@@ -103,7 +111,7 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 		Text:   info.Value,
 	}
 
-	_, err := d.client.AddRecord(context.Background(), record)
+	_, err := d.client.AddRecord(ctx, record)
 	if err != nil {
 		return fmt.Errorf("technitium: add record: %w", err)
 	}
@@ -113,6 +121,11 @@ func (d *DNSProvider) Present(domain, token, keyAuth string) error {
 
 // CleanUp removes the TXT record matching the specified parameters.
 func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	return d.CleanUpWithContext(context.Background(), domain, token, keyAuth)
+}
+
+// CleanUpWithContext removes the TXT record matching the specified parameters.
+func (d *DNSProvider) CleanUpWithContext(ctx context.Context, domain, token, keyAuth string) error {
 	info := dns01.GetChallengeInfo(domain, keyAuth)
 
 	record := internal.Record{
@@ -121,7 +134,7 @@ func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
 		Text:   info.Value,
 	}
 
-	err := d.client.DeleteRecord(context.Background(), record)
+	err := d.client.DeleteRecord(ctx, record)
 	if err != nil {
 		return fmt.Errorf("technitium: delete record: %w", err)
 	}
@@ -1,6 +1,7 @@
 package registration
 
 import (
+	"crypto"
 	"errors"
 	"net/http"
 
@@ -154,6 +155,56 @@ func (r *Registrar) DeleteRegistration() error {
 	return r.core.Accounts.Deactivate(r.user.GetRegistration().URI)
 }
 
+// ChangeKey rolls over the account key: the account will be associated with the new key.
+//
+// On success, the client will use the new key to sign all the next requests,
+// the caller is responsible for persisting it in place of the previous one.
+func (r *Registrar) ChangeKey(newKey crypto.PrivateKey) error {
+	if r == nil || r.user == nil || r.user.GetRegistration() == nil {
+		return errors.New("acme: cannot change the key of a nil client or user")
+	}
+
+	log.Infof("acme: Changing key for account %s", r.user.GetRegistration().URI)
+
+	return r.core.Accounts.KeyChange(newKey)
+}
+
+// ListOrders returns the orders of the account.
+//
+// If fetch is false, only the order URLs (Location) are populated,
+// otherwise each order is retrieved from the ACME server.
+func (r *Registrar) ListOrders(fetch bool) ([]acme.ExtendedOrder, error) {
+	if r == nil || r.user == nil || r.user.GetRegistration() == nil {
+		return nil, errors.New("acme: cannot list the orders of a nil client or user")
+	}
+
+	// The orders URL is not always known: the registration may have been stored before the server provided it.
+	account, err := r.core.Accounts.Get(r.user.GetRegistration().URI)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Orders == "" {
+		return nil, errors.New("acme: the server does not provide the list of orders of the account")
+	}
+
+	if fetch {
+		return r.core.Accounts.GetOrders(account.Orders)
+	}
+
+	orderURLs, err := r.core.Accounts.ListOrders(account.Orders)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]acme.ExtendedOrder, 0, len(orderURLs))
+	for _, orderURL := range orderURLs {
+		orders = append(orders, acme.ExtendedOrder{Location: orderURL})
+	}
+
+	return orders, nil
+}
+
 // ResolveAccountByKey will attempt to look up an account using the given account key
 // and return its registration resource.
 func (r *Registrar) ResolveAccountByKey() (*Resource, error) {